@@ -0,0 +1,13 @@
+// Command unicheck checks declarations of uni modules, it can also be run by go vet:
+//
+//	go vet -vettool=$(which unicheck) ./...
+package main
+
+import (
+	"github.com/jison/uni/analysis/unicheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(unicheck.Analyzer)
+}
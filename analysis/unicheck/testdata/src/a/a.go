@@ -0,0 +1,59 @@
+package a
+
+import (
+	"io"
+
+	"github.com/jison/uni"
+	"github.com/jison/uni/core/model"
+)
+
+type Logger interface {
+	Log(msg string)
+}
+
+type stdLogger struct{}
+
+func (l *stdLogger) Log(msg string) {}
+
+type Server struct {
+	Logger Logger
+	Name   string
+}
+
+func NewLogger() (*stdLogger, error) { return &stdLogger{}, nil }
+
+func NewPair(l Logger) (*Server, *stdLogger) { return nil, nil }
+
+var _ = model.Func(NewLogger, model.Return(0, model.As((*Logger)(nil))))
+
+var _ = model.Func(NewLogger, model.Return(1)) // want `return index 1 is the error result of func\(\) \(\*a.stdLogger, error\)`
+
+var _ = uni.Func(NewLogger, uni.Return(2)) // want `return index 2 is out of range, func\(\) \(\*a.stdLogger, error\) has 2 results`
+
+var _ = uni.Func(NewPair, uni.Return(2)) // want `return index 2 is out of range`
+
+var _ = uni.Func(NewPair, uni.Param(0), uni.Param(1)) // want `parameter index 1 is out of range, func\(l a.Logger\) \(\*a.Server, \*a.stdLogger\) has 1 parameters`
+
+var _ = uni.Func(NewPair, uni.Return(0, uni.As((*io.Closer)(nil)))) // want `\*a.Server does not implement io.Closer`
+
+var _ = uni.Struct(&Server{}, uni.Field("Logger"), uni.Field("Nmae")) // want `a.Server has no field Nmae`
+
+var _ = model.StructConsumer(Server{}, model.Field("Loger")) // want `a.Server has no field Loger`
+
+var _ = uni.Struct(&Server{}, uni.Field("Unknown")) // want `a.Server has no field Unknown`
+
+var _ = uni.Struct(1) // want `int is not a struct or a pointer of struct`
+
+var _ = uni.Value(&stdLogger{}, uni.As((*Logger)(nil)))
+
+var _ = uni.Value(stdLogger{}, uni.As((*Logger)(nil))) // want `a.stdLogger does not implement a.Logger`
+
+var _ = uni.Value(&stdLogger{}, uni.As(Logger(nil))) // want `nil of Logger has no type at runtime, use \(\*Logger\)\(nil\)`
+
+var _ = uni.As(&stdLogger{}) // want `\*a.stdLogger is not an interface, use \(\*I\)\(nil\) for interface I`
+
+func dynamic(opts ...model.Option) {
+	var l Logger
+	_ = uni.Func(NewLogger, opts...)
+	_ = uni.As(l)
+}
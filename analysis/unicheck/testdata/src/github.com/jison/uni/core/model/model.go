@@ -0,0 +1,28 @@
+// Package model is a stub of github.com/jison/uni/core/model for tests of unicheck
+package model
+
+type TypeVal interface{}
+
+type Option interface{}
+
+type Builder struct{}
+
+func Func(function interface{}, opts ...Option) Builder { return Builder{} }
+
+func Struct(t TypeVal, opts ...Option) Builder { return Builder{} }
+
+func Value(val interface{}, opts ...Option) Builder { return Builder{} }
+
+func FuncConsumer(val interface{}, opts ...Option) Builder { return Builder{} }
+
+func StructConsumer(t TypeVal, opts ...Option) Builder { return Builder{} }
+
+func Field(name string, opts ...Option) Option { return nil }
+
+func Param(index int, opts ...Option) Option { return nil }
+
+func Return(index int, opts ...Option) Option { return nil }
+
+func As(ifs ...TypeVal) Option { return nil }
+
+func Name(name string) Option { return nil }
@@ -0,0 +1,14 @@
+// Package uni is a stub of github.com/jison/uni for tests of unicheck
+package uni
+
+import "github.com/jison/uni/core/model"
+
+var Func = model.Func
+var Struct = model.Struct
+var Value = model.Value
+var Field = model.Field
+var Param = model.Param
+var Return = model.Return
+var As = model.As
+var BuildFunc = model.FuncConsumer
+var BuildStruct = model.StructConsumer
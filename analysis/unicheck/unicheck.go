@@ -0,0 +1,391 @@
+// Package unicheck defines an Analyzer that reports mistakes in declarations of uni modules,
+// which are otherwise only reported by Validate at runtime
+package unicheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `check declarations of uni modules
+
+The unicheck analyzer reports these mistakes in calls of uni.Func, uni.Struct,
+uni.Value and their options:
+  - the index of uni.Param or uni.Return is out of range, or uni.Return is
+    at an error result
+  - uni.Field names a field the struct does not have
+  - the type in uni.As is not an interface, or it is not implemented by the
+    component`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "unicheck",
+	Doc:      doc,
+	URL:      "https://github.com/jison/uni/tree/main/analysis/unicheck",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// packages exporting functions of the model, directly or as variables
+var uniPackages = map[string]bool{
+	"github.com/jison/uni/core/model":  true,
+	"github.com/jison/uni":             true,
+	"github.com/jison/uni/generic/uni": true,
+}
+
+// facadeNames are names of variables in the facades which are the functions in the model
+var facadeNames = map[string]string{
+	"BuildFunc":   "FuncConsumer",
+	"BuildStruct": "StructConsumer",
+}
+
+// uniFuncOf returns the name of the function in the model called by the call, if it is
+func uniFuncOf(info *types.Info, call *ast.CallExpr) string {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return ""
+	}
+
+	obj := info.Uses[ident]
+	if obj == nil || obj.Pkg() == nil || !uniPackages[obj.Pkg().Path()] {
+		return ""
+	}
+	switch o := obj.(type) {
+	case *types.Func:
+		if sig, ok := o.Type().(*types.Signature); ok && sig.Recv() != nil {
+			return ""
+		}
+	case *types.Var:
+		if o.Parent() != o.Pkg().Scope() {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	if name, ok := facadeNames[obj.Name()]; ok {
+		return name
+	}
+	return obj.Name()
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		switch uniFuncOf(pass.TypesInfo, call) {
+		case "Func", "FuncConsumer":
+			checkFunc(pass, call)
+		case "Struct", "StructConsumer":
+			checkStruct(pass, call)
+		case "Value":
+			checkValue(pass, call)
+		case "As":
+			checkAsInterfaces(pass, call)
+		}
+	})
+	return nil, nil
+}
+
+// options returns calls of functions in the model in arguments of the call after the first one
+func options(pass *analysis.Pass, call *ast.CallExpr, name string) []*ast.CallExpr {
+	if len(call.Args) < 2 || call.Ellipsis.IsValid() {
+		return nil
+	}
+
+	var res []*ast.CallExpr
+	for _, arg := range call.Args[1:] {
+		opt, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if ok && uniFuncOf(pass.TypesInfo, opt) == name {
+			res = append(res, opt)
+		}
+	}
+	return res
+}
+
+// typeValOf returns the type of the component declared by the expression like model.TypeOf,
+// it is nil if the type is only known at runtime
+func typeValOf(pass *analysis.Pass, expr ast.Expr) types.Type {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil || types.IsInterface(t) {
+		return nil
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "reflect" {
+		return nil
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok && types.IsInterface(ptr.Elem()) {
+		return ptr.Elem()
+	}
+	return t
+}
+
+func intArg(pass *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	if len(call.Args) == 0 {
+		return 0, false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	i, exact := constant.Int64Val(tv.Value)
+	return int(i), exact
+}
+
+func stringArg(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	if len(call.Args) == 0 {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// removeArg returns the fix removing the option from arguments of the call
+func removeArg(call *ast.CallExpr, opt *ast.CallExpr) analysis.SuggestedFix {
+	for i, arg := range call.Args {
+		if ast.Unparen(arg) != opt {
+			continue
+		}
+		pos, end := call.Args[i-1].End(), arg.End()
+		return analysis.SuggestedFix{
+			Message:   "Remove the option",
+			TextEdits: []analysis.TextEdit{{Pos: pos, End: end}},
+		}
+	}
+	return analysis.SuggestedFix{}
+}
+
+func replaceArg(opt *ast.CallExpr, message string, newText string) analysis.SuggestedFix {
+	return analysis.SuggestedFix{
+		Message:   message,
+		TextEdits: []analysis.TextEdit{{Pos: opt.Args[0].Pos(), End: opt.Args[0].End(), NewText: []byte(newText)}},
+	}
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func checkFunc(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	sig, ok := pass.TypesInfo.TypeOf(call.Args[0]).(*types.Signature)
+	if !ok {
+		return
+	}
+
+	for _, opt := range options(pass, call, "Param") {
+		index, ok := intArg(pass, opt)
+		if !ok || (index >= 0 && index < sig.Params().Len()) {
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            opt.Pos(),
+			End:            opt.End(),
+			Message:        fmt.Sprintf("parameter index %d is out of range, %v has %d parameters", index, sig, sig.Params().Len()),
+			SuggestedFixes: []analysis.SuggestedFix{removeArg(call, opt)},
+		})
+	}
+
+	var valid []int
+	for i := 0; i < sig.Results().Len(); i++ {
+		if !isErrorType(sig.Results().At(i).Type()) {
+			valid = append(valid, i)
+		}
+	}
+	for _, opt := range options(pass, call, "Return") {
+		index, ok := intArg(pass, opt)
+		if !ok {
+			continue
+		}
+
+		var message string
+		if index < 0 || index >= sig.Results().Len() {
+			message = fmt.Sprintf("return index %d is out of range, %v has %d results", index, sig, sig.Results().Len())
+		} else if isErrorType(sig.Results().At(index).Type()) {
+			message = fmt.Sprintf("return index %d is the error result of %v", index, sig)
+		} else {
+			checkAsImplemented(pass, opt, sig.Results().At(index).Type())
+			continue
+		}
+
+		var fixes []analysis.SuggestedFix
+		if len(valid) == 1 {
+			fixes = append(fixes, replaceArg(opt, fmt.Sprintf("Use return index %d", valid[0]),
+				strconv.Itoa(valid[0])))
+		}
+		fixes = append(fixes, removeArg(call, opt))
+		pass.Report(analysis.Diagnostic{
+			Pos:            opt.Pos(),
+			End:            opt.End(),
+			Message:        message,
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+func checkStruct(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	t := typeValOf(pass, call.Args[0])
+	if t == nil {
+		return
+	}
+	st := t
+	if ptr, ok := st.Underlying().(*types.Pointer); ok {
+		st = ptr.Elem()
+	}
+	structType, ok := st.Underlying().(*types.Struct)
+	if !ok {
+		pass.Reportf(call.Args[0].Pos(), "%v is not a struct or a pointer of struct", t)
+		return
+	}
+
+	var fields []string
+	for i := 0; i < structType.NumFields(); i++ {
+		fields = append(fields, structType.Field(i).Name())
+	}
+	for _, opt := range options(pass, call, "Field") {
+		name, ok := stringArg(pass, opt)
+		if !ok || contains(fields, name) {
+			continue
+		}
+
+		var fixes []analysis.SuggestedFix
+		if closest, ok := closestName(name, fields); ok {
+			fixes = append(fixes, replaceArg(opt, fmt.Sprintf("Use field %v", closest), strconv.Quote(closest)))
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            opt.Args[0].Pos(),
+			End:            opt.Args[0].End(),
+			Message:        fmt.Sprintf("%v has no field %v", st, name),
+			SuggestedFixes: fixes,
+		})
+	}
+
+	if uniFuncOf(pass.TypesInfo, call) == "Struct" {
+		checkAsImplemented(pass, call, t)
+	}
+}
+
+func checkValue(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	if t := pass.TypesInfo.TypeOf(call.Args[0]); t != nil && !types.IsInterface(t) {
+		checkAsImplemented(pass, call, t)
+	}
+}
+
+// checkAsImplemented checks interfaces in As options of the call are implemented by t
+func checkAsImplemented(pass *analysis.Pass, call *ast.CallExpr, t types.Type) {
+	for _, opt := range options(pass, call, "As") {
+		for _, arg := range opt.Args {
+			iface := typeValOf(pass, arg)
+			if iface == nil || !types.IsInterface(iface) {
+				continue
+			}
+			if !types.Implements(t, iface.Underlying().(*types.Interface)) {
+				pass.Reportf(arg.Pos(), "%v does not implement %v", t, iface)
+			}
+		}
+	}
+}
+
+// checkAsInterfaces checks types in the As call are interfaces
+func checkAsInterfaces(pass *analysis.Pass, call *ast.CallExpr) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	for _, arg := range call.Args {
+		// a nil value of interface has no type at runtime
+		if conv, ok := ast.Unparen(arg).(*ast.CallExpr); ok && len(conv.Args) == 1 {
+			if tv, ok := pass.TypesInfo.Types[conv.Fun]; ok && tv.IsType() && types.IsInterface(tv.Type) &&
+				pass.TypesInfo.Types[conv.Args[0]].IsNil() {
+				typeName := types.ExprString(conv.Fun)
+				pass.Report(analysis.Diagnostic{
+					Pos:     arg.Pos(),
+					End:     arg.End(),
+					Message: fmt.Sprintf("nil of %v has no type at runtime, use (*%v)(nil)", typeName, typeName),
+					SuggestedFixes: []analysis.SuggestedFix{{
+						Message: fmt.Sprintf("Use (*%v)(nil)", typeName),
+						TextEdits: []analysis.TextEdit{{
+							Pos: arg.Pos(), End: arg.End(), NewText: []byte(fmt.Sprintf("(*%v)(nil)", typeName)),
+						}},
+					}},
+				})
+				continue
+			}
+		}
+
+		t := typeValOf(pass, arg)
+		if t != nil && !types.IsInterface(t) {
+			pass.Report(analysis.Diagnostic{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				Message: fmt.Sprintf("%v is not an interface, use (*I)(nil) for interface I", t),
+			})
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// closestName returns the name with the least edit distance to name, if the distance is small
+func closestName(name string, names []string) (string, bool) {
+	best, bestDist := "", len(name)/2+1
+	for _, n := range names {
+		if d := editDistance(name, n); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Damerau–Levenshtein distance (optimal string alignment) of a and b
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
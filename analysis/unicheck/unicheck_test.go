@@ -0,0 +1,31 @@
+package unicheck
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func Test_closestName(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  string
+		ok    bool
+	}{
+		{"Nmae", []string{"Logger", "Name"}, "Name", true},
+		{"Loger", []string{"Logger", "Name"}, "Logger", true},
+		{"Unknown", []string{"Logger", "Name"}, "", false},
+		{"Name", nil, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := closestName(tt.name, tt.names)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("closestName(%v, %v) = %v, %v, want %v, %v", tt.name, tt.names, got, ok, tt.want, tt.ok)
+		}
+	}
+}
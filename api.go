@@ -3,13 +3,23 @@ package uni
 import (
 	"github.com/jison/uni/core"
 	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
 )
 
 type Container = core.Container
+type CachePolicy = model.CachePolicy
+type Resolver = core.Resolver
+type Error = errors.StructError
 
 var IgnoreMissing = core.IgnoreMissing
 var IgnoreUncertain = core.IgnoreUncertain
 var IgnoreCycle = core.IgnoreCycle
+var ActiveProfiles = core.ActiveProfiles
+var Strict = core.Strict
+var Eager = core.Eager
+var CycleSearchLimits = core.CycleSearchLimits
+var NoCallLocations = core.NoCallLocations
+var CaptureLocations = model.CaptureLocations
 var NewContainer = core.NewContainer
 
 var NewModuleBuilder = model.NewModuleBuilder
@@ -17,8 +27,26 @@ var NewModule = model.NewModule
 
 var Module = model.SubModule
 var Provide = model.Provide
+var Private = model.Private
+var Export = model.Export
+var Namespace = model.Namespace
+var WithNamespace = model.WithNamespace
+var NamespacedTag = model.NamespacedSymbol
+var Require = model.Require
+var Default = model.Default
+
+var When = model.When
+var Profile = model.Profile
+var DeclareProfiles = model.DeclareProfiles
+var OnProfile = model.OnProfile
+var OnEnv = model.OnEnv
+var OnSwitch = model.OnSwitch
+var OnFunc = model.OnFunc
+var Not = model.Not
 
 var Value = model.Value
+var Supply = model.Supply
+var Bind = model.Bind
 
 var Struct = model.Struct
 var Field = model.Field
@@ -49,6 +77,15 @@ var ByTags = model.ByTags
 var NewTag = model.NewSymbol
 
 var NewScope = model.NewScope
+var NewScopeWith = model.NewScopeWith
+var ScopeInput = model.ScopeInput
+var ScopesWithAncestors = model.ScopesWithAncestors
+var FormatScopeHierarchy = model.FormatScopeHierarchy
+var ScopeGraph = core.ScopeGraph
+var Cache = model.Cache
+var Seed = core.Seed
+var NamedSeed = core.NamedSeed
+var OptionalSeed = core.OptionalSeed
 
 var BuildFunc = model.FuncConsumer
 var BuildStruct = model.StructConsumer
@@ -63,19 +100,44 @@ var StructOfCtx = core.StructOfCtx
 var ValueOfCtx = core.ValueOfCtx
 var EnterScopeCtx = core.EnterScopeCtx
 var LeaveScopeCtx = core.LeaveScopeCtx
+var EnterScopePathCtx = core.EnterScopePathCtx
+var LeaveScopeToCtx = core.LeaveScopeToCtx
 
 //goland:noinspection GoUnusedFunction
 func suppressUnusedWarningDsl() {
 	var _ = IgnoreMissing
 	var _ = IgnoreUncertain
 	var _ = IgnoreCycle
+	var _ = ActiveProfiles
+	var _ = Strict
+	var _ = Eager
+	var _ = CycleSearchLimits
+	var _ = NoCallLocations
+	var _ = CaptureLocations
 	var _ = NewContainer
 	var _ = NewModuleBuilder
 	var _ = NewModule
 	var _ = Module
 	var _ = Provide
+	var _ = Private
+	var _ = Export
+	var _ = Namespace
+	var _ = WithNamespace
+	var _ = NamespacedTag
+	var _ = Require
+	var _ = Default
+	var _ = When
+	var _ = Profile
+	var _ = DeclareProfiles
+	var _ = OnProfile
+	var _ = OnEnv
+	var _ = OnSwitch
+	var _ = OnFunc
+	var _ = Not
 	var _ = Struct
 	var _ = Value
+	var _ = Supply
+	var _ = Bind
 	var _ = Field
 	var _ = IgnoreFields
 	var _ = Func
@@ -96,6 +158,15 @@ func suppressUnusedWarningDsl() {
 	var _ = Tags
 	var _ = NewTag
 	var _ = NewScope
+	var _ = NewScopeWith
+	var _ = ScopeInput
+	var _ = ScopesWithAncestors
+	var _ = FormatScopeHierarchy
+	var _ = ScopeGraph
+	var _ = Cache
+	var _ = Seed
+	var _ = NamedSeed
+	var _ = OptionalSeed
 	var _ = BuildFunc
 	var _ = BuildStruct
 	var _ = BuildValue
@@ -107,4 +178,6 @@ func suppressUnusedWarningDsl() {
 	var _ = ValueOfCtx
 	var _ = EnterScopeCtx
 	var _ = LeaveScopeCtx
+	var _ = EnterScopePathCtx
+	var _ = LeaveScopeToCtx
 }
@@ -0,0 +1,200 @@
+// Command uni is the command line tool of uni.
+//
+//	uni gen [-o file] [-package name] [-pkgpath path] [-container name] <package> <module>
+//	uni check <package> <module>
+//	uni diff <old.json> <new.json>
+//
+// gen generates code wiring components of a module without reflection. module is an exported
+// variable of model.Module, or an exported function returning it, in the package. The package
+// is loaded by a temporary program in the current module, which must require
+// github.com/jison/uni/commands.
+//
+// check prints missing, uncertain and cyclic dependencies of the module with their source lines,
+// it exits with 1 if there are any. The module is loaded like gen.
+//
+// diff reports the difference of wiring between two snapshots written by commands.MarshalSnapshot,
+// it exits with 1 if they are different.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jison/uni/commands"
+)
+
+const usage = `usage:
+	uni gen [flags] <package> <module>
+	uni check <package> <module>
+	uni diff <old.json> <new.json>
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		_, _ = fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "gen":
+		if err := gen(os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "uni gen: %+v\n", err)
+			os.Exit(1)
+		}
+	case "check":
+		if err := check(os.Args[2:]); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				// the program has printed its diagnostics
+				os.Exit(exitErr.ExitCode())
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "uni check: %+v\n", err)
+			os.Exit(2)
+		}
+	case "diff":
+		same, err := diff(os.Args[2:])
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "uni diff: %+v\n", err)
+			os.Exit(2)
+		}
+		if !same {
+			os.Exit(1)
+		}
+	default:
+		_, _ = fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func diff(args []string) (bool, error) {
+	if len(args) != 2 {
+		return false, fmt.Errorf("expect two snapshots, but got %q", args)
+	}
+
+	var snapshots [2]*commands.Snapshot
+	for i, path := range args {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if snapshots[i], err = commands.UnmarshalSnapshot(data); err != nil {
+			return false, fmt.Errorf("%v: %v", path, err)
+		}
+	}
+
+	d := commands.Diff(snapshots[0], snapshots[1])
+	_, _ = fmt.Fprint(os.Stdout, d)
+	return d.Empty(), nil
+}
+
+func gen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	output := fs.String("o", "", "output file, uni_gen.go in the directory of the package by default")
+	pkgName := fs.String("package", "", "package name of the output file, the name of the package by default")
+	pkgPath := fs.String("pkgpath", "", "import path of the output file, the path of the package by default")
+	containerName := fs.String("container", "", "name of the type of the global scope, Container by default")
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expect the package and the module, but got %q", fs.Args())
+	}
+
+	pkg, err := listPackage(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if pkg.name == "main" {
+		return fmt.Errorf("can not load the module from package main")
+	}
+
+	opts := commands.GenOptions{Package: *pkgName, PkgPath: *pkgPath, ContainerName: *containerName}
+	if opts.Package == "" {
+		opts.Package = pkg.name
+	}
+	if opts.PkgPath == "" && opts.Package == pkg.name {
+		opts.PkgPath = pkg.importPath
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(pkg.dir, "uni_gen.go")
+	}
+	if out, err = filepath.Abs(out); err != nil {
+		return err
+	}
+
+	src, err := commands.GenDriver(pkg.importPath, fs.Arg(1), opts, out)
+	if err != nil {
+		return err
+	}
+
+	return runDriver(src, "uni_gen_")
+}
+
+func check(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expect the package and the module, but got %q", args)
+	}
+
+	pkg, err := listPackage(args[0])
+	if err != nil {
+		return err
+	}
+	if pkg.name == "main" {
+		return fmt.Errorf("can not load the module from package main")
+	}
+
+	src, err := commands.CheckDriver(pkg.importPath, args[1])
+	if err != nil {
+		return err
+	}
+	return runDriver(src, "uni_check_")
+}
+
+// runDriver runs the program in a temporary directory with the prefix
+func runDriver(src []byte, prefix string) error {
+	// the program is in the current module to resolve the package and its dependencies
+	dir, err := os.MkdirTemp(".", prefix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), src, 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "-tags", commands.GenBuildTag, "./"+filepath.ToSlash(dir))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+type goPackage struct {
+	importPath string
+	name       string
+	dir        string
+}
+
+func listPackage(pattern string) (*goPackage, error) {
+	out, err := exec.Command("go", "list", "-tags", commands.GenBuildTag,
+		"-f", "{{.ImportPath}}\t{{.Name}}\t{{.Dir}}", pattern).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("can not load %v: %s", pattern, exitErr.Stderr)
+		}
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("%v matches %v packages", pattern, len(lines))
+	}
+	fields := strings.Split(lines[0], "\t")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected output of go list: %q", lines[0])
+	}
+	return &goPackage{importPath: fields[0], name: fields[1], dir: fields[2]}, nil
+}
@@ -0,0 +1,560 @@
+package commands
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+)
+
+// DiagnosticOptions controls how diagnostics are rendered
+type DiagnosticOptions struct {
+	// Color enables ANSI colors, see TerminalDiagnosticOptions
+	Color bool
+	// Width is the most columns of candidates rendered side by side, 120 by default
+	Width int
+	// ContainerOptions are used to build the graph of the module
+	ContainerOptions []core.ContainerOption
+}
+
+const defaultDiagnosticWidth = 120
+
+// TerminalDiagnosticOptions enables colors if w is a terminal and NO_COLOR is not set
+func TerminalDiagnosticOptions(w io.Writer) DiagnosticOptions {
+	return DiagnosticOptions{Color: isTerminal(w)}
+}
+
+func isTerminal(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// RenderDiagnostics writes missing, uncertain and cyclic dependencies of the module to w, with
+// source lines of them, and returns the count of errors written, see RenderError
+func RenderDiagnostics(w io.Writer, m model.Module, opts DiagnosticOptions) (int, error) {
+	_, err := core.NewDependenceGraph(m, opts.ContainerOptions...)
+	if err == nil {
+		return 0, nil
+	}
+	return RenderError(w, err, opts)
+}
+
+// RenderError writes the error of NewContainer to w like RenderDiagnostics, and returns the count
+// of errors written. Errors of dependencies are rendered with source lines, others as they are.
+func RenderError(w io.Writer, err error, opts DiagnosticOptions) (int, error) {
+	if opts.Width <= 0 {
+		opts.Width = defaultDiagnosticWidth
+	}
+	r := &diagnosticRenderer{opts: opts, files: map[string][]string{}}
+
+	var missing []*core.MissingDependencyError
+	var uncertain []*core.UncertainDependencyError
+	var cycles []*core.DependenceCycleError
+	var others []error
+	walkError(err, func(err error) bool {
+		switch e := err.(type) {
+		case *core.MissingDependencyError:
+			missing = append(missing, e)
+		case *core.UncertainDependencyError:
+			uncertain = append(uncertain, e)
+		case *core.DependenceCycleError:
+			cycles = append(cycles, e)
+		default:
+			return false
+		}
+		return true
+	}, func(err error) {
+		others = append(others, err)
+	})
+	sort.SliceStable(missing, func(i, j int) bool {
+		return dependencyLocationKey(missing[i].Dependency) < dependencyLocationKey(missing[j].Dependency)
+	})
+	sort.SliceStable(uncertain, func(i, j int) bool {
+		return dependencyLocationKey(uncertain[i].Dependency) < dependencyLocationKey(uncertain[j].Dependency)
+	})
+
+	for _, e := range missing {
+		r.renderMissing(e)
+	}
+	for _, e := range uncertain {
+		r.renderUncertain(e)
+	}
+	for _, e := range cycles {
+		r.renderCycle(e)
+	}
+	for _, e := range others {
+		r.title("other", e.Error())
+		r.add(diagLine{})
+	}
+	count := len(missing) + len(uncertain) + len(cycles) + len(others)
+	if count > 0 {
+		summary := fmt.Sprintf("%d errors in the dependence graph", count)
+		if count == 1 {
+			summary = "1 error in the dependence graph"
+		}
+		r.add(diagLine{{summary, styleError}})
+	}
+
+	for _, l := range r.lines {
+		if _, err := io.WriteString(w, l.render(opts.Color)+"\n"); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// walkError visits the main errors in the tree of err. Subtrees of mains matched by match are
+// skipped, leaves not matched are passed to other
+func walkError(err error, match func(error) bool, other func(error)) {
+	if err == nil {
+		return
+	}
+	structErr, ok := err.(errors.StructError)
+	if !ok {
+		if !match(err) {
+			other(err)
+		}
+		return
+	}
+
+	subErrs := structErr.Errors()
+	if main := structErr.Main(); main != nil {
+		if match(main) {
+			return
+		}
+		if len(subErrs) == 0 {
+			other(main)
+			return
+		}
+	}
+	for _, subErr := range subErrs {
+		walkError(subErr, match, other)
+	}
+}
+
+// CheckDriver returns the source of the program rendering diagnostics of the module, which is the
+// symbol in the package at pkgPath, it exits with 1 if there are errors
+func CheckDriver(pkgPath string, symbol string) ([]byte, error) {
+	if !token.IsIdentifier(symbol) || !token.IsExported(symbol) {
+		return nil, errors.Newf("%q is not an exported identifier", symbol)
+	}
+
+	src := fmt.Sprintf(`// Code generated by uni check. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jison/uni/commands"
+	target %q
+)
+
+func main() {
+	m, err := commands.ModuleOf(target.%v)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%%+v\n", err)
+		os.Exit(2)
+	}
+	n, err := commands.RenderDiagnostics(os.Stderr, m, commands.TerminalDiagnosticOptions(os.Stderr))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%%+v\n", err)
+		os.Exit(2)
+	}
+	if n > 0 {
+		os.Exit(1)
+	}
+}
+`, pkgPath, symbol)
+	return format.Source([]byte(src))
+}
+
+// dependencyLocationKey orders dependencies by their locations
+func dependencyLocationKey(dep model.Dependency) string {
+	loc := dep.Consumer().Location()
+	if loc == nil {
+		return fmt.Sprintf("%v", dep)
+	}
+	return fmt.Sprintf("%v:%09d %v", loc.FileName(), loc.FileLine(), dep)
+}
+
+type diagStyle int
+
+const (
+	stylePlain diagStyle = iota
+	styleError
+	styleBold
+	styleGutter
+	stylePrimary
+	styleSecondary
+)
+
+var diagStyleCodes = map[diagStyle]string{
+	styleError:     "\x1b[1;31m",
+	styleBold:      "\x1b[1m",
+	styleGutter:    "\x1b[1;34m",
+	stylePrimary:   "\x1b[1;31m",
+	styleSecondary: "\x1b[1;36m",
+}
+
+type diagSpan struct {
+	text  string
+	style diagStyle
+}
+
+type diagLine []diagSpan
+
+func (l diagLine) width() int {
+	w := 0
+	for _, s := range l {
+		w += utf8.RuneCountInString(s.text)
+	}
+	return w
+}
+
+func (l diagLine) render(color bool) string {
+	var sb strings.Builder
+	for _, s := range l {
+		if code, ok := diagStyleCodes[s.style]; ok && color && s.text != "" {
+			sb.WriteString(code + s.text + "\x1b[0m")
+		} else {
+			sb.WriteString(s.text)
+		}
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+type diagnosticRenderer struct {
+	opts  DiagnosticOptions
+	files map[string][]string
+	lines []diagLine
+}
+
+func (r *diagnosticRenderer) add(lines ...diagLine) {
+	r.lines = append(r.lines, lines...)
+}
+
+func (r *diagnosticRenderer) title(kind string, msg string) {
+	r.add(diagLine{{"error[" + kind + "]", styleError}, {": " + msg, styleBold}})
+}
+
+func (r *diagnosticRenderer) note(gutter int, msg string) {
+	r.add(diagLine{{strings.Repeat(" ", gutter+1) + "= ", styleGutter}, {"note", styleBold}, {": " + msg, stylePlain}})
+}
+
+func (r *diagnosticRenderer) renderMissing(e *core.MissingDependencyError) {
+	dep := e.Dependency
+	r.title("missing", fmt.Sprintf("no component matches %v", dep))
+	snippet, gutter := r.snippetOfDependency(dep, "required here", stylePrimary)
+	r.add(snippet...)
+	r.note(gutter, fmt.Sprintf("required in %v", dep.Consumer().Scope()))
+	for _, b := range e.Blocked {
+		if b.Boundary.Private() {
+			r.note(gutter, fmt.Sprintf("%v at %v is not exported by private %v", b.Component,
+				b.Component.Provider().Location(), b.Boundary))
+		} else {
+			r.note(gutter, fmt.Sprintf("%v at %v is a default only visible in %v", b.Component,
+				b.Component.Provider().Location(), b.Boundary))
+		}
+	}
+	nearMisses := e.NearMisses
+	if len(nearMisses) > maxNearMisses {
+		nearMisses = nearMisses[:maxNearMisses]
+	}
+	for _, nm := range nearMisses {
+		r.note(gutter, fmt.Sprintf("did you mean %v at %v?", nm.Component, nm.Component.Provider().Location()))
+	}
+	r.add(diagLine{})
+}
+
+// maxNearMisses is the most near misses of a missing dependency rendered
+const maxNearMisses = 3
+
+func (r *diagnosticRenderer) renderUncertain(e *core.UncertainDependencyError) {
+	dep := e.Dependency
+	coms := append([]model.Component{}, e.Candidates...)
+	sort.SliceStable(coms, func(i, j int) bool {
+		return locationKey(coms[i].Provider()) < locationKey(coms[j].Provider())
+	})
+
+	r.title("uncertain", fmt.Sprintf("%d components match %v", len(coms), dep))
+	snippet, gutter := r.snippetOfDependency(dep, "required here", stylePrimary)
+	r.add(snippet...)
+	r.note(gutter, "candidates are")
+
+	var blocks [][]diagLine
+	for i, com := range coms {
+		header := diagLine{{fmt.Sprintf("[%d] ", i+1), styleSecondary}, {fmt.Sprintf("%v", com), styleBold}}
+		snippet, _ := r.snippet(com.Provider().Location(), typeNeedles(com.Type()), 0, "provided here",
+			styleSecondary)
+		blocks = append(blocks, append([]diagLine{header}, snippet...))
+	}
+	r.add(sideBySide(blocks, r.opts.Width)...)
+	r.add(diagLine{})
+}
+
+func (r *diagnosticRenderer) renderCycle(e *core.DependenceCycleError) {
+	deps := e.Dependencies
+	providers := len(e.Group.Providers())
+	r.title("cycle", fmt.Sprintf("%d providers depend on each other", providers))
+	gutter := 0
+	for i, dep := range deps {
+		label := fmt.Sprintf("requires %v", dep.Type())
+		if i == len(deps)-1 {
+			label += ", which closes the cycle"
+		}
+		var snippet []diagLine
+		snippet, gutter = r.snippetOfDependency(dep, label, stylePrimary)
+		r.add(snippet...)
+	}
+	if len(deps) < providers {
+		r.note(gutter, fmt.Sprintf("the shortest cycle is shown, %d providers form a strongly connected group",
+			providers))
+	}
+	if !e.Complete {
+		r.note(gutter, "the search of cycles stopped at its limits, there may be more cycles")
+	}
+	r.add(diagLine{})
+}
+
+// snippetOfDependency returns source lines of the consumer of the dependency, and highlights the
+// dependency in them
+func (r *diagnosticRenderer) snippetOfDependency(dep model.Dependency, label string,
+	style diagStyle) ([]diagLine, int) {
+	var needles []string
+	occurrence := 0
+	if field, ok := model.FieldOfDependency(dep); ok {
+		needles = append(needles, field.Name)
+	}
+	needles = append(needles, typeNeedles(dep.Type())...)
+	if index, ok := model.ParamIndexOfDependency(dep); ok {
+		// parameters of the same type before the dependency
+		dep.Consumer().Dependencies().Iterate(func(other model.Dependency) bool {
+			if i, ok := model.ParamIndexOfDependency(other); ok && i < index && other.Type() == dep.Type() {
+				occurrence += 1
+			}
+			return true
+		})
+	}
+	return r.snippet(dep.Consumer().Location(), needles, occurrence, label, style)
+}
+
+var qualifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*\.`)
+
+// typeNeedles returns texts of the type which may be in the source
+func typeNeedles(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	qualified := t.String()
+	unqualified := qualifierPattern.ReplaceAllString(qualified, "")
+	if unqualified == qualified {
+		return []string{qualified}
+	}
+	return []string{qualified, unqualified}
+}
+
+// maxSnippetSearch is the most lines after the location searched for the highlighted text
+const maxSnippetSearch = 5
+
+// snippet returns lines of the source at the location, with the occurrence-th of the first found
+// needle highlighted, and the width of the gutter
+func (r *diagnosticRenderer) snippet(loc location.Location, needles []string, occurrence int,
+	label string, style diagStyle) ([]diagLine, int) {
+	if loc == nil {
+		return []diagLine{{{"  --> ", styleGutter}, {"unknown location", stylePlain}}}, 1
+	}
+
+	arrow := diagLine{{"  --> ", styleGutter}, {fmt.Sprintf("%v:%v", displayPath(loc.FileName()), loc.FileLine()),
+		stylePlain}}
+	source := r.source(loc.FileName())
+	first := loc.FileLine()
+	if first < 1 || first > len(source) {
+		return []diagLine{arrow}, 1
+	}
+
+	last := first + maxSnippetSearch
+	if last > len(source) {
+		last = len(source)
+	}
+	found, col, length := first, -1, 0
+	for _, needle := range needles {
+		seen := 0
+		for n := first; n <= last && col < 0; n++ {
+			for _, c := range findWord(source[n-1], needle) {
+				if seen == occurrence {
+					found, col, length = n, c, utf8.RuneCountInString(needle)
+					break
+				}
+				seen += 1
+			}
+		}
+		if col >= 0 {
+			break
+		}
+	}
+	if col < 0 {
+		// underline the whole line if nothing is found
+		text := source[first-1]
+		trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+		col = utf8.RuneCountInString(text) - utf8.RuneCountInString(trimmed)
+		length = utf8.RuneCountInString(strings.TrimRightFunc(trimmed, unicode.IsSpace))
+	}
+
+	gutter := len(strconv.Itoa(found))
+	arrow[0].text = strings.Repeat(" ", gutter) + "--> "
+	bar := func(prefix string) diagSpan {
+		return diagSpan{fmt.Sprintf("%*s | ", gutter, prefix), styleGutter}
+	}
+	shown := func(n int) bool {
+		return found-first <= 3 || n == first || n == found
+	}
+	// lines are dedented to be compact
+	indent := -1
+	for n := first; n <= found; n++ {
+		if text := source[n-1]; shown(n) && strings.TrimSpace(text) != "" {
+			if i := len(text) - len(strings.TrimLeft(text, " ")); indent < 0 || i < indent {
+				indent = i
+			}
+		}
+	}
+	if indent < 0 || indent > col {
+		indent = 0
+	}
+
+	lines := []diagLine{arrow, {bar("")}}
+	for n := first; n <= found; n++ {
+		if !shown(n) {
+			if n == first+1 {
+				lines = append(lines, diagLine{{strings.Repeat(".", gutter) + " | ", styleGutter}})
+			}
+			continue
+		}
+		text := source[n-1]
+		if len(text) >= indent {
+			text = text[indent:]
+		}
+		lines = append(lines, diagLine{bar(strconv.Itoa(n)), {text, stylePlain}})
+	}
+	lines = append(lines, diagLine{bar(""), {strings.Repeat(" ", col-indent), stylePlain},
+		{strings.Repeat("^", length) + " " + label, style}})
+	return lines, gutter
+}
+
+// displayPath returns the path relative to the working directory if the file is in it
+func displayPath(fileName string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fileName
+	}
+	rel, err := filepath.Rel(wd, fileName)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fileName
+	}
+	return rel
+}
+
+// source returns lines of the file with tabs expanded, or nil if it can not be read
+func (r *diagnosticRenderer) source(fileName string) []string {
+	if lines, ok := r.files[fileName]; ok {
+		return lines
+	}
+	var lines []string
+	if data, err := os.ReadFile(fileName); err == nil {
+		lines = strings.Split(strings.ReplaceAll(string(data), "\t", "    "), "\n")
+	}
+	r.files[fileName] = lines
+	return lines
+}
+
+// findWord returns columns of the needle in the text, which is not a part of an identifier
+func findWord(text string, needle string) []int {
+	isIdent := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	var cols []int
+	for start := 0; ; {
+		i := strings.Index(text[start:], needle)
+		if i < 0 {
+			return cols
+		}
+		i += start
+		end := i + len(needle)
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		first, _ := utf8.DecodeRuneInString(needle)
+		last, _ := utf8.DecodeLastRuneInString(needle)
+		if !(i > 0 && isIdent(first) && isIdent(before)) && !(end < len(text) && isIdent(last) && isIdent(after)) {
+			cols = append(cols, utf8.RuneCountInString(text[:i]))
+		}
+		start = i + len(needle)
+	}
+}
+
+// sideBySide lays out blocks in columns if they fit in the width, otherwise one after another
+func sideBySide(blocks [][]diagLine, width int) []diagLine {
+	const gap = 4
+	widths := make([]int, len(blocks))
+	total, height := 0, 0
+	for i, block := range blocks {
+		for _, l := range block {
+			if w := l.width(); w > widths[i] {
+				widths[i] = w
+			}
+		}
+		total += widths[i]
+		if len(block) > height {
+			height = len(block)
+		}
+	}
+	total += gap * (len(blocks) - 1)
+
+	if total > width || len(blocks) < 2 {
+		var lines []diagLine
+		for _, block := range blocks {
+			lines = append(lines, block...)
+		}
+		return lines
+	}
+
+	lines := make([]diagLine, height)
+	for i, block := range blocks {
+		for row := 0; row < height; row++ {
+			var l diagLine
+			if row < len(block) {
+				l = block[row]
+			}
+			if i < len(blocks)-1 {
+				l = append(append(diagLine{}, l...),
+					diagSpan{strings.Repeat(" ", widths[i]-l.width()+gap), stylePlain})
+			}
+			lines[row] = append(lines[row], l...)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type diagA struct{}
+type diagB interface{}
+
+// sourceLineOf returns the line in this file containing the text, as rendered in diagnostics
+func sourceLineOf(t *testing.T, text string) (string, int, string) {
+	const fileName = "diagnostics_test.go"
+	data, err := os.ReadFile(fileName)
+	assert.Nil(t, err)
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, text) && !strings.Contains(line, "sourceLineOf") {
+			return fileName, i + 1, strings.TrimLeft(line, "\t")
+		}
+	}
+	t.Fatalf("%q is not found", text)
+	return "", 0, ""
+}
+
+func caretsUnder(line string, needle string, occurrence int, label string) string {
+	col := 0
+	for i := 0; i <= occurrence; i++ {
+		col += strings.Index(line[col:], needle)
+		if i < occurrence {
+			col += len(needle)
+		}
+	}
+	return strings.Repeat(" ", col) + strings.Repeat("^", len(needle)) + " " + label
+}
+
+func TestRenderDiagnostics(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, model.NewModule(model.Value(1)), DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a *diagA) string { return "" }),
+		)
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, m, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		file, line, text := sourceLineOf(t, "func(a *diagA) string")
+		gutter := len(fmt.Sprint(line))
+		expected := strings.Join([]string{
+			"error[missing]: no component matches Dependency[*commands.diagA] at parameter `0`",
+			fmt.Sprintf("%v--> %v:%v", strings.Repeat(" ", gutter), file, line),
+			strings.Repeat(" ", gutter) + " |",
+			fmt.Sprintf("%v | %v", line, text),
+			strings.Repeat(" ", gutter) + " | " + caretsUnder(text, "*diagA", 0, "required here"),
+			strings.Repeat(" ", gutter+1) + "= note: required in Global",
+			"",
+			"1 error in the dependence graph",
+			"",
+		}, "\n")
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("missing in private module", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a *diagA) string { return "" }),
+			model.SubModule(model.NewModule(
+				model.Private(),
+				model.Value(&diagA{}),
+			)),
+		)
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, m, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Contains(t, buf.String(), "error[missing]: no component matches Dependency[*commands.diagA]")
+		assert.Contains(t, buf.String(), "is not exported by private Module at")
+	})
+
+	t.Run("near miss", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a *diagA) string { return "" }),
+			model.Value(diagA{}),
+		)
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, m, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Contains(t, buf.String(), "= note: did you mean Component[commands.diagA] at")
+	})
+
+	t.Run("uncertain", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a int, b int) string { return "" }, model.Param(0, model.Optional(true))),
+			model.Value(1, model.Name("one")),
+			model.Value(2, model.Name("two")),
+		)
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, m, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		out := buf.String()
+
+		_, _, text := sourceLineOf(t, "func(a int, b int) string")
+		assert.Contains(t, out, "error[uncertain]: 2 components match Dependency[int] at parameter `1`")
+		assert.Contains(t, out, caretsUnder(text, "int", 1, "required here"))
+		assert.Contains(t, out, "= note: candidates are")
+
+		// candidates are side by side
+		_, line1, text1 := sourceLineOf(t, `model.Value(1, model.Name("one"))`)
+		_, line2, _ := sourceLineOf(t, `model.Value(2, model.Name("two"))`)
+		var row string
+		for _, l := range strings.Split(out, "\n") {
+			if strings.HasPrefix(l, fmt.Sprintf("%v | %v", line1, text1)) {
+				row = l
+			}
+		}
+		assert.Contains(t, row, fmt.Sprintf("%v | ", line2))
+		assert.Contains(t, out, `[1] Component[int]{name="one"}`)
+		assert.Contains(t, out, `[2] Component[int]{name="two"}`)
+
+		t.Run("narrow", func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := RenderDiagnostics(&buf, m, DiagnosticOptions{Width: 40})
+			assert.Nil(t, err)
+			for _, l := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(l, fmt.Sprintf("%v | ", line1)) {
+					assert.NotContains(t, l, fmt.Sprintf("%v | ", line2))
+				}
+			}
+		})
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a diagB) int8 { return 0 }),
+			model.Func(func(a int8) diagB { return nil }),
+		)
+		var buf bytes.Buffer
+		n, err := RenderDiagnostics(&buf, m, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		out := buf.String()
+
+		_, _, text1 := sourceLineOf(t, "func(a diagB) int8")
+		_, _, text2 := sourceLineOf(t, "func(a int8) diagB")
+		assert.Contains(t, out, "error[cycle]: 2 providers depend on each other")
+		i1 := strings.Index(out, text1)
+		i2 := strings.Index(out, text2)
+		assert.True(t, i1 >= 0 && i2 >= 0)
+		assert.True(t, strings.Contains(out, caretsUnder(text1, "diagB", 0, "requires commands.diagB")) ||
+			strings.Contains(out, caretsUnder(text1, "diagB", 0, "requires commands.diagB, which closes the cycle")))
+		assert.True(t, strings.Contains(out, caretsUnder(text2, "int8", 0, "requires int8")) ||
+			strings.Contains(out, caretsUnder(text2, "int8", 0, "requires int8, which closes the cycle")))
+	})
+
+	t.Run("color", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a *diagA) string { return "" }),
+		)
+		var buf bytes.Buffer
+		_, err := RenderDiagnostics(&buf, m, DiagnosticOptions{Color: true})
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "\x1b[1;31merror[missing]\x1b[0m")
+	})
+}
+
+func TestRenderError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := RenderError(&buf, nil, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error of container", func(t *testing.T) {
+		m := model.NewModule(
+			model.Func(func(a *diagA) string { return "" }),
+			model.Func(func(a diagB) int8 { return 0 }),
+			model.Func(func(a int8) diagB { return nil }),
+		)
+		_, err := core.NewContainer(m)
+		assert.NotNil(t, err)
+
+		var buf bytes.Buffer
+		n, err := RenderError(&buf, err, DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Contains(t, buf.String(), "error[missing]")
+		assert.Contains(t, buf.String(), "error[cycle]")
+		assert.Contains(t, buf.String(), "2 errors in the dependence graph")
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := RenderError(&buf, errors.Newf("main").AddErrorf("a").AddErrorf("b"), DiagnosticOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Contains(t, buf.String(), "error[other]: a\n")
+		assert.Contains(t, buf.String(), "error[other]: b\n")
+		assert.NotContains(t, buf.String(), "main")
+	})
+}
+
+func TestCheckDriver(t *testing.T) {
+	src, err := CheckDriver("example.com/app", "Module")
+	assert.Nil(t, err)
+	assert.Contains(t, string(src), `target "example.com/app"`)
+	assert.Contains(t, string(src), "commands.ModuleOf(target.Module)")
+
+	_, err = CheckDriver("example.com/app", "module")
+	assert.NotNil(t, err)
+}
+
+func TestTerminalDiagnosticOptions(t *testing.T) {
+	assert.False(t, TerminalDiagnosticOptions(&bytes.Buffer{}).Color)
+}
+
+func Test_findWord(t *testing.T) {
+	assert.Equal(t, []int{7, 29}, findWord("func(a int, b interface{}, c int)", "int"))
+	assert.Equal(t, []int{7}, findWord("func(a *Foo, b *FooBar)", "*Foo"))
+	assert.Nil(t, findWord("func(a Foo)", "Bar"))
+}
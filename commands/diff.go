@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SnapshotDiff is the difference of wiring between two snapshots
+type SnapshotDiff struct {
+	AddedProviders   []string
+	RemovedProviders []string
+	// ChangedMatches are dependencies matching other components than before
+	ChangedMatches []MatchChange
+	// NewAmbiguities are dependencies matching more than one component, which were not before
+	NewAmbiguities []MatchChange
+	// NewMissing are required dependencies matching no component, which were matched before
+	NewMissing []MatchChange
+	// ResolvedAmbiguities are dependencies which matched more than one component, and do not now
+	ResolvedAmbiguities []MatchChange
+	// ResolvedMissing are required dependencies which matched no component, and do now
+	ResolvedMissing []MatchChange
+	// RemovedDependencies are dependencies of providers in both snapshots, which are removed
+	RemovedDependencies []MatchChange
+}
+
+// MatchChange is the change of components matching a dependency of a provider
+type MatchChange struct {
+	Provider   string
+	Dependency string
+	Old        []string
+	New        []string
+}
+
+// Diff compares two snapshots, providers are matched by their IDs, and dependencies by their keys
+func Diff(before *Snapshot, after *Snapshot) *SnapshotDiff {
+	d := &SnapshotDiff{}
+	oldProviders := map[string]*SnapshotProvider{}
+	for i := range before.Providers {
+		oldProviders[before.Providers[i].ID] = &before.Providers[i]
+	}
+	newProviders := map[string]*SnapshotProvider{}
+	for i := range after.Providers {
+		newProviders[after.Providers[i].ID] = &after.Providers[i]
+	}
+
+	for _, op := range before.Providers {
+		if _, ok := newProviders[op.ID]; !ok {
+			d.RemovedProviders = append(d.RemovedProviders, op.ID)
+		}
+	}
+
+	for _, np := range after.Providers {
+		op, ok := oldProviders[np.ID]
+		if !ok {
+			d.AddedProviders = append(d.AddedProviders, np.ID)
+		}
+
+		oldDeps := map[string]*SnapshotDependency{}
+		if op != nil {
+			for i := range op.Dependencies {
+				oldDeps[op.Dependencies[i].Key] = &op.Dependencies[i]
+			}
+		}
+		newDeps := map[string]struct{}{}
+		for i := range np.Dependencies {
+			newDeps[np.Dependencies[i].Key] = struct{}{}
+		}
+		if op != nil {
+			for _, od := range op.Dependencies {
+				if _, ok := newDeps[od.Key]; !ok {
+					d.RemovedDependencies = append(d.RemovedDependencies,
+						MatchChange{Provider: np.ID, Dependency: od.Key, Old: od.Matches})
+				}
+			}
+		}
+
+		for i := range np.Dependencies {
+			nd := &np.Dependencies[i]
+			od := oldDeps[nd.Key]
+			change := MatchChange{Provider: np.ID, Dependency: nd.Key, New: nd.Matches}
+			if od != nil {
+				change.Old = od.Matches
+			}
+
+			switch {
+			case nd.Ambiguous() && (od == nil || !od.Ambiguous()):
+				d.NewAmbiguities = append(d.NewAmbiguities, change)
+			case nd.Missing() && (od == nil || !od.Missing()):
+				d.NewMissing = append(d.NewMissing, change)
+			case od != nil && od.Ambiguous() && !nd.Ambiguous():
+				d.ResolvedAmbiguities = append(d.ResolvedAmbiguities, change)
+			case od != nil && od.Missing() && !nd.Missing():
+				d.ResolvedMissing = append(d.ResolvedMissing, change)
+			case od != nil && !equalStrings(od.Matches, nd.Matches):
+				d.ChangedMatches = append(d.ChangedMatches, change)
+			}
+		}
+	}
+
+	sort.Strings(d.AddedProviders)
+	sort.Strings(d.RemovedProviders)
+	return d
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if there is no difference
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.AddedProviders) == 0 && len(d.RemovedProviders) == 0 && len(d.ChangedMatches) == 0 &&
+		len(d.NewAmbiguities) == 0 && len(d.NewMissing) == 0 && len(d.ResolvedAmbiguities) == 0 &&
+		len(d.ResolvedMissing) == 0 && len(d.RemovedDependencies) == 0
+}
+
+func (d *SnapshotDiff) String() string {
+	var sb strings.Builder
+	for _, id := range d.AddedProviders {
+		sb.WriteString(fmt.Sprintf("+ provider %v\n", id))
+	}
+	for _, id := range d.RemovedProviders {
+		sb.WriteString(fmt.Sprintf("- provider %v\n", id))
+	}
+	writeChanges := func(title string, changes []MatchChange) {
+		for _, c := range changes {
+			sb.WriteString(fmt.Sprintf("%v %v of %v\n", title, c.Dependency, c.Provider))
+			for _, id := range c.Old {
+				sb.WriteString(fmt.Sprintf("\t- %v\n", id))
+			}
+			for _, id := range c.New {
+				sb.WriteString(fmt.Sprintf("\t+ %v\n", id))
+			}
+		}
+	}
+	writeChanges("~ matches changed:", d.ChangedMatches)
+	writeChanges("! ambiguous:", d.NewAmbiguities)
+	writeChanges("! missing:", d.NewMissing)
+	writeChanges("~ ambiguity resolved:", d.ResolvedAmbiguities)
+	writeChanges("~ missing resolved:", d.ResolvedMissing)
+	writeChanges("- dependency removed:", d.RemovedDependencies)
+	return sb.String()
+}
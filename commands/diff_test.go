@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type diffA struct{}
+type diffB struct{}
+type diffC struct{}
+
+func newDiffA() *diffA                   { return &diffA{} }
+func newOtherDiffA() *diffA              { return &diffA{} }
+func newDiffB(a *diffA) *diffB           { return &diffB{} }
+func newDiffC(a *diffA, b *diffB) *diffC { return &diffC{} }
+func newDiffString(c *diffC) string      { return "" }
+
+func snapshotOf(t *testing.T, providers ...model.ModuleOption) *Snapshot {
+	s, err := SnapshotOf(model.NewModule(providers...))
+	assert.Nil(t, err)
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	const pkg = "github.com/jison/uni/commands"
+
+	before := snapshotOf(t,
+		model.Func(newDiffA, model.Return(0, model.Name("a"))),
+		model.Func(newDiffB, model.Param(0, model.ByName("a"))),
+		model.Func(newDiffC),
+		model.Func(newDiffString),
+	)
+
+	t.Run("same", func(t *testing.T) {
+		after := snapshotOf(t,
+			model.Func(newDiffString),
+			model.Func(newDiffC),
+			model.Func(newDiffB, model.Param(0, model.ByName("a"))),
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+		)
+		d := Diff(before, after)
+		assert.True(t, d.Empty())
+		assert.Equal(t, "", d.String())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		after := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newOtherDiffA, model.Return(0, model.Name("b"))),
+			model.Func(newDiffB, model.Param(0, model.ByName("b"))),
+			model.Func(newDiffC),
+		)
+		d := Diff(before, after)
+		assert.False(t, d.Empty())
+		assert.Equal(t, []string{"func *" + pkg + `.diffA name="b" in Global`}, d.AddedProviders)
+		assert.Equal(t, []string{"func string in Global"}, d.RemovedProviders)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func *" + pkg + ".diffB in Global",
+			Dependency: "param 0",
+			Old:        []string{"*" + pkg + `.diffA name="a" in Global`},
+			New:        []string{"*" + pkg + `.diffA name="b" in Global`},
+		}}, d.ChangedMatches)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func *" + pkg + ".diffC in Global",
+			Dependency: "param 0",
+			Old:        []string{"*" + pkg + `.diffA name="a" in Global`},
+			New:        []string{"*" + pkg + `.diffA name="a" in Global`, "*" + pkg + `.diffA name="b" in Global`},
+		}}, d.NewAmbiguities)
+		assert.Empty(t, d.NewMissing)
+
+		assert.Equal(t, "+ provider func *"+pkg+".diffA name=\"b\" in Global\n"+
+			"- provider func string in Global\n"+
+			"~ matches changed: param 0 of func *"+pkg+".diffB in Global\n"+
+			"\t- *"+pkg+".diffA name=\"a\" in Global\n"+
+			"\t+ *"+pkg+".diffA name=\"b\" in Global\n"+
+			"! ambiguous: param 0 of func *"+pkg+".diffC in Global\n"+
+			"\t- *"+pkg+".diffA name=\"a\" in Global\n"+
+			"\t+ *"+pkg+".diffA name=\"a\" in Global\n"+
+			"\t+ *"+pkg+".diffA name=\"b\" in Global\n", d.String())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		after := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newDiffC),
+			model.Func(newDiffString),
+		)
+		d := Diff(before, after)
+		assert.Equal(t, []string{"func *" + pkg + ".diffB in Global"}, d.RemovedProviders)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func *" + pkg + ".diffC in Global",
+			Dependency: "param 1",
+			Old:        []string{"*" + pkg + ".diffB in Global"},
+			New:        []string{},
+		}}, d.NewMissing)
+		assert.Empty(t, d.ChangedMatches)
+	})
+
+	t.Run("added provider with ambiguity", func(t *testing.T) {
+		after := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newOtherDiffA),
+			model.Func(newDiffB, model.Param(0, model.ByName("a"))),
+			model.Func(newDiffC),
+			model.Func(newDiffString),
+			model.Func(func(a *diffA) int { return 0 }),
+		)
+		d := Diff(before, after)
+		assert.Len(t, d.AddedProviders, 2)
+		assert.Len(t, d.NewAmbiguities, 2)
+		assert.Equal(t, "func int in Global", d.NewAmbiguities[1].Provider)
+		assert.Nil(t, d.NewAmbiguities[1].Old)
+	})
+
+	t.Run("resolved", func(t *testing.T) {
+		ambiguous := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newOtherDiffA, model.Return(0, model.Name("b"))),
+			model.Func(newDiffB, model.Param(0, model.ByName("a"))),
+			model.Func(newDiffC),
+		)
+		d := Diff(ambiguous, before)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func *" + pkg + ".diffC in Global",
+			Dependency: "param 0",
+			Old:        []string{"*" + pkg + `.diffA name="a" in Global`, "*" + pkg + `.diffA name="b" in Global`},
+			New:        []string{"*" + pkg + `.diffA name="a" in Global`},
+		}}, d.ResolvedAmbiguities)
+
+		missing := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newDiffC),
+			model.Func(newDiffString),
+		)
+		d = Diff(missing, before)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func *" + pkg + ".diffC in Global",
+			Dependency: "param 1",
+			Old:        []string{},
+			New:        []string{"*" + pkg + ".diffB in Global"},
+		}}, d.ResolvedMissing)
+		assert.Contains(t, d.String(), "~ missing resolved: param 1 of func *"+pkg+".diffC in Global\n")
+	})
+
+	t.Run("removed dependency", func(t *testing.T) {
+		after := snapshotOf(t,
+			model.Func(newDiffA, model.Return(0, model.Name("a"))),
+			model.Func(newDiffB, model.Param(0, model.ByName("a"))),
+			model.Func(newDiffC),
+			model.Func(func() string { return "" }),
+		)
+		d := Diff(before, after)
+		assert.Empty(t, d.AddedProviders)
+		assert.Empty(t, d.RemovedProviders)
+		assert.Equal(t, []MatchChange{{
+			Provider:   "func string in Global",
+			Dependency: "param 0",
+			Old:        []string{"*" + pkg + ".diffC in Global"},
+		}}, d.RemovedDependencies)
+		assert.Equal(t, "- dependency removed: param 0 of func string in Global\n"+
+			"\t- *"+pkg+".diffC in Global\n", d.String())
+	})
+}
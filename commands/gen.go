@@ -0,0 +1,884 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/reflecting"
+)
+
+// GenOptions controls the code generated from a module
+type GenOptions struct {
+	// Package is the name of the package of the generated code
+	Package string
+	// PkgPath is the import path of the package of the generated code, types and functions in
+	// this package are not qualified, and can be unexported
+	PkgPath string
+	// ContainerName is the name of the generated type of the global scope, "Container" by default
+	ContainerName string
+	// ContainerOptions are used to build the graph of the module
+	ContainerOptions []core.ContainerOption
+}
+
+// GenBuildTag is the build tag set when running the program generating code, files generated
+// before are excluded with it
+const GenBuildTag = "unigen"
+
+// ModuleOf returns the module in v, which is a model.Module or a function returning it
+func ModuleOf(v interface{}) (model.Module, error) {
+	switch m := v.(type) {
+	case model.Module:
+		return m, nil
+	case func() model.Module:
+		return m(), nil
+	default:
+		return nil, errors.Newf("%T is neither a module nor a function returning a module", v)
+	}
+}
+
+// GenerateFile generates the code of the module in v to the file at path, v is a model.Module
+// or a function returning it
+func GenerateFile(v interface{}, opts GenOptions, path string) error {
+	m, err := ModuleOf(v)
+	if err != nil {
+		return err
+	}
+	src, err := Generate(m, opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, src, 0644)
+}
+
+// Generate generates the code creating components of the module without reflection. Each scope
+// is a generated type, the global scope is the container, and other scopes are entered by
+// methods of their parents with their inputs. Components are resolved by methods named after
+// them, which call providers in topological order and cache their values in the scope.
+func Generate(m model.Module, opts GenOptions) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, errors.Newf("package name is empty")
+	}
+	if opts.ContainerName == "" {
+		opts.ContainerName = "Container"
+	}
+
+	g, err := core.NewDependenceGraph(m, opts.ContainerOptions...)
+	if err != nil {
+		return nil, errors.Empty().AddErrors(err).WithMainf("can not build the graph of the module")
+	}
+
+	gen := newGenerator(g, opts)
+	if err = gen.prepare(); err != nil {
+		return nil, errors.Empty().AddErrors(err).WithMainf("can not generate code of the module")
+	}
+	src, err := gen.generate()
+	if err != nil {
+		return nil, errors.Empty().AddErrors(err).WithMainf("can not generate code of the module")
+	}
+	return src, nil
+}
+
+// GenDriver returns the source of the program generating code of the module, which is the symbol
+// in the package at pkgPath, to the file at output
+func GenDriver(pkgPath string, symbol string, opts GenOptions, output string) ([]byte, error) {
+	if !token.IsIdentifier(symbol) || !token.IsExported(symbol) {
+		return nil, errors.Newf("%q is not an exported identifier", symbol)
+	}
+
+	src := fmt.Sprintf(`// Code generated by uni gen. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jison/uni/commands"
+	target %q
+)
+
+func main() {
+	opts := commands.GenOptions{Package: %q, PkgPath: %q, ContainerName: %q}
+	if err := commands.GenerateFile(target.%v, opts, %q); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%%+v\n", err)
+		os.Exit(1)
+	}
+}
+`, pkgPath, opts.Package, opts.PkgPath, opts.ContainerName, symbol, output)
+	return format.Source([]byte(src))
+}
+
+type genScope struct {
+	scope     model.Scope
+	typeName  string
+	parent    *genScope
+	inputs    []*genComponent
+	providers []*genProvider
+	getters   map[string]struct{}
+}
+
+type genProvider struct {
+	provider   model.Provider
+	index      int
+	scope      *genScope
+	components []*genComponent
+}
+
+type genComponent struct {
+	com      model.Component
+	provider *genProvider
+	scope    *genScope
+	field    string
+	getter   string
+	typeExpr string
+}
+
+type generator struct {
+	graph core.DependenceGraph
+	opts  GenOptions
+
+	scopes      []*genScope
+	scopeOf     map[model.Scope]*genScope
+	providers   []*genProvider
+	providerOf  map[model.Provider]*genProvider
+	componentOf map[model.Component]*genComponent
+
+	imports map[string]string
+	// packages whose names are known from their types, others are imported with aliases
+	knownNames map[string]struct{}
+	aliases    map[string]struct{}
+	errs       errors.StructError
+	useFmt     bool
+}
+
+func newGenerator(g core.DependenceGraph, opts GenOptions) *generator {
+	return &generator{
+		graph:       g,
+		opts:        opts,
+		scopeOf:     map[model.Scope]*genScope{},
+		providerOf:  map[model.Provider]*genProvider{},
+		componentOf: map[model.Component]*genComponent{},
+		imports:     map[string]string{},
+		knownNames:  map[string]struct{}{},
+		aliases:     map[string]struct{}{"fmt": {}, "io": {}, "sync": {}},
+		errs:        errors.Empty(),
+	}
+}
+
+func locationKey(p model.Provider) string {
+	loc := p.Location()
+	if loc == nil {
+		return fmt.Sprintf("%v", p)
+	}
+	return fmt.Sprintf("%v:%08d %v", loc.FileName(), loc.FileLine(), p)
+}
+
+// prepare collects scopes, providers and components, and names them
+func (gen *generator) prepare() error {
+	var providers []model.Provider
+	gen.graph.Nodes().Each(func(node core.Node) {
+		if p, ok := gen.graph.ProviderOfNode(node); ok {
+			providers = append(providers, p)
+		}
+	})
+	sort.SliceStable(providers, func(i, j int) bool {
+		return locationKey(providers[i]) < locationKey(providers[j])
+	})
+
+	var scopes []model.Scope
+	for _, p := range providers {
+		scopes = append(scopes, p.Scope())
+	}
+	if err := gen.prepareScopes(model.ScopesWithAncestors(scopes...)); err != nil {
+		return err
+	}
+
+	// providers are sorted in topological order, dependencies first
+	visited := map[model.Provider]struct{}{}
+	var visit func(p model.Provider)
+	visit = func(p model.Provider) {
+		if _, ok := visited[p]; ok {
+			return
+		}
+		visited[p] = struct{}{}
+		for _, dep := range sortedDependencies(p) {
+			for _, com := range gen.componentsOfDependency(dep) {
+				visit(com.Provider())
+			}
+		}
+		gen.addProvider(p)
+	}
+	for _, p := range providers {
+		visit(p)
+	}
+
+	if gen.errs.HasError() {
+		return gen.errs
+	}
+	return nil
+}
+
+func (gen *generator) prepareScopes(scopes []model.Scope) error {
+	sort.SliceStable(scopes, func(i, j int) bool {
+		if scopes[i] == model.GlobalScope || scopes[j] == model.GlobalScope {
+			return scopes[i] == model.GlobalScope && scopes[j] != model.GlobalScope
+		}
+		return scopes[i].Name() < scopes[j].Name()
+	})
+
+	typeNames := map[string]struct{}{gen.opts.ContainerName: {}}
+	var add func(s model.Scope) (*genScope, error)
+	add = func(s model.Scope) (*genScope, error) {
+		if gs, ok := gen.scopeOf[s]; ok {
+			return gs, nil
+		}
+
+		gs := &genScope{scope: s, getters: map[string]struct{}{}}
+		if s == model.GlobalScope {
+			gs.typeName = gen.opts.ContainerName
+		} else {
+			parents := s.Parents()
+			if len(parents) != 1 {
+				return nil, errors.Newf("%+v has %v parents, only scopes with one parent are supported",
+					s, len(parents))
+			}
+			parent, err := add(parents[0])
+			if err != nil {
+				return nil, err
+			}
+			gs.parent = parent
+			gs.typeName = uniqueName(exportedName(s.Name(), "Scope")+gen.opts.ContainerName, typeNames)
+		}
+		typeNames[gs.typeName] = struct{}{}
+		gen.scopeOf[s] = gs
+		gen.scopes = append(gen.scopes, gs)
+
+		for i, input := range s.Inputs() {
+			input.Components().Each(func(com model.Component) {
+				gc := &genComponent{
+					com:    com,
+					scope:  gs,
+					field:  fmt.Sprintf("in%d", i),
+					getter: gen.getterName(gs, com, i),
+				}
+				gen.componentOf[com] = gc
+				gs.inputs = append(gs.inputs, gc)
+			})
+		}
+		return gs, nil
+	}
+
+	for _, s := range scopes {
+		if _, err := add(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gen *generator) addProvider(p model.Provider) {
+	gs := gen.scopeOf[p.Scope()]
+	if model.IsScopeInputProvider(p) {
+		// inputs are fields of the scope
+		return
+	}
+
+	if _, ok := model.CachePolicyOf(p); ok {
+		gen.errs = gen.errs.AddErrorf("cache policy of %+v is not supported", p)
+	}
+
+	gp := &genProvider{provider: p, index: len(gen.providers), scope: gs}
+	gen.providers = append(gen.providers, gp)
+	gen.providerOf[p] = gp
+	gs.providers = append(gs.providers, gp)
+
+	coms := p.Components().ToArray()
+	sort.SliceStable(coms, func(i, j int) bool {
+		ii, _ := model.ReturnIndexOfComponent(coms[i])
+		jj, _ := model.ReturnIndexOfComponent(coms[j])
+		return ii < jj
+	})
+	for _, com := range coms {
+		index, _ := model.ReturnIndexOfComponent(com)
+		gc := &genComponent{
+			com:      com,
+			provider: gp,
+			scope:    gs,
+			field:    fmt.Sprintf("p%dr%d", gp.index, index),
+			getter:   gen.getterName(gs, com, gp.index),
+		}
+		gp.components = append(gp.components, gc)
+		gen.componentOf[com] = gc
+	}
+}
+
+func (gen *generator) getterName(gs *genScope, com model.Component, index int) string {
+	name := com.Name()
+	if name == "" {
+		t := com.Type()
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		name = t.Name()
+	}
+	name = exportedName(name, "Component")
+	if com.Hidden() {
+		name = unexportedName(name)
+	}
+	if name == "Close" || strings.HasPrefix(name, "Enter") {
+		name += "Component"
+	}
+	if _, ok := gs.getters[name]; ok {
+		name = uniqueName(fmt.Sprintf("%v%d", name, index), gs.getters)
+	}
+	gs.getters[name] = struct{}{}
+	return name
+}
+
+func sortedDependencies(p model.Provider) []model.Dependency {
+	var deps []model.Dependency
+	p.Dependencies().Iterate(func(dep model.Dependency) bool {
+		deps = append(deps, dep)
+		return true
+	})
+	sort.SliceStable(deps, func(i, j int) bool {
+		if ii, ok := model.ParamIndexOfDependency(deps[i]); ok {
+			jj, _ := model.ParamIndexOfDependency(deps[j])
+			return ii < jj
+		}
+		fi, _ := model.FieldOfDependency(deps[i])
+		fj, _ := model.FieldOfDependency(deps[j])
+		return lessIndex(fi.Index, fj.Index)
+	})
+	return deps
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func (gen *generator) componentsOfDependency(dep model.Dependency) []model.Component {
+	coms := gen.graph.InputComponentsToDependency(dep).ToArray()
+	sort.SliceStable(coms, func(i, j int) bool {
+		ki, kj := locationKey(coms[i].Provider()), locationKey(coms[j].Provider())
+		if ki != kj {
+			return ki < kj
+		}
+		ii, _ := model.ReturnIndexOfComponent(coms[i])
+		jj, _ := model.ReturnIndexOfComponent(coms[j])
+		return ii < jj
+	})
+	return coms
+}
+
+func (gen *generator) generate() ([]byte, error) {
+	body := &bytes.Buffer{}
+	for _, gs := range gen.scopes {
+		gen.writeScope(body, gs)
+	}
+	for _, gp := range gen.providers {
+		gen.writeProvider(body, gp)
+	}
+	if gen.errs.HasError() {
+		return nil, gen.errs
+	}
+
+	out := &bytes.Buffer{}
+	_, _ = fmt.Fprintf(out, "// Code generated by uni gen. DO NOT EDIT.\n\n")
+	// the generated code is excluded when generating it again, in case it is stale
+	_, _ = fmt.Fprintf(out, "//go:build !%v\n// +build !%v\n\npackage %v\n\n", GenBuildTag, GenBuildTag,
+		gen.opts.Package)
+	gen.writeImports(out)
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, errors.Bugf("generated code is invalid: %v", err)
+	}
+	return src, nil
+}
+
+func (gen *generator) writeImports(w io.Writer) {
+	paths := []string{"io", "sync"}
+	if gen.useFmt {
+		paths = append(paths, "fmt")
+	}
+	for path := range gen.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	_, _ = fmt.Fprintf(w, "import (\n")
+	for _, path := range paths {
+		alias, ok := gen.imports[path]
+		_, known := gen.knownNames[path]
+		if ok && (!known || alias != path[strings.LastIndex(path, "/")+1:]) {
+			_, _ = fmt.Fprintf(w, "%v %q\n", alias, path)
+		} else {
+			_, _ = fmt.Fprintf(w, "%q\n", path)
+		}
+	}
+	_, _ = fmt.Fprintf(w, ")\n\n")
+}
+
+func (gen *generator) writeScope(w io.Writer, gs *genScope) {
+	if gs.parent == nil {
+		_, _ = fmt.Fprintf(w, "// %v creates and caches components in the global scope\n", gs.typeName)
+	} else {
+		_, _ = fmt.Fprintf(w, "// %v creates and caches components in the scope `%v`\n", gs.typeName,
+			gs.scope.Name())
+	}
+	_, _ = fmt.Fprintf(w, "type %v struct {\n", gs.typeName)
+	if gs.parent != nil {
+		_, _ = fmt.Fprintf(w, "parent *%v\n", gs.parent.typeName)
+	}
+	for _, in := range gs.inputs {
+		_, _ = fmt.Fprintf(w, "%v %v\n", in.field, gen.typeOfComponent(in))
+	}
+	for _, gp := range gs.providers {
+		_, _ = fmt.Fprintf(w, "p%dmu sync.Mutex\np%ddone bool\n", gp.index, gp.index)
+		for _, gc := range gp.components {
+			_, _ = fmt.Fprintf(w, "%v %v\n", gc.field, gen.typeOfComponent(gc))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "closersMu sync.Mutex\nclosers []io.Closer\n}\n\n")
+
+	if gs.parent == nil {
+		_, _ = fmt.Fprintf(w, "// New%v creates the container of the global scope\n", gs.typeName)
+		_, _ = fmt.Fprintf(w, "func New%v() *%v {\nreturn &%v{}\n}\n\n", gs.typeName, gs.typeName, gs.typeName)
+	} else {
+		var params, fields []string
+		for _, in := range gs.inputs {
+			param := unexportedName(in.getter)
+			if param == "s" || token.Lookup(param).IsKeyword() {
+				param += "In"
+			}
+			params = append(params, fmt.Sprintf("%v %v", param, gen.typeOfComponent(in)))
+			fields = append(fields, fmt.Sprintf("%v: %v", in.field, param))
+		}
+		fields = append([]string{"parent: s"}, fields...)
+		method := "Enter" + exportedName(gs.scope.Name(), "Scope")
+		_, _ = fmt.Fprintf(w, "// %v enters the scope `%v` with its inputs\n", method, gs.scope.Name())
+		_, _ = fmt.Fprintf(w, "func (s *%v) %v(%v) *%v {\nreturn &%v{%v}\n}\n\n", gs.parent.typeName, method,
+			strings.Join(params, ", "), gs.typeName, gs.typeName, strings.Join(fields, ", "))
+	}
+
+	for _, in := range gs.inputs {
+		_, _ = fmt.Fprintf(w, "// %v returns the input %v\n", in.getter, in.com)
+		_, _ = fmt.Fprintf(w, "func (s *%v) %v() (%v, error) {\nreturn s.%v, nil\n}\n\n",
+			gs.typeName, in.getter, gen.typeOfComponent(in), in.field)
+	}
+	for _, gp := range gs.providers {
+		for _, gc := range gp.components {
+			if gc.com.Ignored() {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "// %v returns %v\n", gc.getter, gc.com)
+			_, _ = fmt.Fprintf(w, "func (s *%v) %v() (v %v, err error) {\n", gs.typeName, gc.getter,
+				gen.typeOfComponent(gc))
+			_, _ = fmt.Fprintf(w, "if err = s.provide%d(); err == nil {\nv = s.%v\n}\nreturn\n}\n\n",
+				gp.index, gc.field)
+		}
+	}
+
+	var reset strings.Builder
+	for _, gp := range gs.providers {
+		_, _ = fmt.Fprintf(&reset, "s.p%dmu.Lock()\ns.p%ddone = false\ns.p%dmu.Unlock()\n",
+			gp.index, gp.index, gp.index)
+	}
+
+	_, _ = fmt.Fprintf(w, `func (s *%v) track(v interface{}) {
+if closer, ok := v.(io.Closer); ok {
+s.closersMu.Lock()
+s.closers = append(s.closers, closer)
+s.closersMu.Unlock()
+}
+}
+
+// Close closes components implementing io.Closer which are created in the scope, the latest
+// created first, and returns the first error. Components are created again if they are got later
+func (s *%v) Close() error {
+s.closersMu.Lock()
+closers := s.closers
+s.closers = nil
+s.closersMu.Unlock()
+%v
+var err error
+for i := len(closers) - 1; i >= 0; i-- {
+if closeErr := closers[i].Close(); closeErr != nil && err == nil {
+err = closeErr
+}
+}
+return err
+}
+
+`, gs.typeName, gs.typeName, reset.String())
+}
+
+func (gen *generator) writeProvider(w io.Writer, gp *genProvider) {
+	gs := gp.scope
+	p := gp.provider
+	_, _ = fmt.Fprintf(w, "// provide%d creates components of %v\n", gp.index, p)
+	_, _ = fmt.Fprintf(w, "func (s *%v) provide%d() error {\n", gs.typeName, gp.index)
+	_, _ = fmt.Fprintf(w, "s.p%dmu.Lock()\ndefer s.p%dmu.Unlock()\nif s.p%ddone {\nreturn nil\n}\n",
+		gp.index, gp.index, gp.index)
+
+	deps := sortedDependencies(p)
+	args := make([]string, len(deps))
+	for i, dep := range deps {
+		args[i] = gen.writeDependency(w, gp, dep, i)
+	}
+
+	switch {
+	case model.IsBindProvider(p):
+		gc := gp.components[0]
+		_, _ = fmt.Fprintf(w, "s.%v = %v\n", gc.field, args[0])
+	default:
+		if _, ok := model.FuncOfProvider(p); ok {
+			gen.writeFuncCall(w, gp, deps, args)
+		} else if st, ok := model.StructTypeOfProvider(p); ok {
+			gen.writeStruct(w, gp, st, deps, args)
+		} else if val, ok := model.ValueOfProvider(p); ok {
+			_, _ = fmt.Fprintf(w, "s.%v = %v\n", gp.components[0].field, gen.literalOf(val))
+		} else {
+			gen.errs = gen.errs.AddErrorf("%+v is not supported", p)
+		}
+		for _, gc := range gp.components {
+			_, _ = fmt.Fprintf(w, "s.track(s.%v)\n", gc.field)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "s.p%ddone = true\nreturn nil\n}\n\n", gp.index)
+}
+
+// writeDependency writes statements resolving the dependency, and returns the expression of it
+func (gen *generator) writeDependency(w io.Writer, gp *genProvider, dep model.Dependency, i int) string {
+	coms := gen.componentsOfDependency(dep)
+	name := fmt.Sprintf("d%d", i)
+
+	if !dep.IsCollector() {
+		switch len(coms) {
+		case 0:
+			if !dep.Optional() {
+				gen.errs = gen.errs.AddErrorf("can not find components that match %+v", dep)
+			}
+			_, _ = fmt.Fprintf(w, "var %v %v\n", name, gen.typeExpr(dep.Type()))
+		case 1:
+			gen.writeGet(w, gp, coms[0], name)
+		default:
+			gen.errs = gen.errs.AddErrorf("more than one component match %+v", dep)
+		}
+		return name
+	}
+
+	var elems []string
+	for j, com := range coms {
+		elem := fmt.Sprintf("%vc%d", name, j)
+		gen.writeGet(w, gp, com, elem)
+		elems = append(elems, elem)
+	}
+	_, _ = fmt.Fprintf(w, "%v := []%v{%v}\n", name, gen.typeExpr(dep.Type()), strings.Join(elems, ", "))
+	return name
+}
+
+func (gen *generator) writeGet(w io.Writer, gp *genProvider, com model.Component, name string) {
+	gc, ok := gen.componentOf[com]
+	if !ok {
+		gen.errs = gen.errs.AddErrors(errors.Bugf("unknown component %+v", com))
+		return
+	}
+
+	receiver := "s"
+	found := false
+	for s := gp.scope; s != nil; s = s.parent {
+		if s == gc.scope {
+			found = true
+			break
+		}
+		receiver += ".parent"
+	}
+	if !found {
+		gen.errs = gen.errs.AddErrorf("%+v is not in the scope of %+v or its ancestors", com, gp.provider)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "%v, err := %v.%v()\nif err != nil {\nreturn err\n}\n", name, receiver, gc.getter)
+}
+
+func (gen *generator) writeFuncCall(w io.Writer, gp *genProvider, deps []model.Dependency, args []string) {
+	funcVal, _ := model.FuncOfProvider(gp.provider)
+	funcType := funcVal.Type()
+	if funcType.IsVariadic() && len(args) > 0 {
+		args[len(args)-1] += "..."
+	}
+	if len(args) != funcType.NumIn() || len(deps) != funcType.NumIn() {
+		gen.errs = gen.errs.AddErrorf("parameters of %+v are not all injected", gp.provider)
+	}
+
+	stored := map[int]struct{}{}
+	for _, gc := range gp.components {
+		index, _ := model.ReturnIndexOfComponent(gc.com)
+		stored[index] = struct{}{}
+	}
+	results := make([]string, funcType.NumOut())
+	for i := range results {
+		if reflecting.IsErrorType(funcType.Out(i)) {
+			results[i] = fmt.Sprintf("e%d", i)
+		} else if _, ok := stored[i]; ok {
+			results[i] = fmt.Sprintf("r%d", i)
+		} else {
+			results[i] = "_"
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%v := %v(%v)\n", strings.Join(results, ", "), gen.funcExpr(funcVal),
+		strings.Join(args, ", "))
+
+	for i, r := range results {
+		if reflecting.IsErrorType(funcType.Out(i)) {
+			gen.useFmt = true
+			_, _ = fmt.Fprintf(w, "if %v != nil {\nreturn fmt.Errorf(\"%%v: %%w\", %q, %v)\n}\n",
+				r, fmt.Sprintf("%v", gp.provider), r)
+		}
+	}
+	for _, gc := range gp.components {
+		index, _ := model.ReturnIndexOfComponent(gc.com)
+		_, _ = fmt.Fprintf(w, "s.%v = %v\n", gc.field, results[index])
+	}
+}
+
+func (gen *generator) writeStruct(w io.Writer, gp *genProvider, st reflect.Type, deps []model.Dependency,
+	args []string) {
+	structType := st
+	prefix := ""
+	if st.Kind() == reflect.Ptr {
+		structType = st.Elem()
+		prefix = "&"
+	}
+
+	var fields []string
+	for i, dep := range deps {
+		field, _ := model.FieldOfDependency(dep)
+		if !token.IsExported(field.Name) && structType.PkgPath() != gen.opts.PkgPath {
+			gen.errs = gen.errs.AddErrorf("field %v of %v is unexported", field.Name, structType)
+		}
+		fields = append(fields, fmt.Sprintf("%v: %v", field.Name, args[i]))
+	}
+	_, _ = fmt.Fprintf(w, "s.%v = %v%v{%v}\n", gp.components[0].field, prefix, gen.typeExpr(structType),
+		strings.Join(fields, ", "))
+}
+
+func (gen *generator) typeOfComponent(gc *genComponent) string {
+	if gc.typeExpr == "" {
+		gc.typeExpr = gen.typeExpr(gc.com.Type())
+	}
+	return gc.typeExpr
+}
+
+// literalOf returns the literal of values of basic kinds
+func (gen *generator) literalOf(val reflect.Value) string {
+	var lit string
+	switch val.Kind() {
+	case reflect.Bool:
+		lit = strconv.FormatBool(val.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit = strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lit = strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		lit = strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits())
+	case reflect.String:
+		lit = strconv.Quote(val.String())
+	default:
+		gen.errs = gen.errs.AddErrorf("value of [%v] can not be generated, provide it by a function",
+			val.Type())
+		return "nil"
+	}
+
+	t := val.Type()
+	switch {
+	case t.PkgPath() == "" && (t.Kind() == reflect.Bool || t.Kind() == reflect.Int || t.Kind() == reflect.String):
+		return lit
+	default:
+		return fmt.Sprintf("%v(%v)", gen.typeExpr(t), lit)
+	}
+}
+
+// funcExpr returns the name of the function qualified by its package
+func (gen *generator) funcExpr(funcVal reflect.Value) string {
+	f := runtime.FuncForPC(funcVal.Pointer())
+	if f == nil {
+		gen.errs = gen.errs.AddErrorf("can not find the name of %v", funcVal.Type())
+		return "nil"
+	}
+
+	fullName := f.Name()
+	slash := strings.LastIndex(fullName, "/")
+	dot := strings.Index(fullName[slash+1:], ".") + slash + 1
+	pkgPath := strings.ReplaceAll(fullName[:dot], "%2e", ".")
+	name := fullName[dot+1:]
+	if !token.IsIdentifier(name) {
+		gen.errs = gen.errs.AddErrorf("%v is not a function declared at package level", fullName)
+		return "nil"
+	}
+	return gen.qualified(pkgPath, defaultPackageName(pkgPath), name)
+}
+
+func (gen *generator) qualified(pkgPath string, pkgName string, name string) string {
+	if pkgPath == gen.opts.PkgPath {
+		return name
+	}
+	if !token.IsExported(name) {
+		gen.errs = gen.errs.AddErrorf("%v.%v is unexported", pkgPath, name)
+	}
+
+	alias, ok := gen.imports[pkgPath]
+	if !ok {
+		alias = uniqueName(pkgName, gen.aliases)
+		gen.aliases[alias] = struct{}{}
+		gen.imports[pkgPath] = alias
+		if alias == pkgName {
+			gen.knownNames[pkgPath] = struct{}{}
+		}
+	}
+	return alias + "." + name
+}
+
+func (gen *generator) typeExpr(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		if strings.Contains(t.Name(), "[") {
+			gen.errs = gen.errs.AddErrorf("generic type %v is not supported", t)
+			return t.Name()
+		}
+		pkgName := strings.SplitN(t.String(), ".", 2)[0]
+		expr := gen.qualified(t.PkgPath(), pkgName, t.Name())
+		if _, ok := gen.imports[t.PkgPath()]; ok && gen.imports[t.PkgPath()] == pkgName {
+			gen.knownNames[t.PkgPath()] = struct{}{}
+		}
+		return expr
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + gen.typeExpr(t.Elem())
+	case reflect.Slice:
+		return "[]" + gen.typeExpr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%v", t.Len(), gen.typeExpr(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%v]%v", gen.typeExpr(t.Key()), gen.typeExpr(t.Elem()))
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + gen.typeExpr(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + gen.typeExpr(t.Elem())
+		default:
+			return "chan " + gen.typeExpr(t.Elem())
+		}
+	case reflect.Func:
+		var in, out []string
+		for i := 0; i < t.NumIn(); i++ {
+			if i == t.NumIn()-1 && t.IsVariadic() {
+				in = append(in, "..."+gen.typeExpr(t.In(i).Elem()))
+			} else {
+				in = append(in, gen.typeExpr(t.In(i)))
+			}
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			out = append(out, gen.typeExpr(t.Out(i)))
+		}
+		expr := fmt.Sprintf("func(%v)", strings.Join(in, ", "))
+		if len(out) == 1 {
+			expr += " " + out[0]
+		} else if len(out) > 1 {
+			expr += fmt.Sprintf(" (%v)", strings.Join(out, ", "))
+		}
+		return expr
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}"
+		}
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			return "struct{}"
+		}
+	}
+
+	gen.errs = gen.errs.AddErrorf("type %v is not supported", t)
+	return "interface{}"
+}
+
+// defaultPackageName guesses the name of the package from its path
+func defaultPackageName(pkgPath string) string {
+	name := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(strings.TrimSuffix(name, "-go"), "go-")
+	return identifierOf(name, "pkg", false)
+}
+
+func identifierOf(name string, fallback string, upper bool) string {
+	sb := strings.Builder{}
+	nextUpper := upper
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			nextUpper = upper || sb.Len() > 0
+			continue
+		}
+		if nextUpper {
+			r = unicode.ToUpper(r)
+			nextUpper = false
+		}
+		sb.WriteRune(r)
+	}
+	res := sb.String()
+	if res == "" || unicode.IsDigit([]rune(res)[0]) {
+		res = fallback + res
+	}
+	if token.Lookup(res).IsKeyword() {
+		res += "_"
+	}
+	return res
+}
+
+func exportedName(name string, fallback string) string {
+	return identifierOf(name, fallback, true)
+}
+
+func unexportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func uniqueName(name string, used map[string]struct{}) string {
+	res := name
+	for i := 2; ; i++ {
+		if _, ok := used[res]; !ok {
+			return res
+		}
+		res = fmt.Sprintf("%v%d", name, i)
+	}
+}
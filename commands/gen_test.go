@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"flag"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jison/uni/commands/internal/genexample"
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+const genexampleFile = "internal/genexample/uni_gen.go"
+
+func TestGenerate_golden(t *testing.T) {
+	src, err := Generate(genexample.Module(), GenOptions{
+		Package: "genexample",
+		PkgPath: "github.com/jison/uni/commands/internal/genexample",
+	})
+	assert.Nil(t, err)
+
+	if *update {
+		assert.Nil(t, os.WriteFile(genexampleFile, src, 0644))
+	}
+	golden, err := os.ReadFile(genexampleFile)
+	assert.Nil(t, err)
+	assert.Equal(t, string(golden), string(src), "run go test -update to update %v", genexampleFile)
+}
+
+func pluginNames(plugins []genexample.Plugin) []string {
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGenerate_matchesContainer(t *testing.T) {
+	c, err := core.NewContainer(genexample.Module())
+	assert.Nil(t, err)
+	gc := genexample.NewContainer()
+
+	t.Run("global", func(t *testing.T) {
+		val, err := core.ValueOf(c, &genexample.Server{})
+		assert.Nil(t, err)
+		expected := val.(*genexample.Server)
+
+		actual, err := gc.Server()
+		assert.Nil(t, err)
+		assert.Equal(t, expected.DB, actual.DB)
+		assert.Equal(t, expected.Metrics, actual.Metrics)
+		assert.Equal(t, expected.Logger.Log("a"), actual.Logger.Log("a"))
+		assert.Equal(t, pluginNames(expected.Plugins), pluginNames(actual.Plugins))
+
+		again, _ := gc.Server()
+		assert.Same(t, actual, again)
+		db, _ := gc.DB()
+		assert.Same(t, actual.DB, db)
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		rc, err := c.EnterScope(genexample.RequestScope,
+			core.NamedSeed("path", "/a"), core.NamedSeed("user", "admin"))
+		assert.Nil(t, err)
+		val, err := core.ValueOf(rc, &genexample.Handler{})
+		assert.Nil(t, err)
+		expected := val.(*genexample.Handler)
+
+		grc := gc.EnterRequest("/a", "admin")
+		actual, err := grc.Handler()
+		assert.Nil(t, err)
+		assert.Equal(t, expected.Path, actual.Path)
+		assert.Equal(t, expected.User, actual.User)
+
+		server, _ := gc.Server()
+		assert.Same(t, server, actual.Server)
+		other, _ := gc.EnterRequest("/b", "admin").Handler()
+		assert.NotSame(t, actual, other)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rc, _ := c.EnterScope(genexample.RequestScope,
+			core.NamedSeed("path", "/a"), core.NamedSeed("user", ""))
+		_, expected := core.ValueOf(rc, &genexample.Handler{})
+		assert.NotNil(t, expected)
+		assert.Contains(t, expected.Error(), "anonymous user visits /a")
+
+		_, actual := gc.EnterRequest("/a", "").Handler()
+		assert.NotNil(t, actual)
+		assert.Contains(t, actual.Error(), "anonymous user visits /a")
+	})
+
+	t.Run("close", func(t *testing.T) {
+		val, _ := core.ValueOf(c, &genexample.DB{})
+		db, _ := gc.DB()
+		assert.Nil(t, c.Close())
+		assert.Nil(t, gc.Close())
+		assert.True(t, db.Closed)
+		assert.Equal(t, val.(*genexample.DB).Closed, db.Closed)
+
+		// closed components are created again
+		val2, _ := core.ValueOf(c, &genexample.DB{})
+		db2, _ := gc.DB()
+		assert.NotSame(t, val, val2)
+		assert.NotSame(t, db, db2)
+		assert.Equal(t, val2.(*genexample.DB).Closed, db2.Closed)
+	})
+}
+
+func TestGenerate_errors(t *testing.T) {
+	opts := GenOptions{Package: "test"}
+	generate := func(m model.Module) error {
+		_, err := Generate(m, opts)
+		return err
+	}
+
+	type dependency struct{}
+	type testStruct struct {
+		d dependency
+	}
+
+	t.Run("package name is empty", func(t *testing.T) {
+		_, err := Generate(model.NewModule(), GenOptions{})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid module", func(t *testing.T) {
+		err := generate(model.NewModule(model.Func(func(d dependency) int { return 0 })))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "can not build the graph of the module")
+	})
+
+	t.Run("anonymous function", func(t *testing.T) {
+		err := generate(model.NewModule(model.Func(func() int { return 0 })))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not a function declared at package level")
+	})
+
+	t.Run("value of struct", func(t *testing.T) {
+		err := generate(model.NewModule(model.Value(dependency{})))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "provide it by a function")
+	})
+
+	t.Run("unexported", func(t *testing.T) {
+		err := generate(model.NewModule(model.Value(time.Second), model.Func(time.NewTimer)))
+		assert.Nil(t, err)
+
+		err = generate(model.NewModule(model.Func(strings.NewReader, model.Param(0, model.Optional(true))),
+			model.Struct(&testStruct{}, model.Field("d", model.Optional(true)))))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is unexported")
+	})
+
+	t.Run("scope with parents", func(t *testing.T) {
+		s1 := model.NewScope("s1")
+		s2 := model.NewScope("s2")
+		s3 := model.NewScope("s3", s1, s2)
+		err := generate(model.NewModule(model.Value(1, model.InScope(s3))))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "only scopes with one parent are supported")
+	})
+
+	t.Run("cache policy", func(t *testing.T) {
+		err := generate(model.NewModule(model.Func(time.Now, model.Cache(model.CachePolicy{TTL: time.Second}))))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cache policy")
+	})
+}
+
+func TestGenerate_qualified(t *testing.T) {
+	src, err := Generate(model.NewModule(
+		model.Value(time.Second),
+		model.Func(time.NewTimer),
+		model.Func(strings.NewReader, model.Param(0, model.Optional(true))),
+	), GenOptions{Package: "wiring", ContainerName: "App"})
+	assert.Nil(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "package wiring")
+	assert.Contains(t, code, `"strings"`)
+	assert.Contains(t, code, `"time"`)
+	assert.Contains(t, code, "time.Duration(1000000000)")
+	assert.Contains(t, code, "time.NewTimer(d0)")
+	assert.Contains(t, code, "func NewApp() *App")
+	assert.Contains(t, code, "func (s *App) Timer() (v *time.Timer, err error)")
+}
+
+func TestGenDriver(t *testing.T) {
+	src, err := GenDriver("example.com/app", "Module", GenOptions{Package: "app"}, "/tmp/uni_gen.go")
+	assert.Nil(t, err)
+	assert.Contains(t, string(src), `target "example.com/app"`)
+	assert.Contains(t, string(src), "commands.GenerateFile(target.Module, opts, \"/tmp/uni_gen.go\")")
+
+	_, err = GenDriver("example.com/app", "module", GenOptions{}, "uni_gen.go")
+	assert.NotNil(t, err)
+}
+
+func TestModuleOf(t *testing.T) {
+	m := model.NewModule()
+	res, err := ModuleOf(m)
+	assert.Nil(t, err)
+	assert.Equal(t, m, res)
+
+	res, err = ModuleOf(func() model.Module { return m })
+	assert.Nil(t, err)
+	assert.Equal(t, m, res)
+
+	_, err = ModuleOf(1)
+	assert.NotNil(t, err)
+}
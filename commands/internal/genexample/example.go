@@ -0,0 +1,128 @@
+// Package genexample is a module wired by the code generated in uni_gen.go, which is compared
+// with the container by tests of the generator
+package genexample
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jison/uni/core/model"
+)
+
+//go:generate go run github.com/jison/uni/commands/cmd/uni gen -o uni_gen.go . Module
+
+type Port int
+
+type Config struct {
+	DSN  string
+	Port Port
+}
+
+type DB struct {
+	DSN    string
+	Closed bool
+}
+
+func (db *DB) Close() error {
+	db.Closed = true
+	return nil
+}
+
+func NewDB(cfg *Config) (*DB, error) {
+	if cfg.DSN == "" {
+		return nil, errors.New("dsn is empty")
+	}
+	return &DB{DSN: cfg.DSN}, nil
+}
+
+type Plugin interface {
+	Name() string
+}
+
+type authPlugin struct{}
+
+func (p *authPlugin) Name() string {
+	return "auth"
+}
+
+type cachePlugin struct {
+	DB *DB
+}
+
+func (p *cachePlugin) Name() string {
+	return "cache:" + p.DB.DSN
+}
+
+func newPlugins(db *DB) (*authPlugin, error, *cachePlugin) {
+	return &authPlugin{}, nil, &cachePlugin{DB: db}
+}
+
+type Logger interface {
+	Log(msg string) string
+}
+
+type prefixLogger struct {
+	prefix string
+}
+
+func (l *prefixLogger) Log(msg string) string {
+	return l.prefix + msg
+}
+
+func newLogger(port Port) *prefixLogger {
+	return &prefixLogger{prefix: fmt.Sprintf("[%d] ", port)}
+}
+
+// Metrics is not provided, it is an optional dependency
+type Metrics struct{}
+
+type Server struct {
+	DB      *DB
+	Logger  Logger
+	Plugins []Plugin
+	Metrics *Metrics
+}
+
+var RequestScope = model.NewScopeWith("request",
+	model.ScopeInput("", model.Name("path")),
+	model.ScopeInput("", model.Name("user")),
+)
+
+type Handler struct {
+	Server *Server
+	Path   string
+	User   string
+}
+
+func (h *Handler) Close() error {
+	h.Server.Logger.Log("closed " + h.Path)
+	return nil
+}
+
+func newHandler(s *Server, path string, user string, plugins ...Plugin) (*Handler, error) {
+	if user == "" {
+		return nil, fmt.Errorf("anonymous user visits %v", path)
+	}
+	return &Handler{Server: s, Path: path, User: user}, nil
+}
+
+// Module is the module generated to uni_gen.go
+func Module() model.Module {
+	return model.NewModule(
+		model.Value("postgres://localhost/app", model.Name("dsn")),
+		model.Value(Port(8080)),
+		model.Struct(&Config{}, model.Field("DSN", model.ByName("dsn"))),
+		model.Func(NewDB),
+		model.Func(newPlugins, model.Return(0, model.As((*Plugin)(nil))),
+			model.Return(2, model.As((*Plugin)(nil)))),
+		model.Func(newLogger),
+		model.Bind((*Logger)(nil), &prefixLogger{}),
+		model.Struct(&Server{},
+			model.Field("Plugins", model.AsCollector(true)),
+			model.Field("Metrics", model.Optional(true))),
+		model.Func(newHandler, model.InScope(RequestScope),
+			model.Param(1, model.ByName("path")),
+			model.Param(2, model.ByName("user")),
+			model.Param(3, model.AsCollector(true))),
+	)
+}
@@ -0,0 +1,426 @@
+// Code generated by uni gen. DO NOT EDIT.
+
+//go:build !unigen
+// +build !unigen
+
+package genexample
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Container creates and caches components in the global scope
+type Container struct {
+	p0mu      sync.Mutex
+	p0done    bool
+	p0r0      string
+	p1mu      sync.Mutex
+	p1done    bool
+	p1r0      Port
+	p2mu      sync.Mutex
+	p2done    bool
+	p2r0      *Config
+	p3mu      sync.Mutex
+	p3done    bool
+	p3r0      *DB
+	p4mu      sync.Mutex
+	p4done    bool
+	p4r0      *authPlugin
+	p4r2      *cachePlugin
+	p5mu      sync.Mutex
+	p5done    bool
+	p5r0      *prefixLogger
+	p6mu      sync.Mutex
+	p6done    bool
+	p6r0      Logger
+	p7mu      sync.Mutex
+	p7done    bool
+	p7r0      *Server
+	closersMu sync.Mutex
+	closers   []io.Closer
+}
+
+// NewContainer creates the container of the global scope
+func NewContainer() *Container {
+	return &Container{}
+}
+
+// Dsn returns Component[string]{name="dsn"}
+func (s *Container) Dsn() (v string, err error) {
+	if err = s.provide0(); err == nil {
+		v = s.p0r0
+	}
+	return
+}
+
+// Port returns Component[genexample.Port]
+func (s *Container) Port() (v Port, err error) {
+	if err = s.provide1(); err == nil {
+		v = s.p1r0
+	}
+	return
+}
+
+// Config returns Component[*genexample.Config]
+func (s *Container) Config() (v *Config, err error) {
+	if err = s.provide2(); err == nil {
+		v = s.p2r0
+	}
+	return
+}
+
+// DB returns Component[*genexample.DB]
+func (s *Container) DB() (v *DB, err error) {
+	if err = s.provide3(); err == nil {
+		v = s.p3r0
+	}
+	return
+}
+
+// AuthPlugin returns Component[*genexample.authPlugin]{as={genexample.Plugin}}
+func (s *Container) AuthPlugin() (v *authPlugin, err error) {
+	if err = s.provide4(); err == nil {
+		v = s.p4r0
+	}
+	return
+}
+
+// CachePlugin returns Component[*genexample.cachePlugin]{as={genexample.Plugin}}
+func (s *Container) CachePlugin() (v *cachePlugin, err error) {
+	if err = s.provide4(); err == nil {
+		v = s.p4r2
+	}
+	return
+}
+
+// PrefixLogger returns Component[*genexample.prefixLogger]
+func (s *Container) PrefixLogger() (v *prefixLogger, err error) {
+	if err = s.provide5(); err == nil {
+		v = s.p5r0
+	}
+	return
+}
+
+// Logger returns Component[genexample.Logger]
+func (s *Container) Logger() (v Logger, err error) {
+	if err = s.provide6(); err == nil {
+		v = s.p6r0
+	}
+	return
+}
+
+// Server returns Component[*genexample.Server]
+func (s *Container) Server() (v *Server, err error) {
+	if err = s.provide7(); err == nil {
+		v = s.p7r0
+	}
+	return
+}
+
+func (s *Container) track(v interface{}) {
+	if closer, ok := v.(io.Closer); ok {
+		s.closersMu.Lock()
+		s.closers = append(s.closers, closer)
+		s.closersMu.Unlock()
+	}
+}
+
+// Close closes components implementing io.Closer which are created in the scope, the latest
+// created first, and returns the first error. Components are created again if they are got later
+func (s *Container) Close() error {
+	s.closersMu.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.closersMu.Unlock()
+	s.p0mu.Lock()
+	s.p0done = false
+	s.p0mu.Unlock()
+	s.p1mu.Lock()
+	s.p1done = false
+	s.p1mu.Unlock()
+	s.p2mu.Lock()
+	s.p2done = false
+	s.p2mu.Unlock()
+	s.p3mu.Lock()
+	s.p3done = false
+	s.p3mu.Unlock()
+	s.p4mu.Lock()
+	s.p4done = false
+	s.p4mu.Unlock()
+	s.p5mu.Lock()
+	s.p5done = false
+	s.p5mu.Unlock()
+	s.p6mu.Lock()
+	s.p6done = false
+	s.p6mu.Unlock()
+	s.p7mu.Lock()
+	s.p7done = false
+	s.p7mu.Unlock()
+
+	var err error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if closeErr := closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// RequestContainer creates and caches components in the scope `request`
+type RequestContainer struct {
+	parent    *Container
+	in0       string
+	in1       string
+	p8mu      sync.Mutex
+	p8done    bool
+	p8r0      *Handler
+	closersMu sync.Mutex
+	closers   []io.Closer
+}
+
+// EnterRequest enters the scope `request` with its inputs
+func (s *Container) EnterRequest(path string, user string) *RequestContainer {
+	return &RequestContainer{parent: s, in0: path, in1: user}
+}
+
+// Path returns the input Component[string]{name="path"}
+func (s *RequestContainer) Path() (string, error) {
+	return s.in0, nil
+}
+
+// User returns the input Component[string]{name="user"}
+func (s *RequestContainer) User() (string, error) {
+	return s.in1, nil
+}
+
+// Handler returns Component[*genexample.Handler]
+func (s *RequestContainer) Handler() (v *Handler, err error) {
+	if err = s.provide8(); err == nil {
+		v = s.p8r0
+	}
+	return
+}
+
+func (s *RequestContainer) track(v interface{}) {
+	if closer, ok := v.(io.Closer); ok {
+		s.closersMu.Lock()
+		s.closers = append(s.closers, closer)
+		s.closersMu.Unlock()
+	}
+}
+
+// Close closes components implementing io.Closer which are created in the scope, the latest
+// created first, and returns the first error. Components are created again if they are got later
+func (s *RequestContainer) Close() error {
+	s.closersMu.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.closersMu.Unlock()
+	s.p8mu.Lock()
+	s.p8done = false
+	s.p8mu.Unlock()
+
+	var err error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if closeErr := closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// provide0 creates components of Value[string](postgres://localhost/app) in Global
+func (s *Container) provide0() error {
+	s.p0mu.Lock()
+	defer s.p0mu.Unlock()
+	if s.p0done {
+		return nil
+	}
+	s.p0r0 = "postgres://localhost/app"
+	s.track(s.p0r0)
+	s.p0done = true
+	return nil
+}
+
+// provide1 creates components of Value[genexample.Port](8080) in Global
+func (s *Container) provide1() error {
+	s.p1mu.Lock()
+	defer s.p1mu.Unlock()
+	if s.p1done {
+		return nil
+	}
+	s.p1r0 = Port(8080)
+	s.track(s.p1r0)
+	s.p1done = true
+	return nil
+}
+
+// provide2 creates components of Struct[*genexample.Config] in Global
+func (s *Container) provide2() error {
+	s.p2mu.Lock()
+	defer s.p2mu.Unlock()
+	if s.p2done {
+		return nil
+	}
+	d0, err := s.Dsn()
+	if err != nil {
+		return err
+	}
+	d1, err := s.Port()
+	if err != nil {
+		return err
+	}
+	s.p2r0 = &Config{DSN: d0, Port: d1}
+	s.track(s.p2r0)
+	s.p2done = true
+	return nil
+}
+
+// provide3 creates components of Function[func(*genexample.Config) (*genexample.DB, error)] in Global
+func (s *Container) provide3() error {
+	s.p3mu.Lock()
+	defer s.p3mu.Unlock()
+	if s.p3done {
+		return nil
+	}
+	d0, err := s.Config()
+	if err != nil {
+		return err
+	}
+	r0, e1 := NewDB(d0)
+	if e1 != nil {
+		return fmt.Errorf("%v: %w", "Function[func(*genexample.Config) (*genexample.DB, error)] in Global", e1)
+	}
+	s.p3r0 = r0
+	s.track(s.p3r0)
+	s.p3done = true
+	return nil
+}
+
+// provide4 creates components of Function[func(*genexample.DB) (*genexample.authPlugin, error, *genexample.cachePlugin)] in Global
+func (s *Container) provide4() error {
+	s.p4mu.Lock()
+	defer s.p4mu.Unlock()
+	if s.p4done {
+		return nil
+	}
+	d0, err := s.DB()
+	if err != nil {
+		return err
+	}
+	r0, e1, r2 := newPlugins(d0)
+	if e1 != nil {
+		return fmt.Errorf("%v: %w", "Function[func(*genexample.DB) (*genexample.authPlugin, error, *genexample.cachePlugin)] in Global", e1)
+	}
+	s.p4r0 = r0
+	s.p4r2 = r2
+	s.track(s.p4r0)
+	s.track(s.p4r2)
+	s.p4done = true
+	return nil
+}
+
+// provide5 creates components of Function[func(genexample.Port) *genexample.prefixLogger] in Global
+func (s *Container) provide5() error {
+	s.p5mu.Lock()
+	defer s.p5mu.Unlock()
+	if s.p5done {
+		return nil
+	}
+	d0, err := s.Port()
+	if err != nil {
+		return err
+	}
+	r0 := newLogger(d0)
+	s.p5r0 = r0
+	s.track(s.p5r0)
+	s.p5done = true
+	return nil
+}
+
+// provide6 creates components of Bind[genexample.Logger](*genexample.prefixLogger) in Global
+func (s *Container) provide6() error {
+	s.p6mu.Lock()
+	defer s.p6mu.Unlock()
+	if s.p6done {
+		return nil
+	}
+	d0, err := s.PrefixLogger()
+	if err != nil {
+		return err
+	}
+	s.p6r0 = d0
+	s.p6done = true
+	return nil
+}
+
+// provide7 creates components of Struct[*genexample.Server] in Global
+func (s *Container) provide7() error {
+	s.p7mu.Lock()
+	defer s.p7mu.Unlock()
+	if s.p7done {
+		return nil
+	}
+	d0, err := s.DB()
+	if err != nil {
+		return err
+	}
+	d1, err := s.Logger()
+	if err != nil {
+		return err
+	}
+	d2c0, err := s.AuthPlugin()
+	if err != nil {
+		return err
+	}
+	d2c1, err := s.CachePlugin()
+	if err != nil {
+		return err
+	}
+	d2 := []Plugin{d2c0, d2c1}
+	var d3 *Metrics
+	s.p7r0 = &Server{DB: d0, Logger: d1, Plugins: d2, Metrics: d3}
+	s.track(s.p7r0)
+	s.p7done = true
+	return nil
+}
+
+// provide8 creates components of Function[func(*genexample.Server, string, string, ...genexample.Plugin) (*genexample.Handler, error)] in request
+func (s *RequestContainer) provide8() error {
+	s.p8mu.Lock()
+	defer s.p8mu.Unlock()
+	if s.p8done {
+		return nil
+	}
+	d0, err := s.parent.Server()
+	if err != nil {
+		return err
+	}
+	d1, err := s.Path()
+	if err != nil {
+		return err
+	}
+	d2, err := s.User()
+	if err != nil {
+		return err
+	}
+	d3c0, err := s.parent.AuthPlugin()
+	if err != nil {
+		return err
+	}
+	d3c1, err := s.parent.CachePlugin()
+	if err != nil {
+		return err
+	}
+	d3 := []Plugin{d3c0, d3c1}
+	r0, e1 := newHandler(d0, d1, d2, d3...)
+	if e1 != nil {
+		return fmt.Errorf("%v: %w", "Function[func(*genexample.Server, string, string, ...genexample.Plugin) (*genexample.Handler, error)] in request", e1)
+	}
+	s.p8r0 = r0
+	s.track(s.p8r0)
+	s.p8done = true
+	return nil
+}
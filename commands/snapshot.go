@@ -0,0 +1,397 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+)
+
+// Snapshot is the canonical form of a dependence graph. Components are identified by their types,
+// names, tags and scopes, and providers by their kinds and components, rather than addresses or
+// locations, so snapshots of the same module are identical between builds, and can be compared by
+// Diff. Components with the same identity are told apart by functions or types of their providers,
+// anonymous functions by their signatures, since the runtime numbers them in the order of the source.
+type Snapshot struct {
+	Providers []SnapshotProvider `json:"providers"`
+}
+
+type SnapshotProvider struct {
+	ID           string               `json:"id"`
+	Kind         string               `json:"kind"`
+	Package      string               `json:"package,omitempty"`
+	Scope        string               `json:"scope"`
+	Components   []SnapshotComponent  `json:"components"`
+	Dependencies []SnapshotDependency `json:"dependencies,omitempty"`
+}
+
+type SnapshotComponent struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type"`
+	Name   string   `json:"name,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	As     []string `json:"as,omitempty"`
+	Hidden bool     `json:"hidden,omitempty"`
+}
+
+type SnapshotDependency struct {
+	// Key is the parameter or the field of the dependency in the provider
+	Key       string   `json:"key"`
+	Type      string   `json:"type"`
+	Name      string   `json:"name,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Optional  bool     `json:"optional,omitempty"`
+	Collector bool     `json:"collector,omitempty"`
+	// Matches are IDs of components matching the dependency
+	Matches []string `json:"matches"`
+}
+
+// Missing returns true if no component matches the dependency which is required
+func (d *SnapshotDependency) Missing() bool {
+	return len(d.Matches) == 0 && !d.Optional && !d.Collector
+}
+
+// Ambiguous returns true if more than one component match the dependency which is not a collector
+func (d *SnapshotDependency) Ambiguous() bool {
+	return len(d.Matches) > 1 && !d.Collector
+}
+
+// SnapshotOf builds the snapshot of the module, missing, uncertain and cyclic dependencies are
+// recorded rather than reported
+func SnapshotOf(m model.Module, opts ...core.ContainerOption) (*Snapshot, error) {
+	opts = append([]core.ContainerOption{core.IgnoreMissing(), core.IgnoreUncertain(), core.IgnoreCycle()},
+		opts...)
+	g, err := core.NewDependenceGraph(m, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return SnapshotOfGraph(g), nil
+}
+
+// SnapshotOfGraph builds the snapshot of the graph
+func SnapshotOfGraph(g core.DependenceGraph) *Snapshot {
+	var providers []model.Provider
+	g.Nodes().Each(func(node core.Node) {
+		if p, ok := g.ProviderOfNode(node); ok {
+			providers = append(providers, p)
+		}
+	})
+	// the order of providers with the same ID is the order in the module
+	sort.SliceStable(providers, func(i, j int) bool {
+		return locationKey(providers[i]) < locationKey(providers[j])
+	})
+
+	s := &Snapshot{}
+	var coms []model.Component
+	for _, p := range providers {
+		sp := SnapshotProvider{
+			Kind:    providerKind(p),
+			Package: providerPackage(p),
+			Scope:   p.Scope().Name(),
+		}
+		p.Components().Each(func(com model.Component) {
+			if !com.Ignored() {
+				coms = append(coms, com)
+			}
+		})
+		s.Providers = append(s.Providers, sp)
+	}
+	comIDs := componentIDs(coms)
+
+	usedProviderIDs := map[string]int{}
+	for i, p := range providers {
+		sp := &s.Providers[i]
+		var ids []string
+		p.Components().Each(func(com model.Component) {
+			if com.Ignored() {
+				return
+			}
+			sc := SnapshotComponent{
+				ID:     comIDs[com],
+				Type:   typeString(com.Type()),
+				Name:   com.Name(),
+				Tags:   tagStrings(com.Tags()),
+				Hidden: com.Hidden(),
+			}
+			com.As().Iterate(func(t reflect.Type) bool {
+				sc.As = append(sc.As, typeString(t))
+				return true
+			})
+			sort.Strings(sc.As)
+			sp.Components = append(sp.Components, sc)
+			ids = append(ids, sc.ID)
+		})
+		sort.Slice(sp.Components, func(i, j int) bool { return sp.Components[i].ID < sp.Components[j].ID })
+
+		// providers are identified by their components, which are unique
+		if len(ids) > 0 {
+			sort.Strings(ids)
+			sp.ID = fmt.Sprintf("%v %v", sp.Kind, strings.Join(ids, ", "))
+		} else {
+			sp.ID = uniqueID(fmt.Sprintf("%v %v in %v", sp.Kind, providerIdentity(p), sp.Scope), usedProviderIDs)
+		}
+	}
+
+	for i, p := range providers {
+		sp := &s.Providers[i]
+		index := 0
+		p.Dependencies().Iterate(func(dep model.Dependency) bool {
+			sd := SnapshotDependency{
+				Key:       dependencyKey(dep, index),
+				Type:      typeString(dep.Type()),
+				Name:      dep.Name(),
+				Tags:      tagStrings(dep.Tags()),
+				Optional:  dep.Optional(),
+				Collector: dep.IsCollector(),
+				Matches:   []string{},
+			}
+			index += 1
+			g.InputComponentsToDependency(dep).Each(func(com model.Component) {
+				if id, ok := comIDs[com]; ok {
+					sd.Matches = append(sd.Matches, id)
+				}
+			})
+			sort.Strings(sd.Matches)
+			sp.Dependencies = append(sp.Dependencies, sd)
+			return true
+		})
+		sort.Slice(sp.Dependencies, func(i, j int) bool { return sp.Dependencies[i].Key < sp.Dependencies[j].Key })
+	}
+
+	sort.Slice(s.Providers, func(i, j int) bool { return s.Providers[i].ID < s.Providers[j].ID })
+	return s
+}
+
+// MarshalSnapshot returns the indented JSON of the snapshot, ended with a newline
+func MarshalSnapshot(s *Snapshot) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// UnmarshalSnapshot parses the JSON of a snapshot
+func UnmarshalSnapshot(data []byte) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.Newf("can not parse the snapshot").AddErrors(err)
+	}
+	return s, nil
+}
+
+// componentIDs identifies components by their types, names, tags and scopes, components with the
+// same identity are told apart by their providers, so IDs do not depend on the order of components
+func componentIDs(coms []model.Component) map[model.Component]string {
+	byID := map[string][]model.Component{}
+	for _, com := range coms {
+		id := criteriaID(typeString(com.Type()), com.Name(), tagStrings(com.Tags()))
+		if com.Hidden() {
+			id += " hidden"
+		}
+		id += " in " + com.Provider().Scope().Name()
+		byID[id] = append(byID[id], com)
+	}
+
+	ids := map[model.Component]string{}
+	for id, same := range byID {
+		if len(same) == 1 {
+			ids[same[0]] = id
+			continue
+		}
+
+		sort.SliceStable(same, func(i, j int) bool {
+			return providerIdentity(same[i].Provider()) < providerIdentity(same[j].Provider())
+		})
+		used := map[string]int{}
+		for _, com := range same {
+			ids[com] = uniqueID(fmt.Sprintf("%v from %v", id, providerIdentity(com.Provider())), used)
+		}
+	}
+	return ids
+}
+
+func uniqueID(id string, used map[string]int) string {
+	used[id] += 1
+	if n := used[id]; n > 1 {
+		return fmt.Sprintf("%v #%d", id, n)
+	}
+	return id
+}
+
+func criteriaID(typ string, name string, tags []string) string {
+	id := typ
+	if name != "" {
+		id += fmt.Sprintf(" name=%q", name)
+	}
+	if len(tags) > 0 {
+		id += fmt.Sprintf(" tags=[%v]", strings.Join(tags, ", "))
+	}
+	return id
+}
+
+func providerKind(p model.Provider) string {
+	if _, ok := model.FuncOfProvider(p); ok {
+		return "func"
+	}
+	if _, ok := model.StructTypeOfProvider(p); ok {
+		return "struct"
+	}
+	if _, ok := model.ValueOfProvider(p); ok {
+		return "value"
+	}
+	if model.IsBindProvider(p) {
+		return "bind"
+	}
+	if model.IsScopeInputProvider(p) {
+		return "input"
+	}
+	return "provider"
+}
+
+// closurePattern matches names of anonymous functions given by the runtime, like "a/b.init.func3",
+// which are numbered in the order of the source
+var closurePattern = regexp.MustCompile(`\.func\d+(\.\d+)*$|\.glob\.\.`)
+
+// providerIdentity is the name of the named function, the signature of the anonymous function with
+// names and tags of its components, the type of the struct, or types of the components
+func providerIdentity(p model.Provider) string {
+	if fn, ok := model.FuncOfProvider(p); ok {
+		if f := runtime.FuncForPC(fn.Pointer()); f != nil && !closurePattern.MatchString(f.Name()) {
+			return f.Name()
+		}
+		id := typeString(fn.Type())
+		p.Components().Each(func(com model.Component) {
+			if com.Ignored() {
+				return
+			}
+			id += criteriaID("", com.Name(), tagStrings(com.Tags()))
+		})
+		return id
+	}
+	if t, ok := model.StructTypeOfProvider(p); ok {
+		return typeString(t)
+	}
+
+	var types []string
+	p.Components().Each(func(com model.Component) {
+		types = append(types, typeString(com.Type()))
+	})
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
+
+func providerPackage(p model.Provider) string {
+	if fn, ok := model.FuncOfProvider(p); ok {
+		if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+			return funcPackage(f.Name())
+		}
+	}
+	if t, ok := model.StructTypeOfProvider(p); ok {
+		return typePackage(t)
+	}
+
+	pkg := ""
+	p.Components().Iterate(func(com model.Component) bool {
+		pkg = typePackage(com.Type())
+		return false
+	})
+	return pkg
+}
+
+// funcPackage returns the package of the full name of a function, like "a/b.c.func1"
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return name
+	}
+	return name[:slash+1+dot]
+}
+
+func typePackage(t reflect.Type) string {
+	for t.Name() == "" && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+func dependencyKey(dep model.Dependency, index int) string {
+	if i, ok := model.ParamIndexOfDependency(dep); ok {
+		return fmt.Sprintf("param %d", i)
+	}
+	if f, ok := model.FieldOfDependency(dep); ok {
+		return "field " + f.Name
+	}
+	return fmt.Sprintf("dependency %d", index)
+}
+
+func tagStrings(tags model.SymbolSet) []string {
+	var res []string
+	tags.Iterate(func(s model.Symbol) bool {
+		res = append(res, fmt.Sprintf("%v", s))
+		return true
+	})
+	sort.Strings(res)
+	return res
+}
+
+// typeString is like reflect.Type.String, but named types are qualified by their package paths
+func typeString(t reflect.Type) string {
+	if t == nil {
+		return "<nil>"
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return t.PkgPath() + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeString(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%v", t.Len(), typeString(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%v]%v", typeString(t.Key()), typeString(t.Elem()))
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + typeString(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + typeString(t.Elem())
+		default:
+			return "chan " + typeString(t.Elem())
+		}
+	case reflect.Func:
+		var in, out []string
+		for i := 0; i < t.NumIn(); i++ {
+			if i == t.NumIn()-1 && t.IsVariadic() {
+				in = append(in, "..."+typeString(t.In(i).Elem()))
+			} else {
+				in = append(in, typeString(t.In(i)))
+			}
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			out = append(out, typeString(t.Out(i)))
+		}
+		s := fmt.Sprintf("func(%v)", strings.Join(in, ", "))
+		if len(out) == 1 {
+			s += " " + out[0]
+		} else if len(out) > 1 {
+			s += fmt.Sprintf(" (%v)", strings.Join(out, ", "))
+		}
+		return s
+	}
+	return t.String()
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jison/uni/commands/internal/genexample"
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const genexampleSnapshotFile = "internal/genexample/snapshot.json"
+
+func TestSnapshotOf_golden(t *testing.T) {
+	s, err := SnapshotOf(genexample.Module())
+	assert.Nil(t, err)
+	data, err := MarshalSnapshot(s)
+	assert.Nil(t, err)
+
+	if *update {
+		assert.Nil(t, os.WriteFile(genexampleSnapshotFile, data, 0644))
+	}
+	golden, err := os.ReadFile(genexampleSnapshotFile)
+	assert.Nil(t, err)
+	assert.Equal(t, string(golden), string(data), "run go test -update to update %v", genexampleSnapshotFile)
+
+	// snapshots of different instances of the module are identical
+	s2, err := SnapshotOf(genexample.Module())
+	assert.Nil(t, err)
+	data2, err := MarshalSnapshot(s2)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), string(data2))
+}
+
+type snapshotDB struct{}
+
+type snapshotRepo struct {
+	DB *snapshotDB
+}
+
+type snapshotHandler struct{}
+
+func newSnapshotDB() *snapshotDB { return &snapshotDB{} }
+
+func newSnapshotHandler(r *snapshotRepo, name string) *snapshotHandler { return &snapshotHandler{} }
+
+func TestSnapshotOf(t *testing.T) {
+	tag := model.NewSymbol("primary")
+	request := model.NewScopeWith("request", model.ScopeInput(""))
+	s, err := SnapshotOf(model.NewModule(
+		model.Func(newSnapshotDB, model.Return(0, model.Name("db"), model.Tags(tag))),
+		model.Struct(&snapshotRepo{}, model.Field("DB", model.ByName("db"))),
+		model.Func(newSnapshotHandler, model.InScope(request)),
+		model.Value(1),
+		model.Value(2, model.Hide()),
+		model.Func(func(ints []int, s *snapshotRepo) float64 { return 0 },
+			model.Param(0, model.AsCollector(true))),
+	))
+	assert.Nil(t, err)
+
+	var ids []string
+	providers := map[string]SnapshotProvider{}
+	for _, p := range s.Providers {
+		ids = append(ids, p.ID)
+		providers[p.ID] = p
+	}
+	const pkg = "github.com/jison/uni/commands"
+	assert.Equal(t, []string{
+		"func *" + pkg + `.snapshotDB name="db" tags=[primary] in Global`,
+		"func *" + pkg + ".snapshotHandler in request",
+		"func float64 in Global",
+		"input string in request",
+		"struct *" + pkg + ".snapshotRepo in Global",
+		"value int hidden in Global",
+		"value int in Global",
+	}, ids)
+
+	db := providers["func *"+pkg+`.snapshotDB name="db" tags=[primary] in Global`]
+	assert.Equal(t, "func", db.Kind)
+	assert.Equal(t, pkg, db.Package)
+	assert.Equal(t, []SnapshotComponent{{
+		ID:   "*" + pkg + `.snapshotDB name="db" tags=[primary] in Global`,
+		Type: "*" + pkg + ".snapshotDB",
+		Name: "db",
+		Tags: []string{"primary"},
+	}}, db.Components)
+
+	repo := providers["struct *"+pkg+".snapshotRepo in Global"]
+	assert.Equal(t, []SnapshotDependency{{
+		Key:     "field DB",
+		Type:    "*" + pkg + ".snapshotDB",
+		Name:    "db",
+		Matches: []string{"*" + pkg + `.snapshotDB name="db" tags=[primary] in Global`},
+	}}, repo.Dependencies)
+
+	handler := providers["func *"+pkg+".snapshotHandler in request"]
+	assert.Equal(t, "param 1", handler.Dependencies[1].Key)
+	assert.Equal(t, []string{"string in request"}, handler.Dependencies[1].Matches)
+
+	collector := providers["func float64 in Global"]
+	assert.True(t, collector.Dependencies[0].Collector)
+	// hidden components are not collected
+	assert.Equal(t, []string{"int in Global"}, collector.Dependencies[0].Matches)
+	assert.True(t, providers["value int hidden in Global"].Components[0].Hidden)
+}
+
+func newSnapshotDB2() *snapshotDB { return &snapshotDB{} }
+
+func TestSnapshotOf_stableIDs(t *testing.T) {
+	const pkg = "github.com/jison/uni/commands"
+	ids := func(s *Snapshot) []string {
+		var res []string
+		for _, p := range s.Providers {
+			res = append(res, p.ID)
+			for _, c := range p.Components {
+				res = append(res, c.ID)
+			}
+		}
+		return res
+	}
+
+	request := model.NewScopeWith("request", model.ScopeInput("", model.Name("user")))
+	s1, err := SnapshotOf(model.NewModule(
+		model.Func(newSnapshotDB, model.Return(0, model.Name("db"))),
+		model.Func(newSnapshotHandler, model.InScope(request), model.Param(1, model.ByName("user"))),
+	))
+	assert.Nil(t, err)
+
+	// providers and inputs of the same types added before do not rename existing ones
+	request2 := model.NewScopeWith("request", model.ScopeInput("", model.Name("path")),
+		model.ScopeInput("", model.Name("user")))
+	s2, err := SnapshotOf(model.NewModule(
+		model.Func(newSnapshotDB2, model.Return(0, model.Name("db2"))),
+		model.Func(newSnapshotDB, model.Return(0, model.Name("db"))),
+		model.Func(newSnapshotHandler, model.InScope(request2), model.Param(1, model.ByName("user"))),
+	))
+	assert.Nil(t, err)
+	assert.Subset(t, ids(s2), ids(s1))
+	assert.Contains(t, ids(s2), `input string name="path" in request`)
+
+	// components with the same identity are told apart by their providers
+	s3, err := SnapshotOf(model.NewModule(model.Func(newSnapshotDB2), model.Func(newSnapshotDB)))
+	assert.Nil(t, err)
+	s4, err := SnapshotOf(model.NewModule(model.Func(newSnapshotDB), model.Func(newSnapshotDB2)))
+	assert.Nil(t, err)
+	assert.Equal(t, ids(s3), ids(s4))
+	assert.Contains(t, ids(s3), "*"+pkg+".snapshotDB in Global from "+pkg+".newSnapshotDB")
+
+	// closures are identified by their signatures rather than names numbered by the runtime
+	s5, err := SnapshotOf(model.NewModule(
+		model.Value(1),
+		model.Value(""),
+		model.Func(func(int) *snapshotDB { return nil }),
+		model.Func(func(string) *snapshotDB { return nil }),
+	))
+	assert.Nil(t, err)
+	for _, id := range ids(s5) {
+		assert.NotContains(t, id, ".func")
+	}
+	assert.Contains(t, ids(s5), "*"+pkg+".snapshotDB in Global from func(int) *"+pkg+".snapshotDB")
+	assert.Contains(t, ids(s5), "*"+pkg+".snapshotDB in Global from func(string) *"+pkg+".snapshotDB")
+
+	named := model.NewModule(model.Func(func(int) (*snapshotHandler, error) { return nil, nil },
+		model.Return(0, model.Name("a"), model.Tags(model.NewSymbol("t")))))
+	named.AllProviders().Iterate(func(p model.Provider) bool {
+		assert.Equal(t, `func(int) (*`+pkg+`.snapshotHandler, error) name="a" tags=[t]`, providerIdentity(p))
+		return true
+	})
+}
+
+func TestUnmarshalSnapshot(t *testing.T) {
+	s, err := SnapshotOf(genexample.Module())
+	assert.Nil(t, err)
+	data, err := MarshalSnapshot(s)
+	assert.Nil(t, err)
+
+	s2, err := UnmarshalSnapshot(data)
+	assert.Nil(t, err)
+	assert.Equal(t, s, s2)
+
+	_, err = UnmarshalSnapshot([]byte("{"))
+	assert.NotNil(t, err)
+}
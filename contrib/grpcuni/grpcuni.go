@@ -0,0 +1,137 @@
+package grpcuni
+
+import (
+	"context"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type options struct {
+	seeds        func(ctx context.Context) []core.EnterScopeOption
+	errorHandler func(ctx context.Context, err error) error
+	closeHandler func(ctx context.Context, err error)
+}
+
+type Option func(*options)
+
+// Seeds adds more seeds for each call, such as the user ID parsed from the metadata
+func Seeds(f func(ctx context.Context) []core.EnterScopeOption) Option {
+	return func(opts *options) {
+		opts.seeds = f
+	}
+}
+
+// ErrorHandler handles the error of entering the scope, and returns the error sent to the client.
+// By default, the error is logged and the client gets an Internal status without details of it
+func ErrorHandler(f func(ctx context.Context, err error) error) Option {
+	return func(opts *options) {
+		opts.errorHandler = f
+	}
+}
+
+// CloseErrorHandler handles the error of closing scoped components after the handler finishes
+func CloseErrorHandler(f func(ctx context.Context, err error)) Option {
+	return func(opts *options) {
+		opts.closeHandler = f
+	}
+}
+
+// errEnterScope is sent to the client instead of the error of entering the scope, which
+// may contain names and locations of providers
+var errEnterScope = status.Error(codes.Internal, "failed to prepare the call")
+
+func defaultErrorHandler(_ context.Context, err error) error {
+	grpclog.Errorf("grpcuni: failed to enter the scope: %v", err)
+	return errEnterScope
+}
+
+func optionsOf(opts []Option) *options {
+	o := &options{errorHandler: defaultErrorHandler}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(o)
+	}
+	return o
+}
+
+// enterScope enters the scope with the container in c or in ctx, the incoming metadata.MD and
+// values in seeds are seeded if they are inputs of the scope
+func enterScope(ctx context.Context, c core.Container, scope model.Scope, o *options,
+	seeds ...interface{}) (context.Context, func(), error) {
+	parent := c
+	if parent == nil {
+		parent = core.ContainerOfCtx(ctx)
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		seeds = append(seeds, md)
+	}
+	enterOpts := []core.EnterScopeOption{core.OptionalSeed(seeds...)}
+	if o.seeds != nil {
+		enterOpts = append(enterOpts, o.seeds(ctx)...)
+	}
+
+	scoped, err := core.EnterScope(parent, scope, enterOpts...)
+	if err != nil {
+		return nil, nil, o.errorHandler(ctx, err)
+	}
+
+	scopedCtx := core.WithContainerCtx(ctx, scoped)
+	closeScope := func() {
+		if err := scoped.Close(); err != nil && o.closeHandler != nil {
+			o.closeHandler(scopedCtx, err)
+		}
+	}
+	return scopedCtx, closeScope, nil
+}
+
+// UnaryServerInterceptor enters the scope for each call, the incoming metadata.MD and
+// *grpc.UnaryServerInfo are seeded if they are inputs of the scope. The container in the scope
+// is carried by the context, and components in the scope are closed when the handler finishes.
+// If c is nil, the container in the context is used.
+func UnaryServerInterceptor(c core.Container, scope model.Scope, opts ...Option) grpc.UnaryServerInterceptor {
+	o := optionsOf(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		scopedCtx, closeScope, err := enterScope(ctx, c, scope, o, info)
+		if err != nil {
+			return nil, err
+		}
+		defer closeScope()
+
+		return handler(scopedCtx, req)
+	}
+}
+
+type scopedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor, *grpc.StreamServerInfo is seeded
+// if it is an input of the scope
+func StreamServerInterceptor(c core.Container, scope model.Scope, opts ...Option) grpc.StreamServerInterceptor {
+	o := optionsOf(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		scopedCtx, closeScope, err := enterScope(ss.Context(), c, scope, o, info)
+		if err != nil {
+			return err
+		}
+		defer closeScope()
+
+		return handler(srv, &scopedServerStream{ServerStream: ss, ctx: scopedCtx})
+	}
+}
@@ -0,0 +1,214 @@
+package grpcuni
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type session struct {
+	user   string
+	closed *[]string
+}
+
+func (s *session) Close() error {
+	*s.closed = append(*s.closed, s.user)
+	return nil
+}
+
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func servingStatusOf(ctx context.Context) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	val, err := core.ValueOfCtx(ctx, &session{})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "%v", err)
+	}
+	if val.(*session).user != "admin" {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
+
+func (s *healthServer) Check(ctx context.Context,
+	_ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	st, err := servingStatusOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: st}, nil
+}
+
+func (s *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest,
+	stream grpc_health_v1.Health_WatchServer) error {
+	st, err := servingStatusOf(stream.Context())
+	if err != nil {
+		return err
+	}
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: st})
+}
+
+func startServer(t *testing.T, opts ...grpc.ServerOption) grpc_health_v1.HealthClient {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(opts...)
+	grpc_health_v1.RegisterHealthServer(server, &healthServer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestInterceptors(t *testing.T) {
+	callScope := model.NewScopeWith("call",
+		model.ScopeInput(metadata.MD{}),
+		model.ScopeInput(&grpc.UnaryServerInfo{}),
+	)
+
+	var closed []string
+	m := model.NewModule(
+		model.Func(func(md metadata.MD) *session {
+			var user string
+			if users := md.Get("user"); len(users) > 0 {
+				user = users[0]
+			}
+			return &session{user: user, closed: &closed}
+		}, model.InScope(callScope)),
+	)
+	c, err := core.NewContainer(m)
+	assert.Nil(t, err)
+
+	client := startServer(t,
+		grpc.UnaryInterceptor(UnaryServerInterceptor(c, callScope)),
+		grpc.StreamInterceptor(StreamServerInterceptor(c, callScope)),
+	)
+
+	t.Run("unary", func(t *testing.T) {
+		closed = nil
+		for _, user := range []string{"admin", "guest"} {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "user", user)
+			resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			assert.Nil(t, err)
+			if user == "admin" {
+				assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+			} else {
+				assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, resp.Status)
+			}
+		}
+		assert.Equal(t, []string{"admin", "guest"}, closed)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		closed = nil
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "user", "admin")
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.Nil(t, err)
+		resp, err := stream.Recv()
+		assert.Nil(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+	})
+}
+
+func TestInterceptors_errors(t *testing.T) {
+	callScope := model.NewScope("call")
+	otherScope := model.NewScope("other", callScope)
+	c, err := core.NewContainer(model.NewModule())
+	assert.Nil(t, err)
+
+	client := startServer(t,
+		grpc.UnaryInterceptor(UnaryServerInterceptor(c, otherScope)),
+		grpc.StreamInterceptor(StreamServerInterceptor(c, otherScope)),
+	)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "failed to prepare the call", status.Convert(err).Message())
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "failed to prepare the call", status.Convert(err).Message())
+}
+
+func TestInterceptors_ErrorHandler(t *testing.T) {
+	callScope := model.NewScope("call")
+	otherScope := model.NewScope("other", callScope)
+	c, err := core.NewContainer(model.NewModule())
+	assert.Nil(t, err)
+
+	var handled []error
+	handler := ErrorHandler(func(_ context.Context, err error) error {
+		handled = append(handled, err)
+		return status.Error(codes.Unavailable, "try later")
+	})
+	client := startServer(t,
+		grpc.UnaryInterceptor(UnaryServerInterceptor(c, otherScope, handler)),
+		grpc.StreamInterceptor(StreamServerInterceptor(c, otherScope, handler)),
+	)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "try later", status.Convert(err).Message())
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	assert.Len(t, handled, 2)
+	assert.Contains(t, handled[0].Error(), "can not enter")
+}
+
+func TestInterceptors_containerInContext(t *testing.T) {
+	callScope := model.NewScopeWith("call", model.ScopeInput(metadata.MD{}))
+	var closed []string
+	c, err := core.NewContainer(model.NewModule(
+		model.Func(func(md metadata.MD) *session {
+			return &session{user: md.Get("user")[0], closed: &closed}
+		}, model.InScope(callScope)),
+	))
+	assert.Nil(t, err)
+
+	var closeErr error
+	interceptor := UnaryServerInterceptor(nil, callScope, CloseErrorHandler(func(_ context.Context, err error) {
+		closeErr = err
+	}), Seeds(func(ctx context.Context) []core.EnterScopeOption {
+		return nil
+	}))
+
+	ctx := core.WithContainerCtx(context.Background(), c)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user", "admin"))
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return servingStatusOf(ctx)
+		})
+	assert.Nil(t, err)
+	assert.Nil(t, closeErr)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp)
+	assert.Equal(t, []string{"admin"}, closed)
+}
@@ -0,0 +1,83 @@
+package httpuni
+
+import (
+	"net/http"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+)
+
+type options struct {
+	seeds        func(r *http.Request) []core.EnterScopeOption
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	closeHandler func(r *http.Request, err error)
+}
+
+type Option func(*options)
+
+// Seeds adds more seeds for each request, such as the user ID parsed from the request
+func Seeds(f func(r *http.Request) []core.EnterScopeOption) Option {
+	return func(opts *options) {
+		opts.seeds = f
+	}
+}
+
+// ErrorHandler handles the error of entering the scope, responds 500 by default
+func ErrorHandler(f func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(opts *options) {
+		opts.errorHandler = f
+	}
+}
+
+// CloseErrorHandler handles the error of closing scoped components after the handler finishes
+func CloseErrorHandler(f func(r *http.Request, err error)) Option {
+	return func(opts *options) {
+		opts.closeHandler = f
+	}
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// Middleware enters the scope for each request, the *http.Request and http.ResponseWriter
+// are seeded if they are inputs of the scope. The container in the scope is carried by the
+// context of the request, and components in the scope are closed when the handler finishes.
+// If c is nil, the container in the context of the request is used.
+func Middleware(c core.Container, scope model.Scope, opts ...Option) func(http.Handler) http.Handler {
+	o := &options{errorHandler: defaultErrorHandler}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			parent := c
+			if parent == nil {
+				parent = core.ContainerOfCtx(r.Context())
+			}
+
+			enterOpts := []core.EnterScopeOption{core.OptionalSeed(r, w)}
+			if o.seeds != nil {
+				enterOpts = append(enterOpts, o.seeds(r)...)
+			}
+
+			scoped, err := core.EnterScope(parent, scope, enterOpts...)
+			if err != nil {
+				o.errorHandler(w, r, err)
+				return
+			}
+			defer func() {
+				if err := scoped.Close(); err != nil && o.closeHandler != nil {
+					o.closeHandler(r, err)
+				}
+			}()
+
+			ctx := core.WithContainerCtx(r.Context(), scoped)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package httpuni
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jison/uni/core"
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type session struct {
+	path   string
+	user   string
+	closed *[]string
+}
+
+func (s *session) Close() error {
+	*s.closed = append(*s.closed, s.path)
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	requestScope := model.NewScopeWith("request",
+		model.ScopeInput(&http.Request{}),
+		model.ScopeInput((*http.ResponseWriter)(nil)),
+		model.ScopeInput("", model.Name("user")),
+	)
+
+	var closed []string
+	m := model.NewModule(
+		model.Func(func(r *http.Request, w http.ResponseWriter, user string) *session {
+			w.Header().Set("X-Path", r.URL.Path)
+			return &session{path: r.URL.Path, user: user, closed: &closed}
+		}, model.InScope(requestScope)),
+	)
+	c, err := core.NewContainer(m)
+	assert.Nil(t, err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		val, err := core.ValueOfCtx(r.Context(), &session{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s := val.(*session)
+		_, _ = fmt.Fprintf(w, "%v %v", s.path, s.user)
+	})
+
+	seeds := Seeds(func(r *http.Request) []core.EnterScopeOption {
+		return []core.EnterScopeOption{core.NamedSeed("user", r.Header.Get("X-User"))}
+	})
+
+	t.Run("enter scope for each request", func(t *testing.T) {
+		closed = nil
+		server := httptest.NewServer(Middleware(c, requestScope, seeds)(handler))
+		defer server.Close()
+
+		for _, path := range []string{"/a", "/b"} {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+			req.Header.Set("X-User", "user"+path)
+			resp, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, path+" user"+path, string(body))
+			assert.Equal(t, path, resp.Header.Get("X-Path"))
+		}
+		assert.Equal(t, []string{"/a", "/b"}, closed)
+	})
+
+	t.Run("container in context", func(t *testing.T) {
+		closed = nil
+		h := Middleware(nil, requestScope, seeds)(handler)
+
+		req := httptest.NewRequest(http.MethodGet, "/c", nil)
+		req = req.WithContext(core.WithContainerCtx(req.Context(), c))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/c ", w.Body.String())
+		assert.Equal(t, []string{"/c"}, closed)
+	})
+
+	t.Run("can not enter scope", func(t *testing.T) {
+		otherScope := model.NewScope("other", requestScope)
+		h := Middleware(c, otherScope)(handler)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("custom error handler", func(t *testing.T) {
+		otherScope := model.NewScope("other", requestScope)
+		var handled error
+		h := Middleware(c, otherScope, nil, ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			handled = err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))(handler)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotNil(t, handled)
+	})
+}
+
+type failedCloser struct{}
+
+func (f *failedCloser) Close() error {
+	return fmt.Errorf("close failed")
+}
+
+func TestMiddleware_CloseErrorHandler(t *testing.T) {
+	requestScope := model.NewScope("request")
+	m := model.NewModule(
+		model.Func(func() *failedCloser { return &failedCloser{} }, model.InScope(requestScope)),
+	)
+	c, err := core.NewContainer(m)
+	assert.Nil(t, err)
+
+	var closeErr error
+	h := Middleware(c, requestScope, CloseErrorHandler(func(r *http.Request, err error) {
+		closeErr = err
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = core.ValueOfCtx(r.Context(), &failedCloser{})
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotNil(t, closeErr)
+	assert.Contains(t, closeErr.Error(), "close failed")
+}
@@ -6,12 +6,20 @@ import (
 	"github.com/jison/uni/internal/errors"
 )
 
+// capturesCallLocations returns false if the container is created with NoCallLocations
+func capturesCallLocations(c Container) bool {
+	cc, ok := c.(*container)
+	return !ok || cc == nil || !cc.noCallLocations
+}
+
 func FuncOf(c Container, function interface{}, opts ...model.FuncConsumerOption) (interface{}, error) {
 	if c == nil {
 		return nil, errors.Newf("container is nil")
 	}
 
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	exe := c.FuncOf(function, opts...)
 	return exe.Execute()
 }
@@ -21,7 +29,9 @@ func StructOf(c Container, t model.TypeVal, opts ...model.StructConsumerOption)
 		return nil, errors.Newf("container is nil")
 	}
 
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	exe := c.StructOf(t, opts...)
 	return exe.Execute()
 }
@@ -31,17 +41,35 @@ func ValueOf(c Container, t model.TypeVal, opts ...model.ValueConsumerOption) (i
 		return nil, errors.Newf("container is nil")
 	}
 
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	exe := c.ValueOf(t, opts...)
 	return exe.Execute()
 }
 
-func EnterScope(c Container, scope model.Scope) (Container, error) {
+func EnterScope(c Container, scope model.Scope, opts ...EnterScopeOption) (Container, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+
+	return c.EnterScope(scope, opts...)
+}
+
+func EnterScopePath(c Container, target model.Scope, opts ...EnterScopeOption) (Container, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+
+	return c.EnterScopePath(target, opts...)
+}
+
+func LeaveScopeTo(c Container, ancestor model.Scope) (Container, error) {
 	if c == nil {
 		return nil, errors.Newf("container is nil")
 	}
 
-	return c.EnterScope(scope)
+	return c.LeaveScopeTo(ancestor)
 }
 
 func LeaveScope(c Container) Container {
@@ -70,25 +98,31 @@ func WithContainerCtx(ctx context.Context, container Container) context.Context
 
 func FuncOfCtx(ctx context.Context, function interface{}, opts ...model.FuncConsumerOption) (interface{}, error) {
 	c := ContainerOfCtx(ctx)
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	return FuncOf(c, function, opts...)
 }
 
 func StructOfCtx(ctx context.Context, t model.TypeVal, opts ...model.StructConsumerOption) (interface{}, error) {
 	c := ContainerOfCtx(ctx)
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	return StructOf(c, t, opts...)
 }
 
 func ValueOfCtx(ctx context.Context, t model.TypeVal, opts ...model.ValueConsumerOption) (interface{}, error) {
 	c := ContainerOfCtx(ctx)
-	opts = append(opts, model.UpdateCallLocation())
+	if capturesCallLocations(c) {
+		opts = append(opts, model.UpdateCallLocation())
+	}
 	return ValueOf(c, t, opts...)
 }
 
-func EnterScopeCtx(ctx context.Context, scope model.Scope) (context.Context, error) {
+func EnterScopeCtx(ctx context.Context, scope model.Scope, opts ...EnterScopeOption) (context.Context, error) {
 	c := ContainerOfCtx(ctx)
-	c2, err := EnterScope(c, scope)
+	c2, err := EnterScope(c, scope, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +137,21 @@ func LeaveScopeCtx(ctx context.Context) context.Context {
 	c2 := LeaveScope(c)
 	return WithContainerCtx(ctx, c2)
 }
+
+func EnterScopePathCtx(ctx context.Context, target model.Scope, opts ...EnterScopeOption) (context.Context, error) {
+	c := ContainerOfCtx(ctx)
+	c2, err := EnterScopePath(c, target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return WithContainerCtx(ctx, c2), nil
+}
+
+func LeaveScopeToCtx(ctx context.Context, ancestor model.Scope) (context.Context, error) {
+	c := ContainerOfCtx(ctx)
+	c2, err := LeaveScopeTo(c, ancestor)
+	if err != nil {
+		return nil, err
+	}
+	return WithContainerCtx(ctx, c2), nil
+}
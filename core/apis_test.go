@@ -300,3 +300,24 @@ func TestLeaveScopeCtx(t *testing.T) {
 		assert.Same(t, ctx, ctx2)
 	})
 }
+
+func TestEnterScopePathCtx(t *testing.T) {
+	m, scope1, scope2 := buildModuleForContainerTest()
+	c, _ := NewContainer(m)
+	ctx := WithContainerCtx(context.TODO(), c)
+
+	ctx2, err := EnterScopePathCtx(ctx, scope2)
+	assert.Nil(t, err)
+	assert.Equal(t, scope2, ContainerOfCtx(ctx2).Scope())
+
+	ctx1, err := LeaveScopeToCtx(ctx2, scope1)
+	assert.Nil(t, err)
+	assert.Equal(t, scope1, ContainerOfCtx(ctx1).Scope())
+
+	_, err = LeaveScopeToCtx(ctx1, scope2)
+	assert.NotNil(t, err)
+	_, err = EnterScopePathCtx(context.TODO(), scope1)
+	assert.NotNil(t, err)
+	_, err = LeaveScopeToCtx(context.TODO(), scope1)
+	assert.NotNil(t, err)
+}
@@ -1,7 +1,13 @@
 package core
 
 import (
+	"io"
+	"reflect"
+	"time"
+
 	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/graph"
 	"github.com/jison/uni/internal/errors"
 )
 
@@ -13,16 +19,51 @@ type Container interface {
 	ValueOf(t model.TypeVal, opts ...model.ValueConsumerOption) Executor
 
 	ExecutorOf(cb model.ConsumerBuilder) Executor
+	// Prepare validates the consumer and derives the graph of it once, errors of missing, uncertain
+	// and cyclic dependencies are reported at once, and the resolver resolves the value many times
+	Prepare(cb model.ConsumerBuilder) (Resolver, error)
 
 	Scope() model.Scope
-	EnterScope(model.Scope) (Container, error)
+	EnterScope(scope model.Scope, opts ...EnterScopeOption) (Container, error)
+	EnterScopePath(target model.Scope, opts ...EnterScopeOption) (Container, error)
 	LeaveScope() Container
+	LeaveScopeTo(ancestor model.Scope) (Container, error)
+	// Evict drops cached values of providers of components matching the criteria in the current
+	// scope and its ancestors, values are closed if they implement io.Closer, and values depending
+	// on them are built again when they are resolved next time
+	Evict(criteria model.CriteriaBuilder) error
+	// Replace replaces the value of the only component matching the criteria in the current scope
+	// and its ancestors, the old values of its provider are closed if they implement io.Closer,
+	// and values depending on them are built again when they are resolved next time
+	Replace(criteria model.CriteriaBuilder, newValue interface{}) error
+	// Child creates a container with components in the module and this container, components
+	// of this container are shared with the child, but components in the module are invisible
+	// to this container. The child is in the same scope as this container
+	Child(m model.Module, opts ...ContainerOption) (Container, error)
+	// ScopeStack returns scopes entered by the container, from the global scope to the current scope
+	ScopeStack() []model.Scope
+	// Built returns components whose values are cached in the scope, seeded components first
+	// and then others in the order of creation, it is empty if the scope is not entered
+	Built(scope model.Scope) model.ComponentCollection
+	// Scopes returns scopes of all components in the container and their ancestors
+	Scopes() []model.Scope
+	// Components returns components matching the criteria in the current scope and its ancestors
+	Components(criteria model.CriteriaBuilder) model.ComponentCollection
+	// Close closes components implementing io.Closer which are created in the current scope,
+	// the latest created first. Values created in the scope are dropped, they are built again
+	// if they are resolved later
+	Close() error
 }
 
 type ContainerOptions struct {
 	ignoreMissing   bool
 	ignoreUncertain bool
 	ignoreCycle     bool
+	activeProfiles  []string
+	strict          bool
+	eager           bool
+	cycleLimits     *graph.CycleLimits
+	noCallLocations bool
 }
 
 type ContainerOption func(*ContainerOptions)
@@ -45,16 +86,148 @@ func IgnoreCycle() ContainerOption {
 	}
 }
 
+// Strict validates the container more strictly, missing, uncertain and cyclic dependencies are not
+// ignored, types of components must be assignable to their dependencies, injected fields must be
+// settable and scopes of providers must be reachable from the global scope
+func Strict() ContainerOption {
+	return func(opts *ContainerOptions) {
+		opts.strict = true
+	}
+}
+
+// Eager builds all components in the global scope when the container is created
+func Eager() ContainerOption {
+	return func(opts *ContainerOptions) {
+		opts.eager = true
+	}
+}
+
+// CycleSearchLimits bounds the search of dependence cycles, it stops when maxCycles cycles are found
+// or timeout is reached, a zero value means no limit. Groups of cycles are always reported, the
+// timeout also bounds the search of their shortest cycles. The default limits are 100 cycles and
+// 1 second.
+func CycleSearchLimits(maxCycles int, timeout time.Duration) ContainerOption {
+	return func(opts *ContainerOptions) {
+		opts.cycleLimits = &graph.CycleLimits{MaxCycles: maxCycles, Timeout: timeout}
+	}
+}
+
+// NoCallLocations stops capturing locations of ValueOf, StructOf and FuncOf of the container, which
+// may be called in hot paths. See model.CaptureLocations to stop capturing
+// all locations.
+func NoCallLocations() ContainerOption {
+	return func(opts *ContainerOptions) {
+		opts.noCallLocations = true
+	}
+}
+
+func ActiveProfiles(profiles ...string) ContainerOption {
+	return func(opts *ContainerOptions) {
+		opts.activeProfiles = append(opts.activeProfiles, profiles...)
+	}
+}
+
+type seedValue struct {
+	name     string
+	value    interface{}
+	optional bool
+}
+
+type EnterScopeOptions struct {
+	seeds []seedValue
+}
+
+type EnterScopeOption func(*EnterScopeOptions)
+
+// Seed provides values of inputs of the scope, each value is matched to an input by type
+func Seed(values ...interface{}) EnterScopeOption {
+	return func(opts *EnterScopeOptions) {
+		for _, v := range values {
+			opts.seeds = append(opts.seeds, seedValue{value: v})
+		}
+	}
+}
+
+// OptionalSeed is like Seed, but values which match no input used by the container are ignored
+func OptionalSeed(values ...interface{}) EnterScopeOption {
+	return func(opts *EnterScopeOptions) {
+		for _, v := range values {
+			opts.seeds = append(opts.seeds, seedValue{value: v, optional: true})
+		}
+	}
+}
+
+// NamedSeed provides the value of the input with the name
+func NamedSeed(name string, value interface{}) EnterScopeOption {
+	return func(opts *EnterScopeOptions) {
+		opts.seeds = append(opts.seeds, seedValue{name: name, value: value})
+	}
+}
+
 func NewContainer(m model.Module, opts ...ContainerOption) (Container, error) {
+	return newContainer(m, containerOptionsOf(opts))
+}
+
+func containerOptionsOf(opts []ContainerOption) *ContainerOptions {
 	containerOpts := &ContainerOptions{}
 	for _, opt := range opts {
 		opt(containerOpts)
 	}
+	return containerOpts
+}
 
-	return newContainer(m, containerOpts)
+// NewDependenceGraph builds and validates the graph of the module like NewContainer, for tools
+// inspecting the module or generating code from it
+func NewDependenceGraph(m model.Module, opts ...ContainerOption) (DependenceGraph, error) {
+	g, _, err := buildGraph(m, containerOptionsOf(opts))
+	return g, err
+}
+
+func buildGraph(m model.Module, opts *ContainerOptions) (DependenceGraph, model.ComponentRepository, error) {
+	activeModule, err := activateModule(m, opts, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rep := model.NewRepositoryOfModule(activeModule)
+	g := newDependenceGraph(rep)
+	setCycleLimits(g, opts)
+	if err = validateContainer(g, rep, rep, opts); err != nil {
+		return nil, nil, err
+	}
+	return g, rep, nil
+}
+
+func setCycleLimits(g DependenceGraph, opts *ContainerOptions) {
+	if dg, ok := g.(*dependenceGraph); ok && opts != nil && opts.cycleLimits != nil {
+		dg.cycleLimits = *opts.cycleLimits
+	}
 }
 
 func newContainer(m model.Module, opts *ContainerOptions) (*container, error) {
+	g, rep, err := buildGraph(m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &container{
+		graph:           g,
+		repository:      rep,
+		storage:         newScopeStorage(),
+		noCallLocations: opts != nil && opts.noCallLocations,
+	}
+	c.storage.shared.hooks = storageHooks{
+		policyOf: c.cachePolicyOfNode,
+		onDrop:   c.closeDropped,
+	}
+	if err = c.buildEagerly(rep, opts); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// activateModule activates the module, inputs of its modules may be provided by components in outer
+func activateModule(m model.Module, opts *ContainerOptions, outer model.ComponentRepository) (model.Module, error) {
 	if m == nil {
 		return nil, errors.Newf("module is nil")
 	}
@@ -63,36 +236,79 @@ func newContainer(m model.Module, opts *ContainerOptions) (*container, error) {
 		return nil, err
 	}
 
-	rep := model.NewRepository(m.AllComponents())
-	g := newDependenceGraph(rep)
+	var env model.Environment
+	if opts != nil {
+		env = model.NewEnvironment(opts.activeProfiles...)
+	} else {
+		env = model.NewEnvironment()
+	}
+
+	activeModule, err := model.ActivateWithin(m, env, outer)
+	if err != nil {
+		return nil, err
+	}
+	if activeModule != m {
+		// components with the same name may be active at the same time
+		if err = activeModule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err = model.ValidateInputs(activeModule, outer); err != nil {
+		return nil, err
+	}
+	return activeModule, nil
+}
+
+// validateContainer validates the graph, and components in rep if the container is strict,
+// dependencies are matched in lookup
+func validateContainer(g DependenceGraph, rep model.ComponentRepository, lookup model.ComponentRepository,
+	opts *ContainerOptions) error {
+	if opts == nil || !opts.strict {
+		return validateGraph(g, opts)
+	}
 
 	errs := errors.Empty()
+	if err := validateGraph(g, opts); err != nil {
+		errs = errs.AddErrors(err)
+	}
+	if err := validateStrict(rep, lookup); err != nil {
+		errs = errs.AddErrors(err)
+	}
 
-	if err := g.MissingError(); err != nil && (opts == nil || !opts.ignoreMissing) {
+	if errs.HasError() {
+		return errs.WithMainf("container is invalid in strict mode")
+	}
+	return nil
+}
+
+func validateGraph(g DependenceGraph, opts *ContainerOptions) error {
+	errs := errors.Empty()
+	strict := opts == nil || opts.strict
+
+	if err := g.MissingError(); err != nil && (strict || !opts.ignoreMissing) {
 		errs = errs.AddErrors(err)
 	}
 
-	if err := g.UncertainError(); err != nil && (opts == nil || !opts.ignoreUncertain) {
+	if err := g.UncertainError(); err != nil && (strict || !opts.ignoreUncertain) {
 		errs = errs.AddErrors(err)
 	}
 
-	if err := g.CycleError(); err != nil && (opts == nil || !opts.ignoreCycle) {
+	if err := g.CycleError(); err != nil && (strict || !opts.ignoreCycle) {
 		errs = errs.AddErrors(err)
 	}
 
 	if errs.HasError() {
-		return nil, errs
+		return errs
 	}
-
-	return &container{
-		graph:   g,
-		storage: newScopeStorage(),
-	}, nil
+	return nil
 }
 
 type container struct {
-	graph   DependenceGraph
-	storage *scopeStorage
+	graph      DependenceGraph
+	repository model.ComponentRepository
+	storage    *scopeStorage
+	// noCallLocations is true if locations of calls of the container are not captured
+	noCallLocations bool
 }
 
 func (c *container) Load(criteriaList ...model.CriteriaBuilder) error {
@@ -125,9 +341,12 @@ func (c *container) FuncOf(function interface{}, opts ...model.FuncConsumerOptio
 		return newExecutorWithError(errors.Newf("container is nil"))
 	}
 
-	cb := model.FuncConsumer(function, opts...).
-		SetScope(c.Scope()).
-		UpdateCallLocation(nil)
+	if c.noCallLocations {
+		opts = append(opts, model.NoLocation())
+	} else {
+		opts = append(opts, model.UpdateCallLocation())
+	}
+	cb := model.FuncConsumer(function, opts...).SetScope(c.Scope())
 	return c.ExecutorOf(cb)
 }
 
@@ -136,9 +355,12 @@ func (c *container) StructOf(t model.TypeVal, opts ...model.StructConsumerOption
 		return newExecutorWithError(errors.Newf("container is nil"))
 	}
 
-	cb := model.StructConsumer(t, opts...).
-		SetScope(c.Scope()).
-		UpdateCallLocation(nil)
+	if c.noCallLocations {
+		opts = append(opts, model.NoLocation())
+	} else {
+		opts = append(opts, model.UpdateCallLocation())
+	}
+	cb := model.StructConsumer(t, opts...).SetScope(c.Scope())
 	return c.ExecutorOf(cb)
 }
 
@@ -147,9 +369,12 @@ func (c *container) ValueOf(t model.TypeVal, opts ...model.ValueConsumerOption)
 		return newExecutorWithError(errors.Newf("container is nil"))
 	}
 
-	cb := model.ValueConsumer(t, opts...).
-		SetScope(c.Scope()).
-		UpdateCallLocation(nil)
+	if c.noCallLocations {
+		opts = append(opts, model.NoLocation())
+	} else {
+		opts = append(opts, model.UpdateCallLocation())
+	}
+	cb := model.ValueConsumer(t, opts...).SetScope(c.Scope())
 	return c.ExecutorOf(cb)
 }
 
@@ -161,10 +386,23 @@ func (c *container) ExecutorOf(cb model.ConsumerBuilder) Executor {
 	return newExecutor(c.graph, c.storage, cb.Consumer())
 }
 
+func (c *container) Prepare(cb model.ConsumerBuilder) (Resolver, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+	if cb == nil {
+		return nil, errors.Newf("consumer is nil")
+	}
+
+	return newResolver(c.graph, c.storage, cb.Consumer())
+}
+
 func (c *container) newContainerWithStorage(storage *scopeStorage) *container {
 	return &container{
-		graph:   c.graph,
-		storage: storage,
+		graph:           c.graph,
+		repository:      c.repository,
+		storage:         storage,
+		noCallLocations: c.noCallLocations,
 	}
 }
 
@@ -175,19 +413,175 @@ func (c *container) Scope() model.Scope {
 	return c.storage.Scope()
 }
 
-func (c *container) EnterScope(s model.Scope) (Container, error) {
+func (c *container) EnterScope(s model.Scope, opts ...EnterScopeOption) (Container, error) {
 	if c == nil {
 		return nil, errors.Newf("container is nil")
 	}
 
+	enterOpts := &EnterScopeOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(enterOpts)
+	}
+
 	newStorage, err := c.storage.Enter(s)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = c.seedStorages([]*scopeStorage{newStorage}, enterOpts.seeds); err != nil {
+		return nil, err
+	}
+
 	return c.newContainerWithStorage(newStorage), nil
 }
 
+func seedMatchInput(seed seedValue, input model.Provider) bool {
+	vType := reflect.TypeOf(seed.value)
+	match := false
+	input.Components().Iterate(func(com model.Component) bool {
+		if seed.name != "" && com.Name() != seed.name {
+			return true
+		}
+		match = vType == com.Type() || vType.AssignableTo(com.Type())
+		return !match
+	})
+	return match
+}
+
+// seedStorages seeds values into the storages which are entered, the storage
+// of the scope whose input matches the value is seeded
+func (c *container) seedStorages(storages []*scopeStorage, seeds []seedValue) error {
+	if len(seeds) == 0 {
+		return nil
+	}
+
+	var scopes []model.Scope
+	storageOfInput := map[model.Provider]*scopeStorage{}
+	for _, storage := range storages {
+		scopes = append(scopes, storage.Scope())
+		for _, input := range storage.Scope().Inputs() {
+			storageOfInput[input] = storage
+		}
+	}
+
+	errs := errors.Empty()
+	seeded := map[model.Provider]struct{}{}
+	for _, seed := range seeds {
+		if seed.value == nil {
+			errs = errs.AddErrorf("can not seed nil value")
+			continue
+		}
+
+		var inputs []model.Provider
+		for _, storage := range storages {
+			for _, input := range storage.Scope().Inputs() {
+				if seedMatchInput(seed, input) {
+					inputs = append(inputs, input)
+				}
+			}
+		}
+
+		if len(inputs) == 0 {
+			if seed.optional {
+				continue
+			}
+			errs = errs.AddErrorf("seed [%T](%v) does not match any input of scope %v",
+				seed.value, seed.value, scopes)
+			continue
+		} else if len(inputs) > 1 {
+			errs = errs.AddErrorf("seed [%T](%v) matches more than one input of scope %v %v",
+				seed.value, seed.value, scopes, inputs)
+			continue
+		}
+
+		input := inputs[0]
+		if _, ok := seeded[input]; ok {
+			errs = errs.AddErrorf("%+v is seeded more than once", input)
+			continue
+		}
+		seeded[input] = struct{}{}
+
+		node, ok := c.graph.NodeOfProvider(input)
+		if !ok {
+			if seed.optional {
+				continue
+			}
+			errs = errs.AddErrorf("%+v is not used by any provider in the container", input)
+			continue
+		}
+		storageOfInput[input].seed(node, valuer.SingleValue(reflect.ValueOf(seed.value)))
+	}
+
+	if errs.HasError() {
+		return errs.WithMainf("can not seed values when entering scope `%v`", scopes[len(scopes)-1])
+	}
+	return nil
+}
+
+// EnterScopePath enters all scopes on the path from the current scope to the target scope,
+// it is an error if there are more than one path
+func (c *container) EnterScopePath(target model.Scope, opts ...EnterScopeOption) (Container, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+	if target == nil {
+		return nil, errors.Newf("scope is nil")
+	}
+
+	paths := model.ScopePaths(c.Scope(), target)
+	if len(paths) == 0 {
+		return nil, errors.Newf("%+v can not enter from %+v", target, c.Scope())
+	} else if len(paths) > 1 {
+		errs := errors.Empty()
+		for _, path := range paths {
+			errs = errs.AddErrorf("%v", path)
+		}
+		return nil, errs.WithMainf("there are more than one path to enter %+v from %+v", target, c.Scope())
+	}
+
+	enterOpts := &EnterScopeOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(enterOpts)
+	}
+
+	storage := c.storage
+	var storages []*scopeStorage
+	for _, s := range paths[0] {
+		var err error
+		if storage, err = storage.Enter(s); err != nil {
+			return nil, err
+		}
+		storages = append(storages, storage)
+	}
+
+	if err := c.seedStorages(storages, enterOpts.seeds); err != nil {
+		return nil, err
+	}
+
+	return c.newContainerWithStorage(storage), nil
+}
+
+// LeaveScopeTo leaves scopes until the scope of the container is the ancestor
+func (c *container) LeaveScopeTo(ancestor model.Scope) (Container, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+
+	for storage := c.storage; storage != nil; storage = storage.Leave() {
+		if storage.Scope() == ancestor {
+			return c.newContainerWithStorage(storage), nil
+		}
+	}
+
+	return nil, errors.Newf("%+v is not entered in the container", ancestor)
+}
+
 func (c *container) LeaveScope() Container {
 	if c == nil {
 		return nil
@@ -200,3 +594,341 @@ func (c *container) LeaveScope() Container {
 
 	return c.newContainerWithStorage(oldStorage)
 }
+
+func (c *container) ScopeStack() []model.Scope {
+	if c == nil {
+		return nil
+	}
+
+	var scopes []model.Scope
+	for storage := c.storage; storage != nil; storage = storage.Leave() {
+		scopes = append([]model.Scope{storage.Scope()}, scopes...)
+	}
+	return scopes
+}
+
+func (c *container) Built(scope model.Scope) model.ComponentCollection {
+	if c == nil {
+		return model.EmptyComponents()
+	}
+
+	for storage := c.storage; storage != nil; storage = storage.Leave() {
+		if storage.Scope() != scope {
+			continue
+		}
+
+		var coms model.ComponentSlice
+		seeded, created := storage.storedNodes()
+		// values are seeded into nodes of the input providers
+		for _, node := range seeded {
+			if p, ok := c.graph.ProviderOfNode(node); ok {
+				coms = append(coms, p.Components().ToArray()...)
+			}
+		}
+		for _, node := range created {
+			if com, ok := c.graph.ComponentOfNode(node); ok {
+				coms = append(coms, com)
+			}
+		}
+		return coms.Distinct()
+	}
+	return model.EmptyComponents()
+}
+
+func (c *container) Scopes() []model.Scope {
+	if c == nil {
+		return nil
+	}
+
+	var scopes []model.Scope
+	c.graph.Nodes().Each(func(node Node) {
+		if com, ok := c.graph.ComponentOfNode(node); ok {
+			scopes = append(scopes, com.Provider().Scope())
+		}
+	})
+	return model.ScopesWithAncestors(scopes...)
+}
+
+func (c *container) Components(criteria model.CriteriaBuilder) model.ComponentCollection {
+	if c == nil || criteria == nil {
+		return model.EmptyComponents()
+	}
+
+	entered := map[model.Scope]struct{}{}
+	for _, s := range c.ScopeStack() {
+		entered[s] = struct{}{}
+	}
+	return c.repository.ComponentsMatch(criteria.Criteria()).Filter(func(com model.Component) bool {
+		_, ok := entered[com.Provider().Scope()]
+		return ok
+	})
+}
+
+func (c *container) Close() error {
+	if c == nil {
+		return errors.Newf("container is nil")
+	}
+
+	// seeded values are not owned by the container
+	inputs := map[model.Provider]struct{}{}
+	for _, input := range c.Scope().Inputs() {
+		inputs[input] = struct{}{}
+	}
+
+	errs := errors.Empty()
+	closed := map[interface{}]struct{}{}
+	nodes, values := c.storage.createdValues()
+	for i, node := range nodes {
+		// values of providers are shared by their components
+		com, ok := c.graph.ComponentOfNode(node)
+		if !ok {
+			continue
+		}
+		if _, ok = inputs[com.Provider()]; ok {
+			continue
+		}
+
+		closer, ok := closerOf(values[i])
+		if !ok {
+			continue
+		}
+		if reflect.TypeOf(closer).Comparable() {
+			if _, ok := closed[closer]; ok {
+				continue
+			}
+			closed[closer] = struct{}{}
+		}
+
+		if err := closer.Close(); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+	// closed values are built again if they are used later
+	c.storage.removeCreated()
+
+	if errs.HasError() {
+		return errs.WithMainf("there are errors when closing components in scope `%v`", c.Scope())
+	}
+	return nil
+}
+
+func closerOf(val valuer.Value) (io.Closer, bool) {
+	rv, ok := val.AsSingle()
+	if !ok || !rv.IsValid() || !rv.CanInterface() {
+		return nil, false
+	}
+	closer, ok := rv.Interface().(io.Closer)
+	return closer, ok
+}
+
+func (c *container) cachePolicyOfNode(node Node) (model.CachePolicy, bool, bool) {
+	if p, ok := c.graph.ProviderOfNode(node); ok {
+		policy, hasPolicy := model.CachePolicyOf(p)
+		return policy, hasPolicy, true
+	}
+	if com, ok := c.graph.ComponentOfNode(node); ok {
+		policy, hasPolicy := model.CachePolicyOf(com.Provider())
+		return policy, hasPolicy, false
+	}
+	return model.CachePolicy{}, false, false
+}
+
+// closeDropped closes the value of a component dropped from the storage
+func (c *container) closeDropped(node Node, val valuer.Value) error {
+	if _, ok := c.graph.ComponentOfNode(node); !ok {
+		return nil
+	}
+	if closer, ok := closerOf(val); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (c *container) Evict(criteria model.CriteriaBuilder) error {
+	if c == nil {
+		return errors.Newf("container is nil")
+	}
+	if criteria == nil {
+		return errors.Newf("criteria is nil")
+	}
+
+	cri := criteria.Criteria()
+	coms := c.repository.ComponentsMatch(cri).ToArray()
+	if len(coms) == 0 {
+		return errors.Newf("there is no component matching %+v", cri)
+	}
+
+	errs := errors.Empty()
+	evicted := map[model.Provider]struct{}{}
+	for _, com := range coms {
+		p := com.Provider()
+		if _, ok := evicted[p]; ok {
+			continue
+		}
+		evicted[p] = struct{}{}
+
+		if isScopeInput(p) {
+			continue
+		}
+		if err := c.dropProvider(p); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+
+	if err := c.storage.sweepAll(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if errs.HasError() {
+		return errs.WithMainf("there are errors when evicting %+v", cri)
+	}
+	return nil
+}
+
+// dropProvider drops cached values of the provider and its components
+func (c *container) dropProvider(p model.Provider) error {
+	var nodes []Node
+	if node, ok := c.graph.NodeOfProvider(p); ok {
+		nodes = append(nodes, node)
+	}
+	p.Components().Each(func(com model.Component) {
+		if node, ok := c.graph.NodeOfComponent(com); ok {
+			nodes = append(nodes, node)
+		}
+	})
+
+	errs := errors.Empty()
+	for _, node := range nodes {
+		storage := c.storage.storageOfNode(node, p.Scope())
+		if storage == nil {
+			continue
+		}
+		if err := storage.dropNode(node); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+func (c *container) Replace(criteria model.CriteriaBuilder, newValue interface{}) error {
+	if c == nil {
+		return errors.Newf("container is nil")
+	}
+	if criteria == nil {
+		return errors.Newf("criteria is nil")
+	}
+	if newValue == nil {
+		return errors.Newf("can not replace with nil value")
+	}
+
+	cri := criteria.Criteria()
+	coms := c.repository.ComponentsMatch(cri).ToArray()
+	if len(coms) == 0 {
+		return errors.Newf("there is no component matching %+v", cri)
+	} else if len(coms) > 1 {
+		return errors.Newf("there are more than one component matching %+v: %v", cri, coms)
+	}
+
+	com := coms[0]
+	p := com.Provider()
+	if isScopeInput(p) {
+		return errors.Newf("%+v is an input of scope %v, it can only be seeded", com, p.Scope())
+	}
+	valType := reflect.TypeOf(newValue)
+	if !valType.AssignableTo(com.Type()) {
+		return errors.Newf("value of type %v can not replace %+v", valType, com)
+	}
+
+	node, ok := c.graph.NodeOfComponent(com)
+	if !ok {
+		return errors.Newf("there is no node of %+v", com)
+	}
+	storage := c.storage.storageOfNode(node, p.Scope())
+	if storage == nil {
+		return errors.Newf("scope `%v` of %+v is not entered", p.Scope(), com)
+	}
+
+	val := reflect.New(com.Type()).Elem()
+	val.Set(reflect.ValueOf(newValue))
+
+	errs := errors.Empty()
+	if err := c.dropProvider(p); err != nil {
+		errs = errs.AddErrors(err)
+	}
+	storage.replace(node, valuer.SingleValue(val))
+	if err := c.storage.sweepAll(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if errs.HasError() {
+		return errs.WithMainf("there are errors when replacing %+v", com)
+	}
+	return nil
+}
+
+// isScopeInput returns true if values of the provider are seeded when entering its scope
+func isScopeInput(p model.Provider) bool {
+	for _, input := range p.Scope().Inputs() {
+		if input == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *container) Child(m model.Module, opts ...ContainerOption) (Container, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+
+	containerOpts := containerOptionsOf(opts)
+	activeModule, err := activateModule(m, containerOpts, c.repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := model.NewRepositoryOfModule(activeModule)
+	g := c.graph.DeriveRepository(rep)
+	setCycleLimits(g, containerOpts)
+	layered := model.LayerRepository(c.repository, rep)
+	if err = validateContainer(g, rep, layered, containerOpts); err != nil {
+		return nil, err
+	}
+
+	child := &container{
+		graph:           g,
+		repository:      layered,
+		storage:         newScopeStorage(),
+		noCallLocations: c.noCallLocations || containerOpts.noCallLocations,
+	}
+	child.storage.shared.base = c.storage
+	child.storage.shared.drops = c.storage.shared.drops
+	child.storage.shared.hooks = storageHooks{
+		policyOf:  child.cachePolicyOfNode,
+		onDrop:    child.closeDropped,
+		inherited: c.hasNode,
+	}
+	if err = child.buildEagerly(rep, containerOpts); err != nil {
+		return nil, err
+	}
+	for _, s := range c.ScopeStack()[1:] {
+		if child.storage, err = child.storage.Enter(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return child, nil
+}
+
+// hasNode returns true if the node is of a provider or a component in the container
+func (c *container) hasNode(node Node) bool {
+	if _, ok := c.graph.ProviderOfNode(node); ok {
+		return true
+	}
+	_, ok := c.graph.ComponentOfNode(node)
+	return ok
+}
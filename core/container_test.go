@@ -3,7 +3,10 @@ package core
 import (
 	"fmt"
 	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/jison/uni/core/model"
 	"github.com/stretchr/testify/assert"
@@ -83,6 +86,342 @@ func Test_NewContainer(t *testing.T) {
 	})
 }
 
+func Test_NewContainer_ActiveProfiles(t *testing.T) {
+	m := model.NewModule(
+		model.DeclareProfiles("dev", "prod"),
+		model.When(model.OnProfile("dev"), model.Value("dev-dsn", model.Name("dsn"))),
+		model.When(model.OnProfile("prod"), model.Value("prod-dsn", model.Name("dsn"))),
+		model.Func(func(dsn string) int { return len(dsn) }, model.Param(0, model.ByName("dsn"))),
+	)
+
+	t.Run("dev", func(t *testing.T) {
+		c, err := NewContainer(m, ActiveProfiles("dev"))
+		assert.Nil(t, err)
+		v, err := c.ValueOf(0).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, len("dev-dsn"), v)
+	})
+
+	t.Run("prod", func(t *testing.T) {
+		c, err := NewContainer(m, ActiveProfiles("prod"))
+		assert.Nil(t, err)
+		v, err := c.ValueOf(0).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, len("prod-dsn"), v)
+	})
+
+	t.Run("no active profile", func(t *testing.T) {
+		_, err := NewContainer(m)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("duplicate names after activating", func(t *testing.T) {
+		_, err := NewContainer(m, ActiveProfiles("dev", "prod"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `duplicate name "dsn"`)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := NewContainer(m, ActiveProfiles("staging"))
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_NewContainer_PrivateModule(t *testing.T) {
+	type helper struct{}
+
+	dbModule := model.NewModule(
+		model.Export(model.NewCriteria("")),
+		model.Value(helper{}),
+		model.Func(func(h helper) string { return "dsn" }),
+	)
+
+	t.Run("use exported component", func(t *testing.T) {
+		m := model.NewModule(
+			model.SubModule(dbModule),
+			model.Func(func(dsn string) int { return len(dsn) }),
+		)
+		c, err := NewContainer(m)
+		assert.Nil(t, err)
+		v, err := c.ValueOf(0).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, v)
+	})
+
+	t.Run("use private component", func(t *testing.T) {
+		m := model.NewModule(
+			model.SubModule(dbModule),
+			model.Func(func(h helper) int { return 0 }),
+		)
+		_, err := NewContainer(m)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not exported by private Module at")
+		assert.Contains(t, err.Error(), "container_test.go")
+	})
+
+	t.Run("consumers of the container are in the root module", func(t *testing.T) {
+		// the root module is private itself, but its components are visible to the container
+		c, err := NewContainer(dbModule)
+		assert.Nil(t, err)
+		v, err := c.ValueOf(helper{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, helper{}, v)
+
+		c, err = NewContainer(model.NewModule(model.SubModule(dbModule)))
+		assert.Nil(t, err)
+		v, err = c.ValueOf("").Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "dsn", v)
+		_, err = c.ValueOf(helper{}).Execute()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not exported by private Module at")
+	})
+}
+
+func Test_NewContainer_NearMisses(t *testing.T) {
+	m := model.NewModule(
+		model.Value(1, model.Name("one")),
+		model.Func(func(i int) string { return "" }, model.Param(0, model.ByName("two"))),
+	)
+	_, err := NewContainer(m)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `did you mean Component[int]{name="one"} at`)
+	assert.Contains(t, err.Error(), "the name or tags are different")
+
+	c, err := NewContainer(m, IgnoreMissing())
+	assert.Nil(t, err)
+	_, err = c.ValueOf("").Execute()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `did you mean Component[int]{name="one"} at`)
+}
+
+func Test_NewContainer_Namespace(t *testing.T) {
+	type config struct{ dsn string }
+	type db struct{ dsn string }
+
+	dbModule := model.NewModule(
+		model.Func(func(cfg *config) *db { return &db{cfg.dsn} }),
+	)
+
+	m := model.NewModule(
+		model.Value(&config{"primary"}),
+		model.Value(&config{"replica"}, model.Name("replica"), model.Hide()),
+		model.SubModule(dbModule),
+		model.SubModule(dbModule, model.Namespace("replica")),
+	)
+	c, err := NewContainer(m)
+	assert.Nil(t, err)
+
+	primary, err := c.ValueOf(&db{}).Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", primary.(*db).dsn)
+
+	replica, err := c.ValueOf(&db{}, model.ByName("replica")).Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "replica", replica.(*db).dsn)
+}
+
+func Test_NewContainer_ModuleInputs(t *testing.T) {
+	type config struct{ addr string }
+	type client struct{ addr string }
+
+	redisModule := model.NewModule(
+		model.Require(model.NewCriteria(&config{})),
+		model.Func(func(cfg *config) *client { return &client{cfg.addr} }),
+	)
+
+	t.Run("missing input", func(t *testing.T) {
+		_, err := NewContainer(model.NewModule(model.SubModule(redisModule)))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not provided")
+		assert.Contains(t, err.Error(), "container_test.go")
+	})
+
+	t.Run("default input", func(t *testing.T) {
+		m := model.NewModule(
+			model.Default(model.NewCriteria(&config{}), model.Value(&config{"default"})),
+			model.Func(func(cfg *config) *client { return &client{cfg.addr} }),
+		)
+		c, err := NewContainer(model.NewModule(model.SubModule(m)))
+		assert.Nil(t, err)
+		v, err := c.ValueOf(&client{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "default", v.(*client).addr)
+
+		// the default is only visible in the module
+		_, err = c.ValueOf(&config{}).Execute()
+		assert.NotNil(t, err)
+		_, err = NewContainer(model.NewModule(
+			model.SubModule(m),
+			model.Func(func(cfg *config) int { return 0 }),
+		))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is a default only visible in Module at")
+	})
+
+	t.Run("provided by parent container", func(t *testing.T) {
+		parent, err := NewContainer(model.NewModule(model.Value(&config{"parent"})))
+		assert.Nil(t, err)
+		child, err := parent.Child(model.NewModule(model.SubModule(redisModule)))
+		assert.Nil(t, err)
+		v, err := child.ValueOf(&client{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "parent", v.(*client).addr)
+
+		m := model.NewModule(
+			model.Default(model.NewCriteria(&config{}), model.Value(&config{"default"})),
+			model.Func(func(cfg *config) *client { return &client{cfg.addr} }),
+		)
+		child, err = parent.Child(model.NewModule(model.SubModule(m)))
+		assert.Nil(t, err)
+		v, err = child.ValueOf(&client{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "parent", v.(*client).addr)
+	})
+}
+
+type bindTestInterface interface {
+	Foo() int
+}
+
+type bindTestImpl struct{ n int }
+
+func (b *bindTestImpl) Foo() int { return b.n }
+
+func Test_NewContainer_Bind(t *testing.T) {
+	created := 0
+	m := model.NewModule(
+		model.Func(func() *bindTestImpl {
+			created++
+			return &bindTestImpl{n: created}
+		}),
+		model.Bind((*bindTestInterface)(nil), &bindTestImpl{}),
+		model.Supply("a", 1),
+	)
+	c, err := NewContainer(m)
+	assert.Nil(t, err)
+
+	impl, err := c.ValueOf(&bindTestImpl{}).Execute()
+	assert.Nil(t, err)
+	iface, err := c.ValueOf((*bindTestInterface)(nil)).Execute()
+	assert.Nil(t, err)
+	assert.Same(t, impl, iface)
+	assert.Equal(t, 1, created)
+
+	s, err := c.ValueOf("").Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", s)
+
+	t.Run("target in scope", func(t *testing.T) {
+		scope1 := model.NewScope("scope1")
+		c, err := NewContainer(model.NewModule(
+			model.Func(func() *bindTestImpl { return &bindTestImpl{n: 1} }, model.InScope(scope1)),
+			model.Bind((*bindTestInterface)(nil), &bindTestImpl{}),
+		))
+		assert.Nil(t, err)
+
+		sc, err := c.EnterScope(scope1)
+		assert.Nil(t, err)
+		impl, err := sc.ValueOf(&bindTestImpl{}).Execute()
+		assert.Nil(t, err)
+		iface, err := sc.ValueOf((*bindTestInterface)(nil)).Execute()
+		assert.Nil(t, err)
+		assert.Same(t, impl, iface)
+	})
+}
+
+func Test_NewContainer_CycleSearchLimits(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		_, err := NewContainer(denseCyclesModule(12))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "12 providers form a strongly connected group")
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, err := NewContainer(denseCyclesModule(4), CycleSearchLimits(1, 0))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "and more cycles which are not searched")
+
+		_, err = NewContainer(denseCyclesModule(4), CycleSearchLimits(0, 0))
+		assert.NotNil(t, err)
+		assert.NotContains(t, err.Error(), "not searched")
+	})
+
+	t.Run("value in cycles which are not found", func(t *testing.T) {
+		c, err := NewContainer(denseCyclesModule(6), CycleSearchLimits(1, 0), IgnoreCycle())
+		assert.Nil(t, err)
+
+		for i := 0; i < 6; i++ {
+			_, err = c.ValueOf(0, model.ByName(fmt.Sprintf("dense%d", i))).Execute()
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "there are cycles in the dependence path")
+		}
+	})
+
+	t.Run("child", func(t *testing.T) {
+		c, err := NewContainer(model.NewModule())
+		assert.Nil(t, err)
+
+		_, err = c.Child(denseCyclesModule(4), CycleSearchLimits(1, 0))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "and more cycles which are not searched")
+	})
+}
+
+func Test_NewContainer_NoCallLocations(t *testing.T) {
+	m := model.NewModule(model.Value(1))
+	locationOf := func(exe Executor) location.Location {
+		e := exe.(*executor)
+		consumer, ok := e.graph.ConsumerOfNode(e.node)
+		assert.True(t, ok)
+		return consumer.Location()
+	}
+
+	t.Run("default", func(t *testing.T) {
+		c, err := NewContainer(m)
+		assert.Nil(t, err)
+		loc := locationOf(c.ValueOf(0))
+		assert.NotNil(t, loc)
+		assert.Contains(t, loc.FileName(), "container_test.go")
+		assert.True(t, capturesCallLocations(c))
+	})
+
+	t.Run("no call locations", func(t *testing.T) {
+		c, err := NewContainer(m, NoCallLocations())
+		assert.Nil(t, err)
+		assert.Nil(t, locationOf(c.ValueOf(0)))
+		assert.Nil(t, locationOf(c.FuncOf(func(int) {})))
+		assert.Nil(t, locationOf(c.StructOf(struct{}{})))
+		assert.False(t, capturesCallLocations(c))
+
+		sc, err := c.EnterScope(model.NewScope("scope"))
+		assert.Nil(t, err)
+		assert.Nil(t, locationOf(sc.ValueOf(0)))
+
+		child, err := c.Child(model.NewModule())
+		assert.Nil(t, err)
+		assert.Nil(t, locationOf(child.ValueOf(0)))
+
+		val, err := ValueOf(c, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, val)
+	})
+
+	t.Run("no locations at all", func(t *testing.T) {
+		model.CaptureLocations(false)
+		defer model.CaptureLocations(true)
+
+		m := model.NewModule(
+			model.Func(func(a int) string { return "" }),
+			model.Func(func(s string) int { return 0 }),
+		)
+		_, err := NewContainer(m)
+		assert.NotNil(t, err)
+		assert.NotContains(t, err.Error(), "container_test.go")
+		assert.Contains(t, fmt.Sprintf("%+v", model.NewScope("scope")), "scope")
+	})
+}
+
 func Test_newContainer(t *testing.T) {
 	t.Run("no errors", func(t *testing.T) {
 		m := model.NewModule(model.Value(123))
@@ -123,6 +462,22 @@ func Test_newContainer(t *testing.T) {
 	})
 }
 
+func TestNewDependenceGraph(t *testing.T) {
+	g, err := NewDependenceGraph(model.NewModule(model.Value(123)))
+	assert.Nil(t, err)
+	com := model.NewModule(model.Value(123)).AllComponents().ToArray()[0]
+	assert.Equal(t, 1, len(g.Nodes().Filter(func(node Node) bool {
+		c, ok := g.ComponentOfNode(node)
+		return ok && c.Type() == com.Type()
+	}).ToArray()))
+
+	m, _, _, _ := buildTestModule()
+	_, err = NewDependenceGraph(m)
+	assert.NotNil(t, err)
+	_, err = NewDependenceGraph(m, IgnoreMissing(), IgnoreUncertain(), IgnoreCycle())
+	assert.Nil(t, err)
+}
+
 func Test_container_Load(t *testing.T) {
 	t.Run("load", func(t *testing.T) {
 		m, scope1, _ := buildModuleForContainerTest()
@@ -487,6 +842,665 @@ func Test_container_EnterScope(t *testing.T) {
 	})
 }
 
+func Test_container_EnterScope_Seed(t *testing.T) {
+	type request struct{ path string }
+	type handler struct {
+		path   string
+		userID string
+	}
+
+	requestScope := model.NewScopeWith("request",
+		model.ScopeInput(&request{}),
+		model.ScopeInput("", model.Name("userID")),
+		model.ScopeInput(0, model.Name("traceID")),
+	)
+	m := model.NewModule(model.Func(func(r *request, userID string) *handler {
+		return &handler{path: r.path, userID: userID}
+	}, model.InScope(requestScope)))
+
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+
+	t.Run("seed values", func(t *testing.T) {
+		for _, path := range []string{"/a", "/b"} {
+			c1, err := c.EnterScope(requestScope, Seed(&request{path}), NamedSeed("userID", "u"+path))
+			assert.Nil(t, err)
+
+			h, err := c1.ValueOf(&handler{}).Execute()
+			assert.Nil(t, err)
+			assert.Equal(t, &handler{path: path, userID: "u" + path}, h)
+		}
+	})
+
+	t.Run("not seeded", func(t *testing.T) {
+		c1, err := c.EnterScope(requestScope, Seed(&request{"/a"}))
+		assert.Nil(t, err)
+
+		_, err = c1.ValueOf(&handler{}).Execute()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not seeded when entering scope `request`")
+	})
+
+	t.Run("seed matches nothing", func(t *testing.T) {
+		_, err := c.EnterScope(requestScope, Seed(1.5))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "does not match any input")
+	})
+
+	t.Run("seed matches more than one input", func(t *testing.T) {
+		s := model.NewScopeWith("s", model.ScopeInput("", model.Name("a")), model.ScopeInput("", model.Name("b")))
+		c2, err := newContainer(model.NewModule(model.Value(1, model.InScope(s))), nil)
+		assert.Nil(t, err)
+		_, err = c2.EnterScope(s, Seed("a"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "matches more than one input")
+
+		_, err = c2.EnterScope(s, NamedSeed("a", "a"), NamedSeed("a", "a"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is seeded more than once")
+	})
+
+	t.Run("scope is not used", func(t *testing.T) {
+		s := model.NewScopeWith("s", model.ScopeInput(""))
+		_, err := c.EnterScope(s, Seed("a"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "is not used by any provider in the container")
+	})
+}
+
+type closeRecorder struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (r *closeRecorder) Close() error {
+	*r.closed = append(*r.closed, r.name)
+	return r.err
+}
+
+func Test_container_Close(t *testing.T) {
+	type db struct{ *closeRecorder }
+	type repo struct{ *closeRecorder }
+	type request struct{ *closeRecorder }
+
+	requestScope := model.NewScopeWith("request", model.ScopeInput(&request{}))
+	var closed []string
+	m := model.NewModule(
+		model.Func(func() *db {
+			return &db{&closeRecorder{name: "db", closed: &closed}}
+		}),
+		model.Func(func(d *db, r *request) *repo {
+			return &repo{&closeRecorder{name: "repo", closed: &closed}}
+		}, model.InScope(requestScope)),
+	)
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+
+	t.Run("close scoped components", func(t *testing.T) {
+		closed = nil
+		c1, err := c.EnterScope(requestScope,
+			Seed(&request{&closeRecorder{name: "request", closed: &closed}}))
+		assert.Nil(t, err)
+		_, err = c1.ValueOf(&repo{}).Execute()
+		assert.Nil(t, err)
+
+		assert.Nil(t, c1.Close())
+		assert.Equal(t, []string{"repo"}, closed)
+
+		assert.Nil(t, c1.LeaveScope().Close())
+		assert.Equal(t, []string{"repo", "db"}, closed)
+	})
+
+	t.Run("close twice", func(t *testing.T) {
+		closed = nil
+		c1, err := c.EnterScope(requestScope,
+			Seed(&request{&closeRecorder{name: "request", closed: &closed}}))
+		assert.Nil(t, err)
+		_, err = c1.ValueOf(&repo{}).Execute()
+		assert.Nil(t, err)
+
+		assert.Nil(t, c1.Close())
+		assert.Nil(t, c1.Close())
+		assert.Equal(t, []string{"repo"}, closed)
+	})
+
+	t.Run("use after close", func(t *testing.T) {
+		closed = nil
+		c1, err := c.EnterScope(requestScope,
+			Seed(&request{&closeRecorder{name: "request", closed: &closed}}))
+		assert.Nil(t, err)
+		r1, err := c1.ValueOf(&repo{}).Execute()
+		assert.Nil(t, err)
+		assert.Nil(t, c1.Close())
+
+		r2, err := c1.ValueOf(&repo{}).Execute()
+		assert.Nil(t, err)
+		assert.NotSame(t, r1, r2)
+		// the seeded value is kept
+		assert.Equal(t, 2, len(c1.Built(requestScope).ToArray()))
+
+		assert.Nil(t, c1.Close())
+		assert.Equal(t, []string{"repo", "repo"}, closed)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var closed2 []string
+		m2 := model.NewModule(
+			model.Value(1),
+			model.Func(func() *db {
+				return &db{&closeRecorder{name: "db", closed: &closed2, err: errors.Newf("close error")}}
+			}),
+		)
+		c2, err := newContainer(m2, nil)
+		assert.Nil(t, err)
+		_, err = c2.ValueOf(&db{}).Execute()
+		assert.Nil(t, err)
+		_, err = c2.ValueOf(0).Execute()
+		assert.Nil(t, err)
+
+		err = c2.Close()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "close error")
+	})
+
+	t.Run("container is nil", func(t *testing.T) {
+		var c *container
+		assert.NotNil(t, c.Close())
+	})
+}
+
+func Test_container_Evict(t *testing.T) {
+	type db struct{ *closeRecorder }
+	type repo struct {
+		*closeRecorder
+		d *db
+	}
+	type session struct{ *closeRecorder }
+
+	sessionScope := model.NewScope("session")
+	var closed []string
+	m := model.NewModule(
+		model.Func(func() *db {
+			return &db{&closeRecorder{name: "db", closed: &closed}}
+		}),
+		model.Func(func(d *db) *repo {
+			return &repo{&closeRecorder{name: "repo", closed: &closed}, d}
+		}),
+		model.Func(func(r *repo) *session {
+			return &session{&closeRecorder{name: "session", closed: &closed}}
+		}, model.InScope(sessionScope)),
+		model.Value("abc"),
+	)
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+	c1, _ := c.EnterScope(sessionScope)
+
+	r1, err := c1.ValueOf(&repo{}).Execute()
+	assert.Nil(t, err)
+	s1, err := c1.ValueOf(&session{}).Execute()
+	assert.Nil(t, err)
+
+	assert.Nil(t, c1.Evict(model.NewCriteria(&db{})))
+	assert.Equal(t, []string{"db", "session", "repo"}, closed)
+
+	r2, err := c1.ValueOf(&repo{}).Execute()
+	assert.Nil(t, err)
+	assert.NotSame(t, r1, r2)
+	assert.NotSame(t, r1.(*repo).d, r2.(*repo).d)
+	s2, err := c1.ValueOf(&session{}).Execute()
+	assert.Nil(t, err)
+	assert.NotSame(t, s1, s2)
+
+	t.Run("not built", func(t *testing.T) {
+		closed = nil
+		assert.Nil(t, c1.Evict(model.NewCriteria("")))
+		assert.Nil(t, closed)
+	})
+
+	t.Run("no component", func(t *testing.T) {
+		assert.NotNil(t, c1.Evict(model.NewCriteria(0)))
+		assert.NotNil(t, c1.Evict(nil))
+		var nilContainer *container
+		assert.NotNil(t, nilContainer.Evict(model.NewCriteria("")))
+	})
+
+	t.Run("close error", func(t *testing.T) {
+		m2 := model.NewModule(model.Func(func() *db {
+			return &db{&closeRecorder{name: "db", closed: &closed, err: errors.Newf("close error")}}
+		}))
+		c2, _ := newContainer(m2, nil)
+		_, _ = c2.ValueOf(&db{}).Execute()
+		err := c2.Evict(model.NewCriteria(&db{}))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "close error")
+	})
+}
+
+func Test_container_Replace(t *testing.T) {
+	type config struct {
+		*closeRecorder
+		addr string
+	}
+	type client struct {
+		*closeRecorder
+		c *config
+	}
+	type session struct{ *closeRecorder }
+
+	sessionScope := model.NewScope("session")
+	var closed []string
+	m := model.NewModule(
+		model.Func(func() *config {
+			return &config{&closeRecorder{name: "config", closed: &closed}, "addr1"}
+		}),
+		model.Func(func(c *config) *client {
+			return &client{&closeRecorder{name: "client", closed: &closed}, c}
+		}),
+		model.Func(func(c *client) *session {
+			return &session{&closeRecorder{name: "session", closed: &closed}}
+		}, model.InScope(sessionScope)),
+		model.Value("abc"),
+		model.Value("def", model.Name("def")),
+	)
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+	c1, _ := c.EnterScope(sessionScope)
+
+	cl1, err := c1.ValueOf(&client{}).Execute()
+	assert.Nil(t, err)
+	s1, err := c1.ValueOf(&session{}).Execute()
+	assert.Nil(t, err)
+
+	newConfig := &config{&closeRecorder{name: "config2", closed: &closed}, "addr2"}
+	assert.Nil(t, c1.Replace(model.NewCriteria(&config{}), newConfig))
+	assert.Equal(t, []string{"config", "session", "client"}, closed)
+
+	conf, err := c.ValueOf(&config{}).Execute()
+	assert.Nil(t, err)
+	assert.Same(t, newConfig, conf)
+	cl2, err := c1.ValueOf(&client{}).Execute()
+	assert.Nil(t, err)
+	assert.NotSame(t, cl1, cl2)
+	assert.Same(t, newConfig, cl2.(*client).c)
+	s2, err := c1.ValueOf(&session{}).Execute()
+	assert.Nil(t, err)
+	assert.NotSame(t, s1, s2)
+
+	t.Run("evict replaced value", func(t *testing.T) {
+		closed = nil
+		assert.Nil(t, c.Evict(model.NewCriteria(&config{})))
+		assert.Equal(t, []string{"config2"}, closed[:1])
+		conf, err := c.ValueOf(&config{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "addr1", conf.(*config).addr)
+	})
+
+	t.Run("not built", func(t *testing.T) {
+		c2, _ := newContainer(m, nil)
+		assert.Nil(t, c2.Replace(model.NewCriteria("").SetName("def"), "xyz"))
+		v, err := c2.ValueOf("", model.ByName("def")).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "xyz", v)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.NotNil(t, c1.Replace(model.NewCriteria(0), 1))
+		assert.NotNil(t, c1.Replace(model.NewCriteria(""), "xyz"))
+		assert.NotNil(t, c1.Replace(model.NewCriteria(&config{}), "xyz"))
+		assert.NotNil(t, c1.Replace(model.NewCriteria(&config{}), nil))
+		assert.NotNil(t, c1.Replace(nil, 1))
+		assert.NotNil(t, c.Replace(model.NewCriteria(&session{}), &session{}))
+		var nilContainer *container
+		assert.NotNil(t, nilContainer.Replace(model.NewCriteria(""), ""))
+
+		inputScope := model.NewScopeWith("input", model.ScopeInput(0))
+		c3, _ := newContainer(model.NewModule(
+			model.Func(func(i int) int8 { return int8(i) }, model.InScope(inputScope)),
+		), nil)
+		c4, err := c3.EnterScope(inputScope, Seed(1))
+		assert.Nil(t, err)
+		assert.NotNil(t, c4.Replace(model.NewCriteria(0), 2))
+	})
+}
+
+func Test_container_Child(t *testing.T) {
+	type db struct{ *closeRecorder }
+	type plugin struct {
+		*closeRecorder
+		d *db
+	}
+	type missing struct{}
+
+	requestScope := model.NewScope("request")
+	var closed []string
+	parentModule := model.NewModule(
+		model.Func(func() *db {
+			return &db{&closeRecorder{name: "db", closed: &closed}}
+		}),
+		model.Value(1, model.InScope(requestScope)),
+	)
+	childModule := model.NewModule(
+		model.Func(func(d *db) *plugin {
+			return &plugin{&closeRecorder{name: "plugin", closed: &closed}, d}
+		}),
+	)
+
+	c, err := newContainer(parentModule, nil)
+	assert.Nil(t, err)
+	child, err := c.Child(childModule)
+	assert.Nil(t, err)
+
+	t.Run("share instances of parent", func(t *testing.T) {
+		d, err := c.ValueOf(&db{}).Execute()
+		assert.Nil(t, err)
+		p, err := child.ValueOf(&plugin{}).Execute()
+		assert.Nil(t, err)
+		assert.Same(t, d, p.(*plugin).d)
+		d2, err := child.ValueOf(&db{}).Execute()
+		assert.Nil(t, err)
+		assert.Same(t, d, d2)
+	})
+
+	t.Run("parent can not see child components", func(t *testing.T) {
+		_, err := c.ValueOf(&plugin{}).Execute()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("close child", func(t *testing.T) {
+		closed = nil
+		assert.Nil(t, child.Close())
+		assert.Equal(t, []string{"plugin"}, closed)
+	})
+
+	t.Run("in scope", func(t *testing.T) {
+		c1, err := c.EnterScope(requestScope)
+		assert.Nil(t, err)
+		child1, err := c1.Child(model.NewModule(model.Func(func(i int) string { return fmt.Sprint(i) },
+			model.InScope(requestScope))))
+		assert.Nil(t, err)
+		assert.Equal(t, c1.ScopeStack(), child1.ScopeStack())
+		v, err := child1.ValueOf("").Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, "1", v)
+	})
+
+	t.Run("parent ignores missing", func(t *testing.T) {
+		c2, err := NewContainer(model.NewModule(
+			model.Func(func(m missing) string { return "" }),
+			model.Value(1),
+		), IgnoreMissing())
+		assert.Nil(t, err)
+		_, err = c2.Child(model.NewModule(model.Func(func(i int) int8 { return int8(i) })))
+		assert.Nil(t, err)
+	})
+
+	t.Run("replace and evict in parent", func(t *testing.T) {
+		type config struct{ port int }
+		type service struct{ c *config }
+		c2, err := newContainer(model.NewModule(model.Func(func() *config { return &config{80} })), nil)
+		assert.Nil(t, err)
+		child2, err := c2.Child(model.NewModule(model.Func(func(cfg *config) *service { return &service{cfg} })))
+		assert.Nil(t, err)
+
+		s1, err := child2.ValueOf(&service{}).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, 80, s1.(*service).c.port)
+
+		assert.Nil(t, c2.Replace(model.NewCriteria(&config{}), &config{100}))
+		s2, err := child2.ValueOf(&service{}).Execute()
+		assert.Nil(t, err)
+		assert.NotSame(t, s1, s2)
+		assert.Equal(t, 100, s2.(*service).c.port)
+
+		assert.Nil(t, c2.Evict(model.NewCriteria(&config{})))
+		s3, err := child2.ValueOf(&service{}).Execute()
+		assert.Nil(t, err)
+		assert.NotSame(t, s2, s3)
+		assert.Equal(t, 80, s3.(*service).c.port)
+	})
+
+	t.Run("errors of child", func(t *testing.T) {
+		_, err := c.Child(model.NewModule(model.Func(func(m missing) string { return "" })))
+		assert.NotNil(t, err)
+
+		_, err = c.Child(model.NewModule(
+			model.Func(func() *db { return &db{} }),
+			model.Func(func(d *db) string { return "" }),
+		))
+		assert.NotNil(t, err)
+
+		_, err = c.Child(nil)
+		assert.NotNil(t, err)
+
+		var nilContainer *container
+		_, err = nilContainer.Child(model.NewModule())
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_container_CachePolicy(t *testing.T) {
+	t.Run("ttl", func(t *testing.T) {
+		counter := 0
+		m := model.NewModule(
+			model.Func(func() int {
+				counter += 1
+				return counter
+			}, model.Cache(model.CachePolicy{TTL: time.Minute})),
+			model.Func(func(i int) string { return fmt.Sprint(i) }),
+		)
+		c, err := newContainer(m, nil)
+		assert.Nil(t, err)
+		now := time.Now()
+		c.storage.shared.hooks.now = func() time.Time { return now }
+
+		v, _ := c.ValueOf("").Execute()
+		assert.Equal(t, "1", v)
+		now = now.Add(59 * time.Second)
+		v, _ = c.ValueOf("").Execute()
+		assert.Equal(t, "1", v)
+		now = now.Add(time.Second)
+		v, _ = c.ValueOf("").Execute()
+		assert.Equal(t, "2", v)
+	})
+
+	t.Run("max entries", func(t *testing.T) {
+		type session struct{ *closeRecorder }
+		sessionScope := model.NewScopeWith("session", model.Cache(model.CachePolicy{MaxEntries: 1}))
+		var closed []string
+		m := model.NewModule(
+			model.Func(func() *session {
+				return &session{&closeRecorder{name: "session", closed: &closed}}
+			}, model.InScope(sessionScope)),
+			model.Func(func() int { return 1 }, model.InScope(sessionScope)),
+		)
+		c, err := newContainer(m, nil)
+		assert.Nil(t, err)
+		c1, _ := c.EnterScope(sessionScope)
+
+		s1, _ := c1.ValueOf(&session{}).Execute()
+		s2, _ := c1.ValueOf(&session{}).Execute()
+		assert.Same(t, s1, s2)
+		_, _ = c1.ValueOf(0).Execute()
+		assert.Equal(t, []string{"session"}, closed)
+		s3, _ := c1.ValueOf(&session{}).Execute()
+		assert.NotSame(t, s1, s3)
+	})
+}
+
+func Test_container_EnterScope_OptionalSeed(t *testing.T) {
+	s := model.NewScopeWith("s", model.ScopeInput(""), model.ScopeInput(0))
+	c, err := newContainer(model.NewModule(model.Func(func(s string) float64 {
+		return 1
+	}, model.InScope(s))), nil)
+	assert.Nil(t, err)
+
+	c1, err := c.EnterScope(s, OptionalSeed("a", 1, true))
+	assert.Nil(t, err)
+	v, err := c1.ValueOf("").Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", v)
+}
+
+func Test_container_EnterScopePath(t *testing.T) {
+	type request struct{ path string }
+	s1 := model.NewScope("s1")
+	s2 := model.NewScopeWith("s2", s1, model.ScopeInput(&request{}))
+	s3 := model.NewScopeWith("s3", s2, model.ScopeInput(""))
+	s4 := model.NewScope("s4", s1)
+	s5 := model.NewScope("s5", s3, s4)
+	m := model.NewModule(
+		model.Func(func(r *request, user string) int {
+			return len(r.path + user)
+		}, model.InScope(s3)),
+		model.Func(func() float64 { return 1 }, model.InScope(s5)),
+	)
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+
+	t.Run("enter", func(t *testing.T) {
+		c3, err := c.EnterScopePath(s3, Seed(&request{path: "/a"}, "bc"))
+		assert.Nil(t, err)
+		assert.Equal(t, s3, c3.Scope())
+		assert.Equal(t, s2, c3.LeaveScope().Scope())
+		assert.Equal(t, s1, c3.LeaveScope().LeaveScope().Scope())
+
+		v, err := c3.ValueOf(0).Execute()
+		assert.Nil(t, err)
+		assert.Equal(t, 4, v)
+	})
+
+	t.Run("seed not match", func(t *testing.T) {
+		_, err := c.EnterScopePath(s3, Seed(&request{}, "", 1))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := c.EnterScopePath(s5)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "more than one path")
+
+		c4, err := c.EnterScopePath(s4)
+		assert.Nil(t, err)
+		c5, err := c4.EnterScopePath(s5)
+		assert.Nil(t, err)
+		assert.Equal(t, s5, c5.Scope())
+	})
+
+	t.Run("can not enter", func(t *testing.T) {
+		c3, _ := c.EnterScopePath(s3, Seed(&request{}, ""))
+		_, err := c3.EnterScopePath(s1)
+		assert.NotNil(t, err)
+		_, err = c.EnterScopePath(nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("container is nil", func(t *testing.T) {
+		var c *container
+		_, err := c.EnterScopePath(s1)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_container_LeaveScopeTo(t *testing.T) {
+	m, scope1, scope2 := buildModuleForContainerTest()
+	c, _ := newContainer(m, nil)
+	c1, _ := c.EnterScope(scope1)
+	c2, _ := c1.EnterScope(scope2)
+
+	c3, err := c2.LeaveScopeTo(model.GlobalScope)
+	assert.Nil(t, err)
+	assert.Equal(t, model.GlobalScope, c3.Scope())
+
+	c4, err := c2.LeaveScopeTo(scope1)
+	assert.Nil(t, err)
+	assert.Equal(t, scope1, c4.Scope())
+
+	c5, err := c2.LeaveScopeTo(scope2)
+	assert.Nil(t, err)
+	assert.Equal(t, scope2, c5.Scope())
+
+	_, err = c1.LeaveScopeTo(scope2)
+	assert.NotNil(t, err)
+
+	var nilContainer *container
+	_, err = nilContainer.LeaveScopeTo(scope1)
+	assert.NotNil(t, err)
+}
+
+func Test_container_ScopeStack(t *testing.T) {
+	m, scope1, scope2 := buildModuleForContainerTest()
+	c, _ := newContainer(m, nil)
+	c2, _ := c.EnterScopePath(scope2)
+
+	assert.Equal(t, []model.Scope{model.GlobalScope}, c.ScopeStack())
+	assert.Equal(t, []model.Scope{model.GlobalScope, scope1, scope2}, c2.ScopeStack())
+
+	var nilContainer *container
+	assert.Nil(t, nilContainer.ScopeStack())
+}
+
+func Test_container_Built(t *testing.T) {
+	type request struct{ path string }
+	s1 := model.NewScopeWith("s1", model.ScopeInput(&request{}))
+	m := model.NewModule(
+		model.Value("abc"),
+		model.Func(func(s string) int { return len(s) }),
+		model.Func(func(r *request, i int) float64 { return float64(i) }, model.InScope(s1)),
+	)
+	c, err := newContainer(m, nil)
+	assert.Nil(t, err)
+	c1, err := c.EnterScope(s1, Seed(&request{}))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, len(c1.Built(model.GlobalScope).ToArray()))
+	assert.Equal(t, 1, len(c1.Built(s1).ToArray()))
+
+	_, err = c1.ValueOf(0.0).Execute()
+	assert.Nil(t, err)
+
+	global := c1.Built(model.GlobalScope).ToArray()
+	assert.Equal(t, 2, len(global))
+	assert.Equal(t, reflect.TypeOf(""), global[0].Type())
+	assert.Equal(t, reflect.TypeOf(0), global[1].Type())
+
+	scoped := c1.Built(s1).ToArray()
+	assert.Equal(t, 2, len(scoped))
+	assert.Equal(t, reflect.TypeOf(&request{}), scoped[0].Type())
+	assert.Equal(t, reflect.TypeOf(0.0), scoped[1].Type())
+
+	assert.Equal(t, 0, len(c.Built(s1).ToArray()))
+
+	var nilContainer *container
+	assert.Equal(t, 0, len(nilContainer.Built(s1).ToArray()))
+}
+
+func Test_container_Scopes(t *testing.T) {
+	m, scope1, scope2 := buildModuleForContainerTest()
+	c, _ := newContainer(m, nil)
+	assert.Equal(t, []model.Scope{model.GlobalScope, scope1, scope2}, c.Scopes())
+
+	var nilContainer *container
+	assert.Nil(t, nilContainer.Scopes())
+}
+
+func Test_container_Components(t *testing.T) {
+	m, scope1, _ := buildModuleForContainerTest()
+	c, _ := newContainer(m, nil)
+	assert.Equal(t, 0, len(c.Components(model.NewCriteria(testStruct{})).ToArray()))
+	assert.Equal(t, 1, len(c.Components(model.NewCriteria(0)).ToArray()))
+
+	c1, _ := c.EnterScope(scope1)
+	coms := c1.Components(model.NewCriteria(testStruct{})).ToArray()
+	assert.Equal(t, 1, len(coms))
+	assert.Equal(t, "name3", coms[0].Name())
+	assert.Equal(t, 0, len(c1.Components(model.NewCriteria(testStruct3{})).ToArray()))
+
+	assert.Equal(t, 0, len(c1.Components(nil).ToArray()))
+	var nilContainer *container
+	assert.Equal(t, 0, len(nilContainer.Components(model.NewCriteria(0)).ToArray()))
+}
+
 func Test_container_LeaveScope(t *testing.T) {
 	t.Run("leave", func(t *testing.T) {
 		m, scope1, scope2 := buildModuleForContainerTest()
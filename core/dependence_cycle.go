@@ -2,6 +2,9 @@ package core
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/jison/uni/core/model"
 
 	"github.com/jison/uni/core/valuer"
 	"github.com/jison/uni/graph"
@@ -11,14 +14,38 @@ type DependenceCycle interface {
 	Nodes() NodeIterator
 }
 
+// DependenceCycleGroup is a strongly connected group of nodes, every two nodes in it depend on
+// each other
+type DependenceCycleGroup interface {
+	Nodes() NodeIterator
+	Providers() []model.Provider
+	// ShortestCycle returns the shortest cycle found, it may not be the shortest of the group if
+	// the search is not complete
+	ShortestCycle() DependenceCycle
+	// Cycles returns cycles in the group which are found within limits of the search
+	Cycles() []DependenceCycle
+	// Complete returns false if the search stopped at its limits, then Cycles may miss some
+	// cycles of the group, and ShortestCycle may not be the shortest
+	Complete() bool
+}
+
 type DependenceCycleInfo interface {
 	CyclesOfNode(node Node) []DependenceCycle
+	// Cycles returns cycles found within limits of the search, see Complete
 	Cycles() []DependenceCycle
+	// Complete returns false if the search stopped at its limits and not all cycles are found,
+	// cycles of a node may be empty even if it is in a cycle
+	Complete() bool
+	Groups() []DependenceCycleGroup
+	GroupOfNode(node Node) (DependenceCycleGroup, bool)
 }
 
 type dependenceCycleInfo struct {
 	cycles       []DependenceCycle
 	cyclesByNode map[Node][]DependenceCycle
+	complete     bool
+	groups       []DependenceCycleGroup
+	groupByNode  map[Node]*dependenceCycleGroup
 }
 
 func (ci *dependenceCycleInfo) CyclesOfNode(node Node) []DependenceCycle {
@@ -29,14 +56,63 @@ func (ci *dependenceCycleInfo) Cycles() []DependenceCycle {
 	return ci.cycles
 }
 
-func buildCycleInfoOf(dg DependenceGraph) DependenceCycleInfo {
-	gCycles := graph.FindCycles(dg.Graph())
+func (ci *dependenceCycleInfo) Complete() bool {
+	return ci.complete
+}
+
+func (ci *dependenceCycleInfo) Groups() []DependenceCycleGroup {
+	return ci.groups
+}
+
+func (ci *dependenceCycleInfo) GroupOfNode(node Node) (DependenceCycleGroup, bool) {
+	group, ok := ci.groupByNode[node]
+	if !ok {
+		return nil, false
+	}
+	return group, true
+}
+
+// defaultCycleLimits bounds the search of cycles, so that densely cyclic graphs don't hang
+// the creation of containers. Groups of cycles are always found.
+var defaultCycleLimits = graph.CycleLimits{MaxCycles: 100, Timeout: time.Second}
+
+func buildCycleInfoOf(dg DependenceGraph, limits graph.CycleLimits) DependenceCycleInfo {
+	// the timeout covers both searches of shortest cycles and of all cycles
+	var deadline time.Time
+	if limits.Timeout > 0 {
+		deadline = time.Now().Add(limits.Timeout)
+	}
+
+	var groups []*dependenceCycleGroup
+	groupByNode := map[Node]*dependenceCycleGroup{}
+	for _, scc := range graph.CyclicComponents(dg.Graph()) {
+		group := newDependenceCycleGroup(dg, scc, deadline)
+		if group == nil {
+			continue
+		}
+		for _, n := range group.nodes {
+			groupByNode[n] = group
+		}
+		groups = append(groups, group)
+	}
+
+	if !deadline.IsZero() {
+		limits.Timeout = time.Until(deadline)
+		if limits.Timeout <= 0 {
+			// a zero timeout means no limit
+			limits.Timeout = time.Nanosecond
+		}
+	}
+	gCycles, complete := graph.FindCyclesWithLimits(dg.Graph(), limits)
 
 	var cycles []DependenceCycle
 	cyclesByNode := map[Node][]DependenceCycle{}
 	for _, gCycle := range gCycles {
 		cycle := dependenceCycleFromGCycle(dg, gCycle)
 		if cycle != nil {
+			if group, ok := groupByNode[cycle.node]; ok {
+				group.cycles = append(group.cycles, cycle)
+			}
 			cur := cycle
 			for {
 				cyclesByNode[cur.node] = append(cyclesByNode[cur.node], cur)
@@ -50,9 +126,18 @@ func buildCycleInfoOf(dg DependenceGraph) DependenceCycleInfo {
 		}
 	}
 
+	var groupArr []DependenceCycleGroup
+	for _, group := range groups {
+		group.complete = group.complete && complete
+		groupArr = append(groupArr, group)
+	}
+
 	return &dependenceCycleInfo{
 		cycles:       cycles,
 		cyclesByNode: cyclesByNode,
+		complete:     complete,
+		groups:       groupArr,
+		groupByNode:  groupByNode,
 	}
 }
 
@@ -120,3 +205,82 @@ func (c *dependenceCycleNode) Format(fs fmt.State, r rune) {
 	_, _ = fmt.Fprint(fs, "cycle:")
 	_formatNodes(c.graph, c, fs, r)
 }
+
+type dependenceCycleGroup struct {
+	graph    DependenceGraph
+	nodes    NodeSlice
+	shortest *dependenceCycleNode
+	cycles   []DependenceCycle
+	complete bool
+}
+
+// newDependenceCycleGroup finds the shortest cycle of the group by searching from every node
+// until the deadline, searches only look for cycles shorter than the shortest found
+func newDependenceCycleGroup(dg DependenceGraph, scc []graph.Node, deadline time.Time) *dependenceCycleGroup {
+	sccGraph := graph.SubGraphWithNodes(dg.Graph(), scc)
+	var shortest graph.Cycle
+	complete := true
+	for i, n := range scc {
+		// the first search always runs, every node of the group is in a cycle
+		if i > 0 && !deadline.IsZero() && time.Now().After(deadline) {
+			complete = false
+			break
+		}
+		if c, ok := graph.ShortestCycleThroughWithin(sccGraph, n, len(shortest)); ok {
+			shortest = c
+		}
+	}
+
+	shortestCycle := dependenceCycleFromGCycle(dg, shortest)
+	if shortestCycle == nil {
+		return nil
+	}
+	var nodes NodeSlice
+	for _, n := range scc {
+		if valNode, ok := n.(valuer.Valuer); ok {
+			nodes = append(nodes, valNode)
+		}
+	}
+	return &dependenceCycleGroup{
+		graph:    dg,
+		nodes:    nodes,
+		shortest: shortestCycle,
+		complete: complete,
+	}
+}
+
+func (g *dependenceCycleGroup) Nodes() NodeIterator {
+	return g.nodes
+}
+
+func (g *dependenceCycleGroup) Providers() []model.Provider {
+	var providers []model.Provider
+	for _, n := range g.nodes {
+		if provider, ok := g.graph.ProviderOfNode(n); ok {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+func (g *dependenceCycleGroup) ShortestCycle() DependenceCycle {
+	return g.shortest
+}
+
+func (g *dependenceCycleGroup) Cycles() []DependenceCycle {
+	return g.cycles
+}
+
+func (g *dependenceCycleGroup) Complete() bool {
+	return g.complete
+}
+
+func (g *dependenceCycleGroup) Format(fs fmt.State, r rune) {
+	if g.complete {
+		_, _ = fmt.Fprintf(fs, "%d providers form a strongly connected group, the shortest ", len(g.Providers()))
+	} else {
+		_, _ = fmt.Fprintf(fs, "%d providers form a strongly connected group, the shortest found ",
+			len(g.Providers()))
+	}
+	g.shortest.Format(fs, r)
+}
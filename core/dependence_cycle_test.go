@@ -2,10 +2,13 @@ package core
 
 import (
 	"fmt"
-	"github.com/jison/uni/core/valuer"
-	"github.com/jison/uni/graph"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/graph"
 
 	"github.com/jison/uni/core/model"
 	"github.com/stretchr/testify/assert"
@@ -126,7 +129,7 @@ func verifyComponentsInOneCycle(t *testing.T, g DependenceGraph, rep model.Compo
 func Test_buildCycleInfoOf(t *testing.T) {
 	t.Run("no cycles", func(t *testing.T) {
 		g := graphWithoutCycle()
-		cycleInfo := buildCycleInfoOf(g)
+		cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
 		assert.Equal(t, 0, len(cycleInfo.Cycles()))
 
 		g.Nodes().Iterate(func(node Node) bool {
@@ -137,7 +140,7 @@ func Test_buildCycleInfoOf(t *testing.T) {
 
 	t.Run("one cycle", func(t *testing.T) {
 		g, _ := graphWithOneCycle()
-		cycleInfo := buildCycleInfoOf(g)
+		cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
 		assert.Equal(t, 1, len(cycleInfo.Cycles()))
 
 		cycle := cycleInfo.Cycles()[0]
@@ -155,7 +158,7 @@ func Test_buildCycleInfoOf(t *testing.T) {
 	t.Run("cycles with crossing node", func(t *testing.T) {
 		g, rep := graphWithCrossingCycles()
 
-		cycleInfo := buildCycleInfoOf(g)
+		cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
 		assert.Equal(t, 4, len(cycleInfo.Cycles()))
 		for _, cycle := range cycleInfo.Cycles() {
 			verifyCycleIsInGraph(t, g, cycle)
@@ -174,10 +177,121 @@ func Test_buildCycleInfoOf(t *testing.T) {
 	})
 }
 
+// denseCyclesModule returns a module of n providers, each of them depends on all the others,
+// so the count of cycles is exponential to n
+func denseCyclesModule(n int) model.Module {
+	nameOf := func(i int) string { return fmt.Sprintf("dense%d", i) }
+	intType := reflect.TypeOf(0)
+
+	var providers []model.ModuleOption
+	for i := 0; i < n; i++ {
+		var in []reflect.Type
+		opts := []model.FuncProviderOption{model.Return(0, model.Name(nameOf(i)))}
+		for j := 0; j < n; j++ {
+			if j != i {
+				opts = append(opts, model.Param(len(in), model.ByName(nameOf(j))))
+				in = append(in, intType)
+			}
+		}
+		fn := reflect.MakeFunc(reflect.FuncOf(in, []reflect.Type{intType}, false),
+			func([]reflect.Value) []reflect.Value { return []reflect.Value{reflect.ValueOf(0)} })
+		providers = append(providers, model.Func(fn.Interface(), opts...))
+	}
+	return model.NewModule(providers...)
+}
+
+func graphWithDenseCycles(n int) DependenceGraph {
+	return newDependenceGraph(model.NewRepository(denseCyclesModule(n).AllComponents()))
+}
+
+func Test_buildCycleInfoOf_groups(t *testing.T) {
+	t.Run("no cycles", func(t *testing.T) {
+		cycleInfo := buildCycleInfoOf(graphWithoutCycle(), defaultCycleLimits)
+		assert.True(t, cycleInfo.Complete())
+		assert.Empty(t, cycleInfo.Groups())
+	})
+
+	t.Run("cycles with crossing node", func(t *testing.T) {
+		g, rep := graphWithCrossingCycles()
+		cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
+		assert.True(t, cycleInfo.Complete())
+		assert.Len(t, cycleInfo.Groups(), 1)
+		assert.True(t, cycleInfo.Groups()[0].Complete())
+
+		group := cycleInfo.Groups()[0]
+		assert.Len(t, group.Providers(), 5)
+		assert.Len(t, group.Cycles(), 4)
+		shortest := NewNodeCollection(group.ShortestCycle().Nodes()).ToArray()
+		allNodes := newNodeSet()
+		for _, cycle := range group.Cycles() {
+			nodes := NewNodeCollection(cycle.Nodes()).ToArray()
+			assert.LessOrEqual(t, len(shortest), len(nodes))
+			for _, n := range nodes {
+				allNodes.Add(n)
+			}
+		}
+		verifyCycleIsInGraph(t, g, group.ShortestCycle())
+		assert.Equal(t, NodeSet(allNodes), NewNodeCollection(group.Nodes()).ToSet())
+
+		g.Nodes().Iterate(func(node Node) bool {
+			nodeGroup, ok := cycleInfo.GroupOfNode(node)
+			assert.True(t, ok)
+			assert.Same(t, group, nodeGroup)
+			return true
+		})
+
+		s := fmt.Sprintf("%v", group)
+		assert.True(t, strings.HasPrefix(s, "5 providers form a strongly connected group, the shortest cycle:"))
+		assert.Contains(t, s, fmt.Sprintf("%+v", comByName(rep, "name1").Provider()))
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		g, _ := graphWithCrossingCycles()
+		cycleInfo := buildCycleInfoOf(g, graph.CycleLimits{MaxCycles: 1})
+		assert.False(t, cycleInfo.Complete())
+		assert.Len(t, cycleInfo.Cycles(), 1)
+		assert.Len(t, cycleInfo.Groups(), 1)
+		assert.Len(t, cycleInfo.Groups()[0].Cycles(), 1)
+		assert.False(t, cycleInfo.Groups()[0].Complete())
+
+		g.Nodes().Iterate(func(node Node) bool {
+			_, ok := cycleInfo.GroupOfNode(node)
+			assert.True(t, ok)
+			return true
+		})
+	})
+
+	t.Run("timeout of the shortest cycle", func(t *testing.T) {
+		g, _ := graphWithCrossingCycles()
+		group := newDependenceCycleGroup(g, graph.CyclicComponents(g.Graph())[0], time.Now())
+		assert.NotNil(t, group)
+		assert.False(t, group.Complete())
+		verifyCycleIsInGraph(t, g, group.ShortestCycle())
+		assert.Contains(t, fmt.Sprintf("%v", group), "the shortest found cycle:")
+
+		complete := newDependenceCycleGroup(g, graph.CyclicComponents(g.Graph())[0], time.Time{})
+		assert.True(t, complete.Complete())
+		assert.LessOrEqual(t, len(NewNodeCollection(complete.ShortestCycle().Nodes()).ToArray()),
+			len(NewNodeCollection(group.ShortestCycle().Nodes()).ToArray()))
+	})
+
+	t.Run("dense cycles", func(t *testing.T) {
+		g := graphWithDenseCycles(12)
+		start := time.Now()
+		cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.False(t, cycleInfo.Complete())
+		assert.Len(t, cycleInfo.Cycles(), defaultCycleLimits.MaxCycles)
+		assert.Len(t, cycleInfo.Groups(), 1)
+		assert.Len(t, cycleInfo.Groups()[0].Providers(), 12)
+		assert.False(t, cycleInfo.Groups()[0].Complete())
+	})
+}
+
 func Test_dependenceCycleNode(t *testing.T) {
 	g, rep := graphWithOneCycle()
 
-	cycleInfo := buildCycleInfoOf(g)
+	cycleInfo := buildCycleInfoOf(g, defaultCycleLimits)
 	com1 := comByName(rep, "name1")
 	cycle := cycleInfo.CyclesOfNode(com1.Valuer())[0]
 
@@ -66,6 +66,10 @@ type DependenceGraph interface {
 	NodeOfProvider(provider model.Provider) (Node, bool)
 
 	Derive(consumer model.Consumer) (DependenceGraph, Node)
+	// DeriveRepository derives a graph with components in the repository, dependencies of them
+	// can be resolved to components in this graph, but not vice versa. Only the new part of
+	// the derived graph is validated
+	DeriveRepository(rep model.ComponentRepository) DependenceGraph
 
 	CycleInfo() DependenceCycleInfo
 
@@ -78,10 +82,14 @@ type DependenceGraph interface {
 	MissingError() error
 	UncertainError() error
 	CycleError() error
+	// ErrorOfNode returns errors of missing, uncertain and cyclic dependencies which the node depends on
+	ErrorOfNode(node Node) error
 }
 
 type dependenceGraph struct {
 	parent *dependenceGraph
+	// isolated graphs are validated without the parent
+	isolated bool
 
 	graph      graph.DirectedGraph
 	repository model.ComponentRepository
@@ -92,7 +100,9 @@ type dependenceGraph struct {
 
 	missingDependencies   []model.Dependency
 	uncertainDependencies []model.Dependency
+	nearMisses            *nearMissCache
 
+	cycleLimits       graph.CycleLimits
 	cycleInfoInitOnce sync.Once
 	cycleInfo         DependenceCycleInfo
 }
@@ -240,7 +250,7 @@ func (dg *dependenceGraph) addMissingNodeOf(dep model.Dependency) Node {
 	} else {
 		dg.missingDependencies = append(dg.missingDependencies, dep)
 
-		v := valuer.Error(&missingError{dep})
+		v := valuer.Error(&missingError{dependency: dep, nearMisses: dg.nearMisses})
 		return v
 	}
 }
@@ -265,6 +275,8 @@ func (dg *dependenceGraph) Derive(consumer model.Consumer) (DependenceGraph, Nod
 		nodeByComponent:  map[model.Component]Node{},
 		nodeByConsumer:   map[model.Consumer]Node{},
 		nodeByDependency: map[model.Dependency]Node{},
+		nearMisses:       dg.nearMisses,
+		cycleLimits:      dg.cycleLimits,
 	}
 
 	consumerNode := derived.addNodeOfConsumer(consumer)
@@ -272,6 +284,44 @@ func (dg *dependenceGraph) Derive(consumer model.Consumer) (DependenceGraph, Nod
 	return derived, consumerNode
 }
 
+func (dg *dependenceGraph) DeriveRepository(rep model.ComponentRepository) DependenceGraph {
+	derived := &dependenceGraph{
+		parent:           dg,
+		isolated:         true,
+		graph:            graph.DeriveDirectedGraph(dg.graph),
+		repository:       model.LayerRepository(dg.repository, rep),
+		nodeByComponent:  map[model.Component]Node{},
+		nodeByConsumer:   map[model.Consumer]Node{},
+		nodeByDependency: map[model.Dependency]Node{},
+		cycleLimits:      dg.cycleLimits,
+	}
+	derived.nearMisses = &nearMissCache{repository: derived.repository}
+
+	rep.AllComponents().Iterate(func(com model.Component) bool {
+		derived.addNodeOfComponent(com)
+		return true
+	})
+
+	return derived
+}
+
+// ownNode returns true if the node is added in this graph but not in the parent
+func (dg *dependenceGraph) ownNode(node Node) bool {
+	if com, ok := dg.ComponentOfNode(node); ok {
+		_, own := dg.nodeByComponent[com]
+		return own
+	}
+	if con, ok := dg.ConsumerOfNode(node); ok {
+		_, own := dg.nodeByConsumer[con]
+		return own
+	}
+	if dep, ok := dg.DependencyOfNode(node); ok {
+		_, own := dg.nodeByDependency[dep]
+		return own
+	}
+	return false
+}
+
 func (dg *dependenceGraph) attrOfNode(node Node, key nodeAttrKey) (interface{}, bool) {
 	var attrs graph.AttrsView
 	var ok bool
@@ -347,7 +397,7 @@ func (dg *dependenceGraph) CycleInfo() DependenceCycleInfo {
 	}
 
 	dg.cycleInfoInitOnce.Do(func() {
-		dg.cycleInfo = buildCycleInfoOf(dg)
+		dg.cycleInfo = buildCycleInfoOf(dg, dg.cycleLimits)
 	})
 
 	return dg.cycleInfo
@@ -411,7 +461,7 @@ func (dg *dependenceGraph) InputComponentsTo(com model.Component) model.Componen
 
 func (dg *dependenceGraph) allMissingDependencies() model.DependencyIterator {
 	selfDeps := model.ArrayDependencyIterator(dg.missingDependencies)
-	if dg.parent == nil {
+	if dg.parent == nil || dg.isolated {
 		return selfDeps
 	}
 
@@ -419,9 +469,24 @@ func (dg *dependenceGraph) allMissingDependencies() model.DependencyIterator {
 }
 
 func (dg *dependenceGraph) MissingError() error {
+	return dg.missingErrorOf(dg.allMissingDependencies())
+}
+
+func (dg *dependenceGraph) missingErrorOf(deps model.DependencyIterator) error {
 	errs := errors.Empty()
-	dg.allMissingDependencies().Iterate(func(dep model.Dependency) bool {
-		err := errors.Newf("%v in %v at %v", dep, dep.Consumer().Scope(), dep.Consumer().Location())
+	deps.Iterate(func(dep model.Dependency) bool {
+		missing := &MissingDependencyError{
+			Dependency: dep,
+			Blocked:    dg.repository.BlockedComponentsOfDependency(dep),
+			NearMisses: dg.nearMisses.of(dep),
+		}
+		err := errors.New(missing)
+		for _, b := range missing.Blocked {
+			err = err.AddErrorf("%v", blockedReason(b))
+		}
+		for _, suggestion := range nearMissSuggestions(dep, missing.NearMisses) {
+			err = err.AddErrorf("%v", suggestion)
+		}
 		errs = errs.AddErrors(err)
 		return true
 	})
@@ -435,7 +500,7 @@ func (dg *dependenceGraph) MissingError() error {
 
 func (dg *dependenceGraph) allUncertainDependencies() model.DependencyIterator {
 	selfDeps := model.ArrayDependencyIterator(dg.uncertainDependencies)
-	if dg.parent == nil {
+	if dg.parent == nil || dg.isolated {
 		return selfDeps
 	}
 
@@ -443,16 +508,22 @@ func (dg *dependenceGraph) allUncertainDependencies() model.DependencyIterator {
 }
 
 func (dg *dependenceGraph) UncertainError() error {
+	return dg.uncertainErrorOf(dg.allUncertainDependencies())
+}
+
+func (dg *dependenceGraph) uncertainErrorOf(deps model.DependencyIterator) error {
 	errs := errors.Empty()
-	dg.allUncertainDependencies().Iterate(func(dep model.Dependency) bool {
+	deps.Iterate(func(dep model.Dependency) bool {
 		notUniqueErr := errors.Empty()
+		uncertain := &UncertainDependencyError{Dependency: dep}
 
 		dg.InputComponentsToDependency(dep).Each(func(com model.Component) {
+			uncertain.Candidates = append(uncertain.Candidates, com)
 			notUniqueErr = notUniqueErr.AddErrorf("%v at %v", com, com.Provider().Location())
 		})
 
 		if notUniqueErr.HasError() {
-			errs = errs.AddErrors(notUniqueErr.WithMainf("%v at %v", dep, dep.Consumer().Location()))
+			errs = errs.AddErrors(notUniqueErr.WithMain(uncertain))
 		}
 
 		return true
@@ -465,11 +536,17 @@ func (dg *dependenceGraph) UncertainError() error {
 	return nil
 }
 
+// maxCyclesInGroupError is the most cycles listed in the error of a group of cycles
+const maxCyclesInGroupError = 5
+
 func (dg *dependenceGraph) CycleError() error {
 	errs := errors.Empty()
-	cycles := dg.CycleInfo().Cycles()
-	for _, cycle := range cycles {
-		errs = errs.AddErrorf("%v", cycle)
+	cycleInfo := dg.CycleInfo()
+	for _, group := range cycleInfo.Groups() {
+		if dg.isolated && !dg.hasOwnNode(group.Nodes()) {
+			continue
+		}
+		errs = errs.AddErrors(dg.cycleGroupError(group))
 	}
 
 	if errs.HasError() {
@@ -479,6 +556,45 @@ func (dg *dependenceGraph) CycleError() error {
 	return nil
 }
 
+// cycleGroupError reports the only cycle of the group, or a summary of the group with some of its cycles
+func (dg *dependenceGraph) cycleGroupError(group DependenceCycleGroup) error {
+	complete := group.Complete()
+	cycleErr := &DependenceCycleError{Group: group, Complete: complete}
+	group.ShortestCycle().Nodes().Iterate(func(node Node) bool {
+		if dep, ok := dg.DependencyOfNode(node); ok {
+			cycleErr.Dependencies = append(cycleErr.Dependencies, dep)
+		}
+		return true
+	})
+
+	cycles := group.Cycles()
+	if len(cycles) == 1 && complete {
+		return errors.New(cycleErr)
+	}
+
+	err := errors.New(cycleErr)
+	for i, cycle := range cycles {
+		if i == maxCyclesInGroupError {
+			err = err.AddErrorf("and %d more cycles", len(cycles)-i)
+			break
+		}
+		err = err.AddErrorf("%v", cycle)
+	}
+	if !complete {
+		err = err.AddErrorf("and more cycles which are not searched, see CycleSearchLimits")
+	}
+	return err
+}
+
+func (dg *dependenceGraph) hasOwnNode(ni NodeIterator) bool {
+	hasOwn := false
+	ni.Iterate(func(node Node) bool {
+		hasOwn = dg.ownNode(node)
+		return !hasOwn
+	})
+	return hasOwn
+}
+
 func (dg *dependenceGraph) Validate() error {
 	errs := errors.Empty()
 
@@ -509,6 +625,8 @@ func newDependenceGraph(rep model.ComponentRepository) DependenceGraph {
 		nodeByComponent:  map[model.Component]Node{},
 		nodeByConsumer:   map[model.Consumer]Node{},
 		nodeByDependency: map[model.Dependency]Node{},
+		nearMisses:       &nearMissCache{repository: rep},
+		cycleLimits:      defaultCycleLimits,
 	}
 
 	rep.AllComponents().Iterate(func(com model.Component) bool {
@@ -521,9 +639,191 @@ func newDependenceGraph(rep model.ComponentRepository) DependenceGraph {
 
 type missingError struct {
 	dependency model.Dependency
+	nearMisses *nearMissCache
 }
 
 func (e *missingError) Error() string {
-	return fmt.Sprintf("can not find components that match %v in %v", e.dependency,
+	msg := fmt.Sprintf("can not find components that match %v in %v", e.dependency,
 		e.dependency.Consumer().Scope())
+	if e.nearMisses != nil && e.nearMisses.repository != nil {
+		for _, b := range e.nearMisses.repository.BlockedComponentsOfDependency(e.dependency) {
+			msg += ", " + blockedReason(b)
+		}
+	}
+	for _, suggestion := range nearMissSuggestions(e.dependency, e.nearMisses.of(e.dependency)) {
+		msg += ", " + suggestion
+	}
+	return msg
+}
+
+// MissingDependencyError is the main error of a dependency which no component matches, in errors
+// of NewContainer. Tools may walk the errors to report them in their own way
+type MissingDependencyError struct {
+	Dependency model.Dependency
+	// Blocked are components matching the dependency, but not visible to it
+	Blocked []model.BlockedComponent
+	// NearMisses are components nearly matching the dependency, the most likely first
+	NearMisses []model.NearMiss
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("%v in %v at %v", e.Dependency, e.Dependency.Consumer().Scope(),
+		e.Dependency.Consumer().Location())
+}
+
+// UncertainDependencyError is the main error of a dependency which more than one component
+// matches, in errors of NewContainer
+type UncertainDependencyError struct {
+	Dependency model.Dependency
+	Candidates []model.Component
+}
+
+func (e *UncertainDependencyError) Error() string {
+	return fmt.Sprintf("%v at %v", e.Dependency, e.Dependency.Consumer().Location())
+}
+
+// DependenceCycleError is the main error of a group of cycles, in errors of NewContainer
+type DependenceCycleError struct {
+	Group DependenceCycleGroup
+	// Complete is false if the search of cycles stopped at its limits
+	Complete bool
+	// Dependencies are dependencies on the shortest cycle of the group, in order
+	Dependencies []model.Dependency
+}
+
+func (e *DependenceCycleError) Error() string {
+	if cycles := e.Group.Cycles(); len(cycles) == 1 && e.Complete {
+		return fmt.Sprintf("%v", cycles[0])
+	}
+	return fmt.Sprintf("%v", e.Group)
+}
+
+// blockedReason tells why the component matching the dependency can not be injected
+func blockedReason(b model.BlockedComponent) string {
+	if b.Boundary.Private() {
+		return fmt.Sprintf("%v at %v is not exported by private %v",
+			b.Component, b.Component.Provider().Location(), b.Boundary)
+	}
+	return fmt.Sprintf("%v at %v is a default only visible in %v",
+		b.Component, b.Component.Provider().Location(), b.Boundary)
+}
+
+// nearMissCache finds near misses of a dependency when it is reported, and only once
+type nearMissCache struct {
+	repository   model.ComponentRepository
+	mu           sync.Mutex
+	byDependency map[model.Dependency][]model.NearMiss
+}
+
+func (c *nearMissCache) of(dep model.Dependency) []model.NearMiss {
+	if c == nil || c.repository == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nearMisses, ok := c.byDependency[dep]; ok {
+		return nearMisses
+	}
+	if c.byDependency == nil {
+		c.byDependency = map[model.Dependency][]model.NearMiss{}
+	}
+	nearMisses := c.repository.NearMissesOfDependency(dep)
+	c.byDependency[dep] = nearMisses
+	return nearMisses
+}
+
+// maxNearMissSuggestions limits the suggestions of one missing dependency
+const maxNearMissSuggestions = 3
+
+func nearMissSuggestions(dep model.Dependency, nearMisses []model.NearMiss) []string {
+	if len(nearMisses) > maxNearMissSuggestions {
+		nearMisses = nearMisses[:maxNearMissSuggestions]
+	}
+
+	var suggestions []string
+	for _, nm := range nearMisses {
+		com := nm.Component
+		var reason string
+		switch nm.Kind {
+		case model.NearMissNameOrTags:
+			reason = "the name or tags are different"
+		case model.NearMissHidden:
+			reason = "it is hidden and can only be matched by name or tags"
+		case model.NearMissScope:
+			reason = fmt.Sprintf("its scope %v can not enter %v", com.Provider().Scope(), dep.Consumer().Scope())
+		case model.NearMissPointer:
+			reason = fmt.Sprintf("it is of type %v rather than %v", com.Type(), dep.Type())
+		case model.NearMissAs:
+			reason = fmt.Sprintf("it implements %v but is not declared with As", dep.Type())
+		}
+		suggestions = append(suggestions, fmt.Sprintf("did you mean %v at %v? %v",
+			com, com.Provider().Location(), reason))
+	}
+	return suggestions
+}
+
+func (dg *dependenceGraph) ErrorOfNode(node Node) error {
+	missingSet := map[model.Dependency]struct{}{}
+	dg.allMissingDependencies().Iterate(func(dep model.Dependency) bool {
+		missingSet[dep] = struct{}{}
+		return true
+	})
+	uncertainSet := map[model.Dependency]struct{}{}
+	dg.allUncertainDependencies().Iterate(func(dep model.Dependency) bool {
+		uncertainSet[dep] = struct{}{}
+		return true
+	})
+
+	var missing, uncertain []model.Dependency
+	var cycles []DependenceCycle
+	var group DependenceCycleGroup
+	cycleInfo := dg.CycleInfo()
+	visited := map[Node]struct{}{}
+	queue := []Node{node}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[n]; ok {
+			continue
+		}
+		visited[n] = struct{}{}
+
+		if dep, ok := dg.DependencyOfNode(n); ok {
+			if _, ok = missingSet[dep]; ok {
+				missing = append(missing, dep)
+			}
+			if _, ok = uncertainSet[dep]; ok {
+				uncertain = append(uncertain, dep)
+			}
+		}
+		if nodeCycles := cycleInfo.CyclesOfNode(n); len(nodeCycles) > 0 && len(cycles) == 0 {
+			cycles = nodeCycles
+		}
+		if nodeGroup, ok := cycleInfo.GroupOfNode(n); ok && group == nil {
+			group = nodeGroup
+		}
+		dg.InputNodesTo(n).Each(func(input Node) {
+			queue = append(queue, input)
+		})
+	}
+
+	errs := errors.Empty()
+	if err := dg.missingErrorOf(model.ArrayDependencyIterator(missing)); err != nil {
+		errs = errs.AddErrors(err)
+	}
+	if err := dg.uncertainErrorOf(model.ArrayDependencyIterator(uncertain)); err != nil {
+		errs = errs.AddErrors(err)
+	}
+	if len(cycles) > 0 {
+		errs = errs.AddErrorf("there are cycles in the dependence path. %v", cycles)
+	} else if group != nil {
+		// cycles of nodes may be not found because of limits of the search
+		errs = errs.AddErrorf("there are cycles in the dependence path. %v", group)
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jison/uni/core/valuer"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/jison/uni/core/model"
 	"github.com/jison/uni/graph"
+	"github.com/jison/uni/internal/errors"
 )
 
 //lint:ignore U1000 we need the field name to locate the field
@@ -305,7 +307,7 @@ func Test_dependenceGraph_Graph(t *testing.T) {
 		{"name9", []depVerifyInfo{
 			{model.TypeOf(0), nil, []string{"name1"}},
 			{model.TypeOf(""), nil, []string{"name4"}},
-			{model.TypeOf((*testInterface)(nil)), valuer.Error(&missingError{name10Dep}),
+			{model.TypeOf((*testInterface)(nil)), valuer.Error(&missingError{dependency: name10Dep, nearMisses: &nearMissCache{repository: rep}}),
 				[]string{}},
 		}},
 		{"name10", []depVerifyInfo{
@@ -667,6 +669,102 @@ func Test_dependenceGraph_Validate(t *testing.T) {
 	})
 }
 
+func Test_dependenceGraph_Validate_typedErrors(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		g, _, _, _ := buildTestGraphWithMissingDependencyError()
+		var missing *MissingDependencyError
+		assert.True(t, errors.As(g.Validate(), &missing))
+		assert.NotNil(t, missing.Dependency)
+		assert.Contains(t, g.MissingError().Error(), missing.Error())
+	})
+
+	t.Run("uncertain", func(t *testing.T) {
+		g, _, _, _ := buildTestGraphWithNotUniqueDependencyError()
+		var uncertain *UncertainDependencyError
+		assert.True(t, errors.As(g.Validate(), &uncertain))
+		assert.Greater(t, len(uncertain.Candidates), 1)
+		assert.Equal(t, len(uncertain.Candidates),
+			len(g.InputComponentsToDependency(uncertain.Dependency).ToArray()))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		g, _ := graphWithCrossingCycles()
+		var cycle *DependenceCycleError
+		assert.True(t, errors.As(g.Validate(), &cycle))
+		assert.True(t, cycle.Complete)
+		assert.NotEmpty(t, cycle.Dependencies)
+		assert.Contains(t, cycle.Error(), "5 providers form a strongly connected group")
+	})
+}
+
+func Test_dependenceGraph_ErrorOfNode(t *testing.T) {
+	type missing struct{}
+	type a struct{}
+	type b struct{}
+	tag := model.NewSymbol("tag")
+	g := newDependenceGraph(model.NewRepository(model.NewModule(
+		model.Value(1),
+		model.Value("a", model.Tags(tag)),
+		model.Value("b", model.Tags(tag)),
+		model.Func(func(m missing) int8 { return 0 }),
+		model.Func(func(b) a { return a{} }),
+		model.Func(func(a) b { return b{} }),
+	).AllComponents()))
+
+	errorOf := func(cb model.ConsumerBuilder) error {
+		dg, node := g.Derive(cb.Consumer())
+		return dg.ErrorOfNode(node)
+	}
+
+	assert.Nil(t, errorOf(model.ValueConsumer(0)))
+
+	err := errorOf(model.ValueConsumer(int8(0)))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "can not find component match these dependencies")
+
+	err = errorOf(model.ValueConsumer("", model.ByTags(tag)))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "these dependencies are more than one component match")
+
+	err = errorOf(model.ValueConsumer(a{}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "there are cycles in the dependence path")
+}
+
+type countingNearMissRepository struct {
+	model.ComponentRepository
+	count int
+}
+
+func (r *countingNearMissRepository) NearMissesOfDependency(dep model.Dependency) []model.NearMiss {
+	r.count++
+	return r.ComponentRepository.NearMissesOfDependency(dep)
+}
+
+func Test_dependenceGraph_nearMisses(t *testing.T) {
+	rep := &countingNearMissRepository{ComponentRepository: model.NewRepository(model.NewModule(
+		model.Value(1, model.Name("a")),
+	).AllComponents())}
+	g := newDependenceGraph(rep)
+
+	var nodes []Node
+	var graphs []DependenceGraph
+	for i := 0; i < 3; i++ {
+		dg, node := g.Derive(model.ValueConsumer(0, model.ByName("b")).Consumer())
+		graphs = append(graphs, dg)
+		nodes = append(nodes, node)
+	}
+	assert.Equal(t, 0, rep.count)
+
+	for j := 0; j < 2; j++ {
+		for i, dg := range graphs {
+			err := dg.ErrorOfNode(nodes[i])
+			assert.Contains(t, err.Error(), "the name or tags are different")
+		}
+	}
+	assert.Equal(t, 3, rep.count)
+}
+
 func Test_dependenceGraph_Derive(t *testing.T) {
 	m, _, scope2, _ := buildTestModule()
 	rep := model.NewRepository(m.AllComponents())
@@ -1022,9 +1120,117 @@ func newDependenceGraphForTest(coms model.ComponentCollection) *dependenceGraph
 		nodeByComponent:  map[model.Component]Node{},
 		nodeByConsumer:   map[model.Consumer]Node{},
 		nodeByDependency: map[model.Dependency]Node{},
+		nearMisses:       &nearMissCache{repository: rep},
 	}
 }
 
+func Test_dependenceGraph_DeriveRepository(t *testing.T) {
+	type plugin struct{}
+	type missing struct{}
+	parent := newDependenceGraph(model.NewRepository(model.NewModule(
+		model.Value(1),
+		model.Func(func(m missing) string { return "" }),
+	).AllComponents()))
+	assert.NotNil(t, parent.MissingError())
+
+	t.Run("resolve to parent", func(t *testing.T) {
+		childRep := model.NewRepository(model.NewModule(
+			model.Func(func(i int) *plugin { return &plugin{} }),
+		).AllComponents())
+		g := parent.DeriveRepository(childRep)
+		assert.Nil(t, g.Validate())
+
+		com := childRep.AllComponents().ToArray()[0]
+		comNode, ok := g.NodeOfComponent(com)
+		assert.True(t, ok)
+		_, ok = parent.NodeOfComponent(com)
+		assert.False(t, ok)
+
+		inputs := g.InputComponentsTo(com).ToArray()
+		assert.Equal(t, 1, len(inputs))
+		parentNode, _ := parent.NodeOfComponent(inputs[0])
+		inputNode, _ := g.NodeOfComponent(inputs[0])
+		assert.Equal(t, parentNode, inputNode)
+		assert.NotNil(t, comNode)
+	})
+
+	t.Run("errors of new part", func(t *testing.T) {
+		g := parent.DeriveRepository(model.NewRepository(model.NewModule(
+			model.Value(2),
+			model.Func(func(i int, m missing) *plugin { return &plugin{} }),
+		).AllComponents()))
+		assert.NotNil(t, g.MissingError())
+		assert.Equal(t, 1, strings.Count(g.MissingError().Error(), "core.missing"))
+		assert.NotNil(t, g.UncertainError())
+	})
+
+	t.Run("cycles of new part", func(t *testing.T) {
+		type a struct{}
+		type b struct{}
+		cyclic := newDependenceGraph(model.NewRepository(model.NewModule(
+			model.Func(func(b) a { return a{} }),
+			model.Func(func(a) b { return b{} }),
+		).AllComponents()))
+		assert.NotNil(t, cyclic.CycleError())
+
+		g := cyclic.DeriveRepository(model.NewRepository(model.NewModule(
+			model.Func(func(a) *plugin { return &plugin{} }),
+		).AllComponents()))
+		assert.Nil(t, g.CycleError())
+
+		type c struct{}
+		type d struct{}
+		g2 := cyclic.DeriveRepository(model.NewRepository(model.NewModule(
+			model.Func(func(d) c { return c{} }),
+			model.Func(func(c) d { return d{} }),
+		).AllComponents()))
+		assert.NotNil(t, g2.CycleError())
+		assert.Contains(t, g2.CycleError().Error(), "core.c")
+		assert.NotContains(t, g2.CycleError().Error(), "core.a")
+	})
+}
+
+func Test_dependenceGraph_CycleError_groups(t *testing.T) {
+	t.Run("one cycle", func(t *testing.T) {
+		g, _ := graphWithOneCycle()
+		err := g.CycleError()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cycle:")
+		assert.NotContains(t, err.Error(), "strongly connected group")
+	})
+
+	t.Run("crossing cycles", func(t *testing.T) {
+		g, _ := graphWithCrossingCycles()
+		err := g.CycleError()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "5 providers form a strongly connected group")
+		assert.Equal(t, 4+1, strings.Count(err.Error(), "cycle:"))
+	})
+
+	t.Run("dense cycles", func(t *testing.T) {
+		g := graphWithDenseCycles(12)
+		err := g.CycleError()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "12 providers form a strongly connected group")
+		assert.Contains(t, err.Error(), fmt.Sprintf("and %d more cycles", defaultCycleLimits.MaxCycles-maxCyclesInGroupError))
+		assert.Contains(t, err.Error(), "and more cycles which are not searched")
+		assert.Equal(t, maxCyclesInGroupError+1, strings.Count(err.Error(), "cycle:"))
+	})
+
+	t.Run("node of cycle not found", func(t *testing.T) {
+		g, rep := graphWithCrossingCycles()
+		g.(*dependenceGraph).cycleLimits = graph.CycleLimits{MaxCycles: 1}
+
+		g.Nodes().Iterate(func(node Node) bool {
+			err := g.ErrorOfNode(node)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "there are cycles in the dependence path")
+			return true
+		})
+		assert.NotNil(t, g.ErrorOfNode(comByName(rep, "name3").Valuer()))
+	})
+}
+
 func Test_dependenceGraph_addNodeOfComponent(t *testing.T) {
 	pro := model.Func(func(_ int) string { return "" }).Provider()
 	com := pro.Components().ToArray()[0]
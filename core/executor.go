@@ -28,6 +28,11 @@ func (e *executor) getValueOfNode(node Node, storage ScopeBaseStorage, stack Pat
 		err := errors.Newf("there are cycles in the dependence path. %v", cycles)
 		return valuer.ErrorValue(err)
 	}
+	// cycles of the node may be not found because of limits of the search
+	if group, ok := e.cycleInfo.GroupOfNode(node); ok {
+		err := errors.Newf("there are cycles in the dependence path. %v", group)
+		return valuer.ErrorValue(err)
+	}
 
 	nodeScope := e.scopeOfNode(node)
 
@@ -37,21 +42,23 @@ func (e *executor) getValueOfNode(node Node, storage ScopeBaseStorage, stack Pat
 		e.graph.InputNodesTo(node).Each(func(inputNode Node) {
 			params = append(params, e.getValueOfNode(inputNode, s, nodeStack))
 		})
-		nodeVal := node.Value(params)
-
 		provider, isProvider := e.graph.ProviderOfNode(node)
-		if isProvider {
-			return nodeVal
-		}
-		err, isErr := nodeVal.AsError()
-		if !isErr {
-			return nodeVal
-		}
-		providerErr := errors.Newf("%+v", provider).AddErrors(err)
-		return valuer.ErrorValue(providerErr)
+		return valueOfProvider(node.Value(params), provider, isProvider)
 	})
 }
 
+// valueOfProvider wraps the error of the node with the provider, if the node is a provider
+func valueOfProvider(nodeVal valuer.Value, provider model.Provider, isProvider bool) valuer.Value {
+	if !isProvider {
+		return nodeVal
+	}
+	err, isErr := nodeVal.AsError()
+	if !isErr {
+		return nodeVal
+	}
+	return valuer.ErrorValue(errors.Newf("%+v", provider).AddErrors(err))
+}
+
 func (e *executor) scopeOfNode(node Node) model.Scope {
 	if provider, ok := e.graph.ProviderOfNode(node); ok {
 		return provider.Scope()
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/jison/uni/core/model"
@@ -131,3 +134,38 @@ func Test_executor(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+type executorTestError struct {
+	code int
+}
+
+func (e *executorTestError) Error() string {
+	return fmt.Sprintf("error %d", e.code)
+}
+
+func Test_executor_errorOfProvider(t *testing.T) {
+	cause := &executorTestError{code: 1}
+	module := model.NewModule(
+		model.Func(func() (int, error) { return 0, cause }),
+		model.Func(func(a int) string { return "" }),
+	)
+	rep := model.NewRepository(module.AllComponents())
+	g := newDependenceGraph(rep)
+	consumer := model.FuncConsumer(func(s string) {}).Consumer()
+
+	exe := newExecutor(g, newScopeStorage(), consumer)
+	_, err := exe.Execute()
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, cause))
+
+	var target *executorTestError
+	assert.True(t, errors.As(err, &target))
+	assert.Same(t, cause, target)
+
+	// the error is wrapped by each provider in the path
+	lines := strings.Split(err.Error(), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "func(int) string")
+	assert.Contains(t, lines[1], "func() (int, error)")
+	assert.Equal(t, "\t\terror 1", lines[2])
+}
@@ -0,0 +1,307 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+)
+
+// bindProvider provides a component which forwards to the bound component,
+// so no more instance is created
+type bindProvider struct {
+	*dependency
+	*baseConsumer
+	baseProvider
+	com *component
+}
+
+var _ Provider = &bindProvider{}
+
+func (bp *bindProvider) Dependencies() DependencyIterator {
+	return bp.dependency
+}
+
+func (bp *bindProvider) Components() ComponentCollection {
+	return ComponentsOfIterator(bp.com)
+}
+
+// IsBindProvider returns whether the provider forwards to the component of its only dependency
+func IsBindProvider(p Provider) bool {
+	bp, ok := p.(*bindProvider)
+	return ok && bp != nil
+}
+
+func (bp *bindProvider) Valuer() valuer.Valuer {
+	return bp.baseConsumer.val
+}
+
+func (bp *bindProvider) Validate() error {
+	errs := errors.Empty()
+
+	if err := bp.dependency.Validate(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if err := bp.com.Validate(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if bp.com.Type() != nil && bp.com.Type().Kind() != reflect.Interface {
+		errs = errs.AddErrorf("[%v] is not an interface", bp.com.Type())
+	} else if bp.dependency.Type() != nil && bp.com.Type() != nil &&
+		!bp.dependency.Type().Implements(bp.com.Type()) {
+		errs = errs.AddErrorf("[%v] does not implement [%v]", bp.dependency.Type(), bp.com.Type())
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+
+	return nil
+}
+
+func (bp *bindProvider) Format(f fmt.State, r rune) {
+	_, _ = fmt.Fprintf(f, "Bind[%v](%v) in %v", bp.com.Type(), bp.dependency.Type(), bp.Scope())
+
+	if f.Flag('+') && r == 'v' {
+		_, _ = fmt.Fprintf(f, " at %v", bp.Location())
+	}
+}
+
+func (bp *bindProvider) clone() *bindProvider {
+	if bp == nil {
+		return nil
+	}
+	cloned := &bindProvider{
+		dependency:   bp.dependency.clone(),
+		baseConsumer: bp.baseConsumer.clone(),
+		baseProvider: bp.baseProvider,
+		com:          bp.com.clone(),
+	}
+	cloned.dependency.consumer = cloned
+	cloned.com.provider = cloned
+	return cloned
+}
+
+func (bp *bindProvider) Equal(other interface{}) bool {
+	o, ok := other.(*bindProvider)
+	if !ok {
+		return false
+	}
+
+	if bp == nil || o == nil {
+		return bp == nil && o == nil
+	}
+
+	if bp.dependency != nil {
+		if !bp.dependency.Equal(o.dependency) {
+			return false
+		}
+	} else if o.dependency != nil {
+		return false
+	}
+
+	if bp.baseConsumer != nil {
+		if !bp.baseConsumer.Equal(o.baseConsumer) {
+			return false
+		}
+	} else if o.baseConsumer != nil {
+		return false
+	}
+
+	if bp.com != nil {
+		if !bp.com.Equal(o.com) {
+			return false
+		}
+	} else if o.com != nil {
+		return false
+	}
+
+	return true
+}
+
+type BindProviderBuilder interface {
+	ModuleOption
+	ProviderBuilder
+
+	SetIgnore(ignore bool) BindProviderBuilder
+	SetHidden(hidden bool) BindProviderBuilder
+	SetName(name string) BindProviderBuilder
+	AddTags(tags ...Symbol) BindProviderBuilder
+
+	SetTargetName(name string) BindProviderBuilder
+	AddTargetTags(tags ...Symbol) BindProviderBuilder
+
+	SetScope(scope Scope) BindProviderBuilder
+	SetLocation(loc location.Location) BindProviderBuilder
+	UpdateCallLocation(loc location.Location) BindProviderBuilder
+}
+
+func (bp *bindProvider) ApplyModule(mb ModuleBuilder) {
+	mb.AddProvider(bp)
+}
+
+func (bp *bindProvider) Provider() Provider {
+	return bp.clone()
+}
+
+func (bp *bindProvider) SetIgnore(ignore bool) BindProviderBuilder {
+	bp.com.SetIgnore(ignore)
+	return bp
+}
+
+func (bp *bindProvider) SetHidden(hidden bool) BindProviderBuilder {
+	bp.com.SetHidden(hidden)
+	return bp
+}
+
+func (bp *bindProvider) addTypeConstraint(tc typeConstraint) {
+	bp.com.addTypeConstraint(tc)
+}
+
+func (bp *bindProvider) SetName(name string) BindProviderBuilder {
+	bp.com.SetName(name)
+	return bp
+}
+
+func (bp *bindProvider) AddTags(tags ...Symbol) BindProviderBuilder {
+	bp.com.AddTags(tags...)
+	return bp
+}
+
+func (bp *bindProvider) SetTargetName(name string) BindProviderBuilder {
+	bp.dependency.SetName(name)
+	return bp
+}
+
+func (bp *bindProvider) AddTargetTags(tags ...Symbol) BindProviderBuilder {
+	bp.dependency.AddTags(tags...)
+	return bp
+}
+
+func (bp *bindProvider) SetScope(scope Scope) BindProviderBuilder {
+	bp.baseConsumer.SetScope(scope)
+	return bp
+}
+
+func (bp *bindProvider) SetLocation(loc location.Location) BindProviderBuilder {
+	bp.baseConsumer.SetLocation(loc)
+	return bp
+}
+
+func (bp *bindProvider) UpdateCallLocation(loc location.Location) BindProviderBuilder {
+	if bp.Location() == nil {
+		if loc == nil {
+			loc = location.GetLocation(3)
+		}
+		bp.SetLocation(loc)
+	}
+	return bp
+}
+
+func bindProviderOf(iface TypeVal, target TypeVal, opts ...BindProviderOption) *bindProvider {
+	bp := &bindProvider{
+		baseConsumer: &baseConsumer{
+			val: valuer.Identity(),
+		},
+		dependency: &dependency{
+			rType: TypeOf(target),
+			val:   valuer.Identity(),
+		},
+		com: &component{
+			val:   valuer.Identity(),
+			rType: TypeOf(iface),
+		},
+	}
+	bp.dependency.consumer = bp
+	bp.com.provider = bp
+
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		o.ApplyBindProvider(bp)
+	}
+	return bp
+}
+
+// inheritScopesOfBinds puts bind providers of the module without a scope into the scope of
+// their targets, which are provided in the module or its sub modules
+func inheritScopesOfBinds(m *module) {
+	m.providers.Iterate(func(p Provider) bool {
+		bp, ok := p.(*bindProvider)
+		if !ok || bp.baseConsumer.scope != nil {
+			return true
+		}
+
+		scopes := map[Scope]struct{}{}
+		m.AllProviders().Iterate(func(target Provider) bool {
+			if target == p {
+				return true
+			}
+			target.Components().Each(func(com Component) {
+				if !com.Ignored() && componentMatch(com, bp.dependency) {
+					scopes[target.Scope()] = struct{}{}
+				}
+			})
+			return true
+		})
+
+		if len(scopes) == 1 {
+			for s := range scopes {
+				bp.baseConsumer.SetScope(s)
+			}
+		}
+		return true
+	})
+}
+
+// Bind provides a component of type iface, which is the component of type target,
+// instead of a new instance. iface must be an interface implemented by target, the
+// component is in the scope of the target provided in the same module unless InScope is given
+func Bind(iface TypeVal, target TypeVal, opts ...BindProviderOption) BindProviderBuilder {
+	return bindProviderOf(iface, target, opts...).UpdateCallLocation(nil)
+}
+
+type BindProviderOption interface {
+	ApplyBindProvider(b BindProviderBuilder)
+}
+
+func (o LocationOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetLocation(o.Location)
+}
+
+func (o UpdateCallLocationOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.UpdateCallLocation(o.Location)
+}
+
+func (o ScopeOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetScope(o.scope)
+}
+
+func (o IgnoreOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetIgnore(o.ignore)
+}
+
+func (o HiddenOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetHidden(o.hidden)
+}
+
+func (o NameOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetName(string(o))
+}
+
+func (o TagsOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.AddTags(o.tags...)
+}
+
+func (o ByNameOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.SetTargetName(string(o))
+}
+
+func (o ByTagsOption) ApplyBindProvider(b BindProviderBuilder) {
+	b.AddTargetTags(o.tags...)
+}
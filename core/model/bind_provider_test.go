@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/internal/location"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindTestInterface interface {
+	Foo()
+}
+
+type bindTestImpl struct{}
+
+func (b *bindTestImpl) Foo() {}
+
+func TestBind(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		tag1 := NewSymbol("tag1")
+		tag2 := NewSymbol("tag2")
+		scope1 := NewScope("scope1")
+		baseLoc := location.GetCallLocation(0)
+		bp := Bind((*bindTestInterface)(nil), &bindTestImpl{},
+			Name("abc"),
+			Tags(tag1),
+			ByName("impl"),
+			ByTags(tag2),
+			Hide(),
+			InScope(scope1),
+			nil,
+		)
+		p := bp.Provider()
+		assert.Nil(t, p.Validate())
+
+		assert.Equal(t, valuer.Identity(), p.Valuer())
+		assert.Equal(t, baseLoc.FileName(), p.Location().FileName())
+		assert.Equal(t, baseLoc.FileLine()+1, p.Location().FileLine())
+		assert.Equal(t, scope1, p.Scope())
+
+		deps := dependencyIteratorToArray(p.Dependencies())
+		assert.Equal(t, 1, len(deps))
+		dep := deps[0]
+		assert.Equal(t, TypeOf(&bindTestImpl{}), dep.Type())
+		assert.Equal(t, "impl", dep.Name())
+		assert.Equal(t, newSymbolSet(tag2), dep.Tags())
+		assert.Same(t, p, dep.Consumer())
+
+		coms := p.Components().ToArray()
+		assert.Equal(t, 1, len(coms))
+		com := coms[0]
+		assert.Equal(t, TypeOf((*bindTestInterface)(nil)), com.Type())
+		assert.Equal(t, "abc", com.Name())
+		assert.Equal(t, newSymbolSet(tag1), com.Tags())
+		assert.True(t, com.Hidden())
+		assert.Same(t, p, com.Provider())
+
+		assert.Contains(t, fmt.Sprintf("%+v", p), "Bind[model.bindTestInterface](*model.bindTestImpl)")
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		p := Bind((*bindTestInterface)(nil), 123).Provider()
+		assert.Contains(t, fmt.Sprint(p.Validate()), "[int] does not implement [model.bindTestInterface]")
+	})
+
+	t.Run("not interface", func(t *testing.T) {
+		p := Bind(&bindTestImpl{}, &bindTestImpl{}).Provider()
+		assert.Contains(t, fmt.Sprint(p.Validate()), "[*model.bindTestImpl] is not an interface")
+	})
+
+	t.Run("inherit scope of target", func(t *testing.T) {
+		scope1 := NewScope("scope1")
+		scope2 := NewScope("scope2")
+		bindInScope := func(m Module) Scope {
+			var s Scope
+			m.Providers().Iterate(func(p Provider) bool {
+				if IsBindProvider(p) {
+					s = p.Scope()
+				}
+				return true
+			})
+			return s
+		}
+
+		m := NewModule(
+			Bind((*bindTestInterface)(nil), &bindTestImpl{}),
+			SubModule(NewModule(Value(&bindTestImpl{}, InScope(scope1)))),
+		)
+		assert.Equal(t, scope1, bindInScope(m))
+
+		m = NewModule(
+			Bind((*bindTestInterface)(nil), &bindTestImpl{}, InScope(scope2)),
+			Value(&bindTestImpl{}, InScope(scope1)),
+		)
+		assert.Equal(t, scope2, bindInScope(m))
+
+		m = NewModule(
+			Bind((*bindTestInterface)(nil), &bindTestImpl{}),
+			Value(&bindTestImpl{}, InScope(scope1)),
+			Value(&bindTestImpl{}, InScope(scope2)),
+		)
+		assert.Equal(t, GlobalScope, bindInScope(m))
+	})
+
+	t.Run("clone and equal", func(t *testing.T) {
+		bp := Bind((*bindTestInterface)(nil), &bindTestImpl{}, Name("abc"))
+		p1 := bp.Provider()
+		p2 := bp.Provider()
+		assert.NotSame(t, p1, p2)
+		assert.True(t, p1.Equal(p2))
+
+		p3 := Bind((*bindTestInterface)(nil), &bindTestImpl{}, Name("def")).Provider()
+		assert.False(t, p1.Equal(p3))
+		assert.False(t, p1.Equal(Value(1).Provider()))
+	})
+}
+
+func TestSupply(t *testing.T) {
+	baseLoc := location.GetCallLocation(0)
+	m := NewModule(Supply(1, "a", &bindTestImpl{}))
+
+	var types []string
+	m.AllProviders().Iterate(func(p Provider) bool {
+		assert.Equal(t, baseLoc.FileLine()+1, p.Location().FileLine())
+		p.Components().Each(func(com Component) {
+			types = append(types, com.Type().String())
+		})
+		return true
+	})
+	assert.ElementsMatch(t, []string{"int", "string", "*model.bindTestImpl"}, types)
+}
+
+func TestIsBindProvider(t *testing.T) {
+	assert.True(t, IsBindProvider(Bind((*bindTestInterface)(nil), &bindTestImpl{}).Provider()))
+	assert.False(t, IsBindProvider(Value(1).Provider()))
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// CachePolicy controls how long values are cached in the storage of their scope,
+// values are cached forever by default
+type CachePolicy struct {
+	// TTL is the max duration a value is cached after it is created, zero means forever
+	TTL time.Duration
+	// MaxEntries is the max number of providers whose values are cached in the storage of a scope,
+	// the earliest created are evicted first, zero means unlimited. It only takes effect
+	// on the policy of a scope
+	MaxEntries int
+}
+
+func (p CachePolicy) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "CachePolicy{TTL=%v, MaxEntries=%v}", p.TTL, p.MaxEntries)
+}
+
+func Cache(policy CachePolicy) CacheOption {
+	return CacheOption{policy}
+}
+
+type CacheOption struct {
+	policy CachePolicy
+}
+
+func (o CacheOption) ApplyNewScope(b ScopeBuilder) {
+	b.SetCachePolicy(o.policy)
+}
+
+func (o CacheOption) ApplyFuncProvider(b FuncProviderBuilder) {
+	b.SetCachePolicy(o.policy)
+}
+
+func (o CacheOption) ApplyStructProvider(b StructProviderBuilder) {
+	b.SetCachePolicy(o.policy)
+}
+
+// CachePolicyOf returns the cache policy of the provider, or the policy of its scope
+// if the provider has not one
+func CachePolicyOf(p Provider) (CachePolicy, bool) {
+	if p == nil {
+		return CachePolicy{}, false
+	}
+	if policy, ok := p.CachePolicy(); ok {
+		return policy, true
+	}
+	if s := p.Scope(); s != nil {
+		return s.CachePolicy()
+	}
+	return CachePolicy{}, false
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	policy := CachePolicy{TTL: time.Minute, MaxEntries: 10}
+
+	t.Run("scope", func(t *testing.T) {
+		s1 := NewScopeWith("s1", Cache(policy))
+		p, ok := s1.CachePolicy()
+		assert.True(t, ok)
+		assert.Equal(t, policy, p)
+
+		s2 := NewScope("s2", s1)
+		_, ok = s2.CachePolicy()
+		assert.False(t, ok)
+		assert.True(t, s2.CanEnterDirectlyFrom(s1))
+
+		_, ok = GlobalScope.CachePolicy()
+		assert.False(t, ok)
+	})
+
+	t.Run("func provider", func(t *testing.T) {
+		p := Func(func() int { return 1 }, Cache(policy)).Provider()
+		p2, ok := p.CachePolicy()
+		assert.True(t, ok)
+		assert.Equal(t, policy, p2)
+
+		_, ok = Func(func() int { return 1 }).Provider().CachePolicy()
+		assert.False(t, ok)
+	})
+
+	t.Run("struct provider", func(t *testing.T) {
+		type testStruct struct{}
+		p := Struct(testStruct{}, Cache(policy)).Provider()
+		p2, ok := p.CachePolicy()
+		assert.True(t, ok)
+		assert.Equal(t, policy, p2)
+	})
+
+	t.Run("format", func(t *testing.T) {
+		assert.Equal(t, "CachePolicy{TTL=1m0s, MaxEntries=10}", fmt.Sprintf("%v", policy))
+	})
+}
+
+func TestCachePolicyOf(t *testing.T) {
+	scopePolicy := CachePolicy{MaxEntries: 3}
+	providerPolicy := CachePolicy{TTL: time.Second}
+	s := NewScopeWith("s", Cache(scopePolicy))
+
+	p, ok := CachePolicyOf(Func(func() int { return 1 }, InScope(s), Cache(providerPolicy)).Provider())
+	assert.True(t, ok)
+	assert.Equal(t, providerPolicy, p)
+
+	p, ok = CachePolicyOf(Func(func() int { return 1 }, InScope(s)).Provider())
+	assert.True(t, ok)
+	assert.Equal(t, scopePolicy, p)
+
+	_, ok = CachePolicyOf(Func(func() int { return 1 }).Provider())
+	assert.False(t, ok)
+
+	_, ok = CachePolicyOf(nil)
+	assert.False(t, ok)
+}
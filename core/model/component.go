@@ -45,6 +45,8 @@ type component struct {
 	as       *typeSet
 	name     string
 	tags     *symbolSet
+	// constraints of types of typed names
+	constraints typeConstraints
 }
 
 var _ Component = &component{}
@@ -92,6 +94,15 @@ func (c *component) Validate() error {
 		errs = errs.AddErrorf("type of component can not be `error`")
 	}
 
+	if c.rType != nil {
+		err := c.constraints.check(func(t reflect.Type) bool {
+			return c.rType == t || c.as.Has(t)
+		}, c.tags)
+		if err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+
 	c.as.Iterate(func(i reflect.Type) bool {
 		if reflecting.IsErrorType(i) {
 			errs = errs.AddErrorf("[%v] in `as` has implemented error, can not as error interface", i)
@@ -171,11 +182,17 @@ func (c *component) clone() *component {
 		as:       c.as.clone(),
 		name:     c.name,
 		tags:     c.tags.clone(),
+
+		constraints: c.constraints.clone(),
 	}
 
 	return cloned
 }
 
+func (c *component) addTypeConstraint(tc typeConstraint) {
+	c.constraints = append(c.constraints, tc)
+}
+
 func (c *component) Format(f fmt.State, r rune) {
 	isVerbose := (f.Flag('+') || f.Flag('#')) && r == 'v'
 
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 )
 
 type ComponentRepository interface {
@@ -9,12 +11,51 @@ type ComponentRepository interface {
 	ComponentsMatch(Criteria) ComponentCollection
 	ComponentsWithScope(scope Scope) ComponentCollection
 	ComponentsMatchDependency(dep Dependency) ComponentCollection
+	// BlockedComponentsOfDependency returns components that match the dependency,
+	// but can not be injected because of private modules
+	BlockedComponentsOfDependency(dep Dependency) []BlockedComponent
+	// NearMissesOfDependency returns components that almost match the dependency,
+	// they are suggestions when the dependency is missing
+	NearMissesOfDependency(dep Dependency) []NearMiss
+}
+
+type BlockedComponent struct {
+	Component Component
+	// Boundary is the private module which does not export the component,
+	// or the module which the component is a default of
+	Boundary Module
+}
+
+type NearMissKind int
+
+const (
+	// NearMissNameOrTags is a component of the type, but with a different name or tags
+	NearMissNameOrTags NearMissKind = iota
+	// NearMissHidden is a hidden component, which could be matched by name or tags
+	NearMissHidden
+	// NearMissScope is a component whose scope can not enter the scope of the dependency
+	NearMissScope
+	// NearMissPointer is a component of the pointer type of the dependency, or the other way round
+	NearMissPointer
+	// NearMissAs is a component which implements the interface of the dependency without As
+	NearMissAs
+)
+
+type NearMiss struct {
+	Component Component
+	Kind      NearMissKind
 }
 
 type componentRepository struct {
 	entryByType       map[reflect.Type]typeEntry
 	componentsByScope map[Scope]componentSet
 	allComponents     componentSet
+	// modules contain the provider, the innermost module first
+	modulesByProvider map[Consumer][]Module
+	// ownersOfDefaults are modules whose inputs use the default provider, it is only visible in them
+	ownersOfDefaults map[Provider][]Module
+	// root is the module of the repository, consumers of the container are in it
+	root Module
 }
 
 var _ ComponentRepository = &componentRepository{}
@@ -78,27 +119,191 @@ func (m *componentRepository) ComponentsMatchDependency(dep Dependency) Componen
 		return EmptyComponents()
 	}
 
-	s := dep.Consumer().Scope()
-
 	if isCriteriaMatchAll(dep) {
-		return m.ComponentsWithScope(s)
+		return m.ComponentsWithScope(dep.Consumer().Scope()).Filter(func(com Component) bool {
+			return m.boundaryOf(com, dep.Consumer()) == nil
+		})
 	}
 
-	return m.ComponentsMatch(dep).Filter(func(com Component) bool {
-		// can not use the same provider as dependence's consumer
-		if com.Provider() == dep.Consumer() {
-			return false
+	coms := m.componentsMatchCriteriaOfDependency(dep, dep)
+
+	if fd, ok := dep.(withFallbacks); ok {
+		for _, cri := range fd.Fallbacks() {
+			if len(coms.ToArray()) > 0 {
+				break
+			}
+			coms = m.componentsMatchCriteriaOfDependency(cri, dep)
 		}
+	}
 
-		comScope := com.Provider().Scope()
+	return coms
+}
 
-		// the scope with the injected component could enter the dependence's scope
-		if s != comScope && !s.CanEnterFrom(comScope) {
+func (m *componentRepository) componentsMatchCriteriaOfDependency(cri Criteria, dep Dependency) ComponentCollection {
+	return m.ComponentsMatch(cri).Filter(func(com Component) bool {
+		if !m.canInject(com, dep) {
 			return false
 		}
 
-		return true
+		return m.boundaryOf(com, dep.Consumer()) == nil
+	})
+}
+
+func (m *componentRepository) canInject(com Component, dep Dependency) bool {
+	// can not use the same provider as dependence's consumer
+	if com.Provider() == dep.Consumer() {
+		return false
+	}
+
+	s := dep.Consumer().Scope()
+	comScope := com.Provider().Scope()
+
+	// the scope with the injected component could enter the dependence's scope
+	if s != comScope && !s.CanEnterFrom(comScope) {
+		return false
+	}
+
+	return true
+}
+
+func (m *componentRepository) BlockedComponentsOfDependency(dep Dependency) []BlockedComponent {
+	if dep == nil || len(m.modulesByProvider) == 0 || isCriteriaMatchAll(dep) {
+		return nil
+	}
+
+	var blocked []BlockedComponent
+	m.ComponentsMatch(dep).Each(func(com Component) {
+		if !m.canInject(com, dep) {
+			return
+		}
+
+		if boundary := m.boundaryOf(com, dep.Consumer()); boundary != nil {
+			blocked = append(blocked, BlockedComponent{Component: com, Boundary: boundary})
+		}
 	})
+
+	return blocked
+}
+
+func (m *componentRepository) NearMissesOfDependency(dep Dependency) []NearMiss {
+	if dep == nil || isCriteriaMatchAll(dep) {
+		return nil
+	}
+
+	t := dep.Type()
+	var alt reflect.Type
+	if t.Kind() == reflect.Ptr {
+		alt = t.Elem()
+	} else {
+		alt = reflect.PtrTo(t)
+	}
+
+	var nearMisses []NearMiss
+	m.allComponents.Each(func(com Component) {
+		if com.Provider() == dep.Consumer() || m.boundaryOf(com, dep.Consumer()) != nil {
+			return
+		}
+
+		kind, ok := m.nearMissKindOf(com, dep, alt)
+		if ok {
+			nearMisses = append(nearMisses, NearMiss{Component: com, Kind: kind})
+		}
+	})
+
+	sortNearMisses(nearMisses)
+	return nearMisses
+}
+
+func (m *componentRepository) nearMissKindOf(com Component, dep Dependency, alt reflect.Type) (NearMissKind, bool) {
+	t := dep.Type()
+	isType := com.Type() == t || com.As().Has(t)
+	if isType && componentMatch(com, dep) {
+		if !m.canInject(com, dep) {
+			return NearMissScope, true
+		}
+		return 0, false
+	}
+
+	if !m.canInject(com, dep) {
+		return 0, false
+	}
+
+	if isType {
+		if com.Hidden() && dep.Name() == "" && dep.Tags().Len() == 0 {
+			return NearMissHidden, true
+		}
+		return NearMissNameOrTags, true
+	}
+
+	if !criteriaMatchExceptType(com, dep) {
+		return 0, false
+	}
+
+	if com.Type() == alt || com.As().Has(alt) {
+		return NearMissPointer, true
+	}
+
+	if t.Kind() == reflect.Interface && com.Type().Implements(t) {
+		return NearMissAs, true
+	}
+
+	return 0, false
+}
+
+func sortNearMisses(nearMisses []NearMiss) {
+	sort.SliceStable(nearMisses, func(i, j int) bool {
+		if nearMisses[i].Kind != nearMisses[j].Kind {
+			return nearMisses[i].Kind < nearMisses[j].Kind
+		}
+		return fmt.Sprint(nearMisses[i].Component) < fmt.Sprint(nearMisses[j].Component)
+	})
+}
+
+// boundaryOf returns the private module which prevents the component from being injected into the consumer
+func (m *componentRepository) boundaryOf(com Component, consumer Consumer) Module {
+	if len(m.modulesByProvider) == 0 {
+		return nil
+	}
+
+	consumerModules, ok := m.modulesByProvider[consumer]
+	if _, isProvider := consumer.(Provider); !ok && !isProvider && m.root != nil {
+		// consumers of the container, such as by ValueOf, are in the root module, they see
+		// components of the root module and components exported to it
+		consumerModules = []Module{m.root}
+	}
+	isInModule := func(sm Module) bool {
+		for _, cm := range consumerModules {
+			if cm == sm {
+				return true
+			}
+		}
+		return false
+	}
+
+	if owners, ok := m.ownersOfDefaults[com.Provider()]; ok {
+		visible := false
+		for _, owner := range owners {
+			if isInModule(owner) {
+				visible = true
+				break
+			}
+		}
+		if !visible {
+			return owners[0]
+		}
+	}
+
+	for _, sm := range m.modulesByProvider[com.Provider()] {
+		if !sm.Private() || isInModule(sm) {
+			continue
+		}
+
+		if !IsExported(sm, com) {
+			return sm
+		}
+	}
+
+	return nil
 }
 
 func (m *componentRepository) typeEntryOf(t reflect.Type) typeEntry {
@@ -185,6 +390,10 @@ func componentMatch(com Component, cri Criteria) bool {
 		return false
 	}
 
+	return criteriaMatchExceptType(com, cri)
+}
+
+func criteriaMatchExceptType(com Component, cri Criteria) bool {
 	if cri.Name() != "" {
 		if com.Name() != cri.Name() {
 			return false
@@ -227,6 +436,180 @@ func NewRepository(components ComponentCollection) ComponentRepository {
 	components.Each(func(com Component) {
 		matcher.addComponent(com)
 	})
+	matcher.addScopeInputs()
 
 	return matcher
 }
+
+// addScopeInputs adds inputs of scopes of all components and their ancestors,
+// the inputs are seeded when entering the scope
+func (m *componentRepository) addScopeInputs() {
+	visited := map[Scope]struct{}{}
+	var visit func(s Scope)
+	visit = func(s Scope) {
+		if _, ok := visited[s]; ok || s == nil {
+			return
+		}
+		visited[s] = struct{}{}
+
+		for _, input := range s.Inputs() {
+			input.Components().Each(func(com Component) {
+				m.addComponent(com)
+			})
+		}
+		for _, p := range s.Parents() {
+			visit(p)
+		}
+	}
+
+	var scopes []Scope
+	for s := range m.componentsByScope {
+		scopes = append(scopes, s)
+	}
+	for _, s := range scopes {
+		visit(s)
+	}
+}
+
+// NewRepositoryOfModule creates a repository with all components in the module,
+// and the visibility of components in private modules is respected
+func NewRepositoryOfModule(module Module) ComponentRepository {
+	rep := NewRepository(module.AllComponents()).(*componentRepository)
+
+	modulesByProvider := map[Consumer][]Module{}
+	hasPrivate := false
+
+	var walk func(m Module, path []Module)
+	walk = func(m Module, path []Module) {
+		if m.Private() {
+			hasPrivate = true
+		}
+		// innermost module first
+		path = append([]Module{m}, path...)
+
+		m.Providers().Iterate(func(p Provider) bool {
+			modules := modulesByProvider[p]
+			for _, pm := range path {
+				found := false
+				for _, existing := range modules {
+					if existing == pm {
+						found = true
+						break
+					}
+				}
+				if !found {
+					modules = append(modules, pm)
+				}
+			}
+			modulesByProvider[p] = modules
+			return true
+		})
+
+		m.SubModules().Iterate(func(sm Module) bool {
+			walk(sm, path)
+			return true
+		})
+	}
+	walk(module, nil)
+
+	owners := map[Provider][]Module{}
+	for p, ms := range defaultOwners(module) {
+		if _, ok := modulesByProvider[p]; ok {
+			owners[p] = ms
+		}
+	}
+
+	if hasPrivate || len(owners) > 0 {
+		rep.modulesByProvider = modulesByProvider
+		rep.ownersOfDefaults = owners
+		rep.root = module
+	}
+
+	return rep
+}
+
+type criteriaOfDependencyMatcher interface {
+	componentsMatchCriteriaOfDependency(cri Criteria, dep Dependency) ComponentCollection
+}
+
+// layeredRepository contains components in both the parent and the child repository
+type layeredRepository struct {
+	parent ComponentRepository
+	child  ComponentRepository
+}
+
+var _ ComponentRepository = &layeredRepository{}
+
+// LayerRepository creates a repository with components in the parent and the child,
+// dependencies are matched in both of them, and fallbacks are used only if neither matches
+func LayerRepository(parent ComponentRepository, child ComponentRepository) ComponentRepository {
+	return &layeredRepository{parent: parent, child: child}
+}
+
+func (r *layeredRepository) AllComponents() ComponentCollection {
+	return CombineComponents(r.parent.AllComponents(), r.child.AllComponents()).Distinct()
+}
+
+func (r *layeredRepository) ComponentsMatch(cri Criteria) ComponentCollection {
+	return CombineComponents(r.parent.ComponentsMatch(cri), r.child.ComponentsMatch(cri)).Distinct()
+}
+
+func (r *layeredRepository) ComponentsWithScope(scope Scope) ComponentCollection {
+	return CombineComponents(r.parent.ComponentsWithScope(scope), r.child.ComponentsWithScope(scope)).Distinct()
+}
+
+func (r *layeredRepository) ComponentsMatchDependency(dep Dependency) ComponentCollection {
+	if dep == nil {
+		return EmptyComponents()
+	}
+
+	if isCriteriaMatchAll(dep) {
+		return CombineComponents(r.parent.ComponentsMatchDependency(dep), r.child.ComponentsMatchDependency(dep)).Distinct()
+	}
+
+	coms := r.componentsMatchCriteriaOfDependency(dep, dep)
+
+	if fd, ok := dep.(withFallbacks); ok {
+		for _, cri := range fd.Fallbacks() {
+			if len(coms.ToArray()) > 0 {
+				break
+			}
+			coms = r.componentsMatchCriteriaOfDependency(cri, dep)
+		}
+	}
+
+	return coms
+}
+
+func (r *layeredRepository) componentsMatchCriteriaOfDependency(cri Criteria, dep Dependency) ComponentCollection {
+	var its []ComponentIterator
+	for _, rep := range []ComponentRepository{r.parent, r.child} {
+		if m, ok := rep.(criteriaOfDependencyMatcher); ok {
+			its = append(its, m.componentsMatchCriteriaOfDependency(cri, dep))
+		} else {
+			its = append(its, rep.ComponentsMatchDependency(dep))
+		}
+	}
+	return CombineComponents(its...).Distinct()
+}
+
+func (r *layeredRepository) BlockedComponentsOfDependency(dep Dependency) []BlockedComponent {
+	return append(r.parent.BlockedComponentsOfDependency(dep), r.child.BlockedComponentsOfDependency(dep)...)
+}
+
+func (r *layeredRepository) NearMissesOfDependency(dep Dependency) []NearMiss {
+	var nearMisses []NearMiss
+	seen := map[Component]struct{}{}
+	for _, rep := range []ComponentRepository{r.parent, r.child} {
+		for _, nm := range rep.NearMissesOfDependency(dep) {
+			if _, ok := seen[nm.Component]; ok {
+				continue
+			}
+			seen[nm.Component] = struct{}{}
+			nearMisses = append(nearMisses, nm)
+		}
+	}
+
+	sortNearMisses(nearMisses)
+	return nearMisses
+}
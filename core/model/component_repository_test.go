@@ -1,6 +1,7 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -320,6 +321,91 @@ func Test_componentRepository_ComponentsMatchDependency(t *testing.T) {
 	})
 }
 
+type nearMissInterface interface {
+	nearMiss()
+}
+
+type nearMissStruct struct{}
+
+func (s *nearMissStruct) nearMiss() {}
+
+func Test_componentRepository_NearMissesOfDependency(t *testing.T) {
+	scope1 := NewScope("scope1")
+
+	m := NewModule(
+		Func(func(a int, b string, c int8, d nearMissStruct, e nearMissInterface) float64 {
+			return 0
+		}, Param(0, ByName("two"))),
+		Value(1, Name("one")),
+		Value("a", Hide()),
+		Value(int8(1), InScope(scope1)),
+		Value(&nearMissStruct{}),
+	)
+	rep := NewRepository(m.AllComponents())
+
+	var consumer Consumer
+	rep.AllComponents().Each(func(com Component) {
+		if com.Type() == TypeOf(0.0) {
+			consumer = com.Provider()
+		}
+	})
+	deps := dependencyIteratorToArray(consumer.Dependencies())
+
+	nearMissOf := func(t *testing.T, index int) NearMiss {
+		for _, dep := range deps {
+			if i, ok := ParamIndexOfDependency(dep); ok && i == index {
+				assert.Equal(t, 0, len(rep.ComponentsMatchDependency(dep).ToArray()))
+				nearMisses := rep.NearMissesOfDependency(dep)
+				assert.Equal(t, 1, len(nearMisses))
+				return nearMisses[0]
+			}
+		}
+		t.Fatalf("dependency at %v is not found", index)
+		return NearMiss{}
+	}
+
+	t.Run("dep is nil", func(t *testing.T) {
+		assert.Nil(t, rep.NearMissesOfDependency(nil))
+	})
+
+	t.Run("name or tags", func(t *testing.T) {
+		nm := nearMissOf(t, 0)
+		assert.Equal(t, NearMissNameOrTags, nm.Kind)
+		assert.Equal(t, "one", nm.Component.Name())
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		nm := nearMissOf(t, 1)
+		assert.Equal(t, NearMissHidden, nm.Kind)
+		assert.Equal(t, TypeOf(""), nm.Component.Type())
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		nm := nearMissOf(t, 2)
+		assert.Equal(t, NearMissScope, nm.Kind)
+		assert.Same(t, scope1, nm.Component.Provider().Scope())
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		nm := nearMissOf(t, 3)
+		assert.Equal(t, NearMissPointer, nm.Kind)
+		assert.Equal(t, TypeOf(&nearMissStruct{}), nm.Component.Type())
+	})
+
+	t.Run("as", func(t *testing.T) {
+		nm := nearMissOf(t, 4)
+		assert.Equal(t, NearMissAs, nm.Kind)
+		assert.Equal(t, TypeOf(&nearMissStruct{}), nm.Component.Type())
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		layered := LayerRepository(rep, rep)
+		for _, dep := range deps {
+			assert.Equal(t, rep.NearMissesOfDependency(dep), layered.NearMissesOfDependency(dep))
+		}
+	})
+}
+
 func Test_componentMatch(t *testing.T) {
 	tag1 := NewSymbol("tag1")
 	tag2 := NewSymbol("tag2")
@@ -439,3 +525,174 @@ func TestNewRepository(t *testing.T) {
 	assert.Equal(t, 3, len(rep.AllComponents().ToArray()))
 	assert.Equal(t, it.ToSet(), rep.AllComponents().ToSet())
 }
+
+func TestNewRepositoryOfModule(t *testing.T) {
+	type repo interface{}
+	type repoImpl struct{}
+	type helper struct{}
+
+	inner := NewModule(
+		Export(NewCriteria((*repo)(nil))),
+		Struct(&repoImpl{}, As((*repo)(nil)), IgnoreFields(func(reflect.StructField) bool { return true })),
+		Value(helper{}),
+		Func(func(h helper) int { return 1 }),
+	)
+	outer := NewModule(
+		Private(),
+		SubModule(inner),
+		Func(func(r repo) string { return "" }),
+		Func(func(h helper) float64 { return 0 }),
+	)
+	root := NewModule(
+		SubModule(outer),
+		Func(func(r repo) int8 { return 0 }),
+	)
+
+	rep := NewRepositoryOfModule(root)
+
+	depOf := func(t *testing.T, consumerType reflect.Type) Dependency {
+		var dep Dependency
+		rep.AllComponents().Each(func(com Component) {
+			if com.Type() == consumerType {
+				com.Provider().Dependencies().Iterate(func(d Dependency) bool {
+					dep = d
+					return false
+				})
+			}
+		})
+		if dep == nil {
+			t.Fatalf("dependency of %v not found", consumerType)
+		}
+		return dep
+	}
+
+	t.Run("visible in the same module", func(t *testing.T) {
+		dep := depOf(t, TypeOf(1))
+		assert.Equal(t, 1, len(rep.ComponentsMatchDependency(dep).ToArray()))
+		assert.Nil(t, rep.BlockedComponentsOfDependency(dep))
+	})
+
+	t.Run("exported to parent module", func(t *testing.T) {
+		dep := depOf(t, TypeOf(""))
+		assert.Equal(t, 1, len(rep.ComponentsMatchDependency(dep).ToArray()))
+	})
+
+	t.Run("not exported to parent module", func(t *testing.T) {
+		dep := depOf(t, TypeOf(0.0))
+		assert.Equal(t, 0, len(rep.ComponentsMatchDependency(dep).ToArray()))
+
+		blocked := rep.BlockedComponentsOfDependency(dep)
+		assert.Equal(t, 1, len(blocked))
+		assert.Equal(t, TypeOf(helper{}), blocked[0].Component.Type())
+		assert.Same(t, inner, blocked[0].Boundary)
+	})
+
+	t.Run("not exported by outer module", func(t *testing.T) {
+		dep := depOf(t, TypeOf(int8(0)))
+		assert.Equal(t, 0, len(rep.ComponentsMatchDependency(dep).ToArray()))
+
+		blocked := rep.BlockedComponentsOfDependency(dep)
+		assert.Equal(t, 1, len(blocked))
+		assert.Same(t, outer, blocked[0].Boundary)
+	})
+
+	t.Run("consumer outside modules", func(t *testing.T) {
+		vc := ValueConsumer((*repo)(nil)).Consumer()
+		var dep Dependency
+		vc.Dependencies().Iterate(func(d Dependency) bool {
+			dep = d
+			return false
+		})
+		assert.Equal(t, 0, len(rep.ComponentsMatchDependency(dep).ToArray()))
+		assert.Equal(t, 1, len(rep.BlockedComponentsOfDependency(dep)))
+	})
+
+	t.Run("consumer of private root module", func(t *testing.T) {
+		rep2 := NewRepositoryOfModule(inner)
+		dep := dependencyIteratorToArray(ValueConsumer(helper{}).Consumer().Dependencies())[0]
+		assert.Equal(t, 1, len(rep2.ComponentsMatchDependency(dep).ToArray()))
+		assert.Nil(t, rep2.BlockedComponentsOfDependency(dep))
+
+		// providers of other repositories are not in the root module
+		p := Func(func(h helper) bool { return false }).Provider()
+		dep = dependencyIteratorToArray(p.Dependencies())[0]
+		assert.Equal(t, 0, len(rep2.ComponentsMatchDependency(dep).ToArray()))
+		assert.Same(t, inner, rep2.BlockedComponentsOfDependency(dep)[0].Boundary)
+	})
+
+	t.Run("load all", func(t *testing.T) {
+		all := dependencyIteratorToArray(LoadAllConsumer(GlobalScope).Consumer().Dependencies())[0]
+		var types []reflect.Type
+		rep.ComponentsMatchDependency(all).Each(func(com Component) {
+			types = append(types, com.Type())
+		})
+		assert.Equal(t, []reflect.Type{TypeOf(int8(0))}, types)
+
+		layered := LayerRepository(rep, NewRepository(NewModule(Value(true)).AllComponents()))
+		assert.Equal(t, 2, len(layered.ComponentsMatchDependency(all).ToArray()))
+	})
+
+	t.Run("module without private modules", func(t *testing.T) {
+		rep2 := NewRepositoryOfModule(NewModule(Value(1), Func(func(int) string { return "" })))
+		rep2.AllComponents().Each(func(com Component) {
+			com.Provider().Dependencies().Iterate(func(d Dependency) bool {
+				assert.Equal(t, 1, len(rep2.ComponentsMatchDependency(d).ToArray()))
+				assert.Nil(t, rep2.BlockedComponentsOfDependency(d))
+				return true
+			})
+		})
+	})
+}
+
+func TestLayerRepository(t *testing.T) {
+	scope1 := NewScope("scope1")
+	parent := NewRepository(NewModule(
+		Value(1, Name("name1")),
+		Value("a", Name("name2")),
+		Value(2, InScope(scope1), Name("name3")),
+	).AllComponents())
+	child := NewRepository(NewModule(
+		Value(3, Name("name4")),
+		Func(func(i int) float64 { return 0 }, InScope(scope1), Return(0, Name("name5"))),
+	).AllComponents())
+	rep := LayerRepository(parent, child)
+
+	names := func(coms ComponentCollection) []string {
+		var res []string
+		coms.Each(func(com Component) {
+			res = append(res, com.Name())
+		})
+		return res
+	}
+
+	t.Run("AllComponents", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"name1", "name2", "name3", "name4", "name5"},
+			names(rep.AllComponents()))
+	})
+
+	t.Run("ComponentsMatch", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"name1", "name3", "name4"}, names(rep.ComponentsMatch(criOf(0))))
+		assert.ElementsMatch(t, []string{"name4"}, names(rep.ComponentsMatch(criOf(0, ByName("name4")))))
+	})
+
+	t.Run("ComponentsWithScope", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"name3", "name5"}, names(rep.ComponentsWithScope(scope1)))
+	})
+
+	t.Run("ComponentsMatchDependency", func(t *testing.T) {
+		com := child.AllComponents().ToArray()
+		var dep Dependency
+		for _, c := range com {
+			if c.Name() == "name5" {
+				dep = dependencyIteratorToArray(c.Provider().Dependencies())[0]
+			}
+		}
+		assert.ElementsMatch(t, []string{"name1", "name3", "name4"}, names(rep.ComponentsMatchDependency(dep)))
+		assert.Equal(t, 0, len(rep.ComponentsMatchDependency(nil).ToArray()))
+
+		all := dependencyIteratorToArray(LoadAllConsumer(scope1).Consumer().Dependencies())[0]
+		assert.ElementsMatch(t, []string{"name3", "name5"}, names(rep.ComponentsMatchDependency(all)))
+
+		assert.Nil(t, rep.BlockedComponentsOfDependency(dep))
+	})
+}
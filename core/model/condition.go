@@ -0,0 +1,214 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Environment describes what is switched on when a container is created,
+// conditions of modules are evaluated against it
+type Environment interface {
+	ProfileActive(name string) bool
+	ActiveProfiles() []string
+}
+
+type environment struct {
+	profiles map[string]struct{}
+}
+
+func (e *environment) ProfileActive(name string) bool {
+	if e == nil {
+		return false
+	}
+
+	_, ok := e.profiles[name]
+	return ok
+}
+
+func (e *environment) ActiveProfiles() []string {
+	if e == nil {
+		return nil
+	}
+
+	return sortedProfiles(e.profiles)
+}
+
+func NewEnvironment(activeProfiles ...string) Environment {
+	env := &environment{profiles: map[string]struct{}{}}
+	for _, p := range activeProfiles {
+		env.profiles[p] = struct{}{}
+	}
+	return env
+}
+
+// Condition decides whether a module is active in an Environment
+type Condition interface {
+	Satisfied(env Environment) bool
+	// Profiles returns all profiles referenced by the condition
+	Profiles() []string
+}
+
+type profileCondition struct {
+	profiles []string
+}
+
+func (c *profileCondition) Satisfied(env Environment) bool {
+	if env == nil {
+		return false
+	}
+
+	for _, p := range c.profiles {
+		if env.ProfileActive(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *profileCondition) Profiles() []string {
+	return c.profiles
+}
+
+func (c *profileCondition) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "OnProfile%q", c.profiles)
+}
+
+// OnProfile is satisfied if any of the profiles is active
+func OnProfile(profiles ...string) Condition {
+	return &profileCondition{profiles: profiles}
+}
+
+type envCondition struct {
+	key   string
+	value string
+}
+
+func (c *envCondition) Satisfied(_ Environment) bool {
+	return os.Getenv(c.key) == c.value
+}
+
+func (c *envCondition) Profiles() []string {
+	return nil
+}
+
+func (c *envCondition) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "OnEnv(%v=%q)", c.key, c.value)
+}
+
+// OnEnv is satisfied if the environment variable `key` equals to `value`
+// when the container is created
+func OnEnv(key string, value string) Condition {
+	return &envCondition{key: key, value: value}
+}
+
+type switchCondition bool
+
+func (c switchCondition) Satisfied(_ Environment) bool {
+	return bool(c)
+}
+
+func (c switchCondition) Profiles() []string {
+	return nil
+}
+
+func (c switchCondition) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "OnSwitch(%v)", bool(c))
+}
+
+// OnSwitch is used with build-time switches, such as a constant defined in files with build tags
+func OnSwitch(on bool) Condition {
+	return switchCondition(on)
+}
+
+type funcCondition struct {
+	f func(Environment) bool
+}
+
+func (c *funcCondition) Satisfied(env Environment) bool {
+	if c.f == nil {
+		return false
+	}
+	return c.f(env)
+}
+
+func (c *funcCondition) Profiles() []string {
+	return nil
+}
+
+func (c *funcCondition) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprint(f, "OnFunc")
+}
+
+func OnFunc(f func(env Environment) bool) Condition {
+	return &funcCondition{f: f}
+}
+
+type notCondition struct {
+	cond Condition
+}
+
+func (c *notCondition) Satisfied(env Environment) bool {
+	return !conditionSatisfied(c.cond, env)
+}
+
+func (c *notCondition) Profiles() []string {
+	if c.cond == nil {
+		return nil
+	}
+	return c.cond.Profiles()
+}
+
+func (c *notCondition) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprintf(f, "Not(%v)", c.cond)
+}
+
+func Not(cond Condition) Condition {
+	return &notCondition{cond: cond}
+}
+
+type allConditions []Condition
+
+func (cs allConditions) Satisfied(env Environment) bool {
+	for _, c := range cs {
+		if !conditionSatisfied(c, env) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cs allConditions) Profiles() []string {
+	var profiles []string
+	for _, c := range cs {
+		profiles = append(profiles, c.Profiles()...)
+	}
+	return profiles
+}
+
+func (cs allConditions) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprint(f, "[")
+	for i, c := range cs {
+		if i > 0 {
+			_, _ = fmt.Fprint(f, " && ")
+		}
+		_, _ = fmt.Fprintf(f, "%v", c)
+	}
+	_, _ = fmt.Fprint(f, "]")
+}
+
+func conditionSatisfied(cond Condition, env Environment) bool {
+	if cond == nil {
+		return true
+	}
+	return cond.Satisfied(env)
+}
+
+func sortedProfiles(profiles map[string]struct{}) []string {
+	var res []string
+	for p := range profiles {
+		res = append(res, p)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnvironment(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		env := NewEnvironment()
+		assert.False(t, env.ProfileActive("dev"))
+		assert.Nil(t, env.ActiveProfiles())
+	})
+
+	t.Run("profiles", func(t *testing.T) {
+		env := NewEnvironment("prod", "dev", "prod")
+		assert.True(t, env.ProfileActive("dev"))
+		assert.True(t, env.ProfileActive("prod"))
+		assert.False(t, env.ProfileActive("staging"))
+		assert.Equal(t, []string{"dev", "prod"}, env.ActiveProfiles())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var env *environment
+		assert.False(t, env.ProfileActive("dev"))
+		assert.Nil(t, env.ActiveProfiles())
+	})
+}
+
+func TestOnProfile(t *testing.T) {
+	cond := OnProfile("dev", "staging")
+	assert.Equal(t, []string{"dev", "staging"}, cond.Profiles())
+
+	assert.True(t, cond.Satisfied(NewEnvironment("dev")))
+	assert.True(t, cond.Satisfied(NewEnvironment("staging", "prod")))
+	assert.False(t, cond.Satisfied(NewEnvironment("prod")))
+	assert.False(t, cond.Satisfied(nil))
+
+	assert.Equal(t, `OnProfile["dev" "staging"]`, fmt.Sprintf("%v", cond))
+}
+
+func TestOnEnv(t *testing.T) {
+	key := "UNI_TEST_ON_ENV"
+	cond := OnEnv(key, "on")
+	assert.Nil(t, cond.Profiles())
+
+	_ = os.Unsetenv(key)
+	assert.False(t, cond.Satisfied(NewEnvironment()))
+
+	_ = os.Setenv(key, "on")
+	defer func() { _ = os.Unsetenv(key) }()
+	assert.True(t, cond.Satisfied(NewEnvironment()))
+
+	assert.Equal(t, `OnEnv(UNI_TEST_ON_ENV="on")`, fmt.Sprintf("%v", cond))
+}
+
+func TestOnSwitch(t *testing.T) {
+	assert.True(t, OnSwitch(true).Satisfied(nil))
+	assert.False(t, OnSwitch(false).Satisfied(nil))
+	assert.Nil(t, OnSwitch(true).Profiles())
+	assert.Equal(t, "OnSwitch(true)", fmt.Sprintf("%v", OnSwitch(true)))
+}
+
+func TestOnFunc(t *testing.T) {
+	cond := OnFunc(func(env Environment) bool {
+		return env.ProfileActive("dev") && !env.ProfileActive("prod")
+	})
+	assert.Nil(t, cond.Profiles())
+	assert.True(t, cond.Satisfied(NewEnvironment("dev")))
+	assert.False(t, cond.Satisfied(NewEnvironment("dev", "prod")))
+
+	assert.False(t, OnFunc(nil).Satisfied(NewEnvironment()))
+	assert.Equal(t, "OnFunc", fmt.Sprintf("%v", cond))
+}
+
+func TestNot(t *testing.T) {
+	cond := Not(OnProfile("dev"))
+	assert.Equal(t, []string{"dev"}, cond.Profiles())
+	assert.False(t, cond.Satisfied(NewEnvironment("dev")))
+	assert.True(t, cond.Satisfied(NewEnvironment("prod")))
+	assert.Equal(t, `Not(OnProfile["dev"])`, fmt.Sprintf("%v", cond))
+
+	t.Run("nil", func(t *testing.T) {
+		cond := Not(nil)
+		assert.Nil(t, cond.Profiles())
+		assert.False(t, cond.Satisfied(NewEnvironment()))
+	})
+}
+
+func Test_allConditions(t *testing.T) {
+	cond := allConditions{OnProfile("dev"), OnSwitch(true), Not(OnProfile("prod"))}
+	assert.Equal(t, []string{"dev", "prod"}, cond.Profiles())
+	assert.True(t, cond.Satisfied(NewEnvironment("dev")))
+	assert.False(t, cond.Satisfied(NewEnvironment("dev", "prod")))
+	assert.False(t, cond.Satisfied(NewEnvironment()))
+	assert.Equal(t, `[OnProfile["dev"] && OnSwitch(true) && Not(OnProfile["prod"])]`, fmt.Sprintf("%v", cond))
+
+	assert.True(t, allConditions{}.Satisfied(nil))
+}
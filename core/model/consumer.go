@@ -22,6 +22,8 @@ type baseConsumer struct {
 	scope Scope
 	loc   location.Location
 	val   valuer.Valuer
+	// noLocation is true if the location of the call is not captured
+	noLocation bool
 }
 
 func (c *baseConsumer) Valuer() valuer.Valuer {
@@ -58,9 +60,10 @@ func (c *baseConsumer) clone() *baseConsumer {
 	}
 
 	cloned := &baseConsumer{
-		scope: c.scope,
-		loc:   c.loc,
-		val:   val2,
+		scope:      c.scope,
+		loc:        c.loc,
+		val:        val2,
+		noLocation: c.noLocation,
 	}
 	return cloned
 }
@@ -42,10 +42,18 @@ type dependency struct {
 	val         valuer.Valuer
 	name        string
 	tags        *symbolSet
+	// criteria used in order if nothing matches the dependency
+	fallbacks []Criteria
+	// constraints of types of typed names
+	constraints typeConstraints
 }
 
 var _ Dependency = &dependency{}
 
+type withFallbacks interface {
+	Fallbacks() []Criteria
+}
+
 func (d *dependency) Consumer() Consumer {
 	return d.consumer
 }
@@ -77,6 +85,10 @@ func (d *dependency) Valuer() valuer.Valuer {
 	return d.val
 }
 
+func (d *dependency) Fallbacks() []Criteria {
+	return d.fallbacks
+}
+
 func (d *dependency) Validate() error {
 	errs := errors.Empty()
 
@@ -92,6 +104,9 @@ func (d *dependency) Validate() error {
 			"[%v] can not marked as collector, only `Slice` type dependency can be collector",
 			d.rType)
 	}
+	if err := d.constraints.check(func(t reflect.Type) bool { return d.Type() == t }, d.tags); err != nil {
+		errs = errs.AddErrors(err)
+	}
 	if errs.HasError() {
 		return errs
 	}
@@ -134,6 +149,10 @@ func (d *dependency) Equal(other interface{}) bool {
 	return true
 }
 
+func (d *dependency) addTypeConstraint(tc typeConstraint) {
+	d.constraints = append(d.constraints, tc)
+}
+
 func (d *dependency) clone() *dependency {
 	if d == nil {
 		return nil
@@ -152,6 +171,8 @@ func (d *dependency) clone() *dependency {
 		val:         val2,
 		name:        d.name,
 		tags:        d.tags.clone(),
+		fallbacks:   append([]Criteria(nil), d.fallbacks...),
+		constraints: d.constraints.clone(),
 	}
 
 	return cloned
@@ -57,6 +57,16 @@ func (p *funcParam) Equal(other interface{}) bool {
 	return true
 }
 
+// ParamIndexOfDependency returns the index of the parameter injected by the dependency,
+// if it is a parameter of function
+func ParamIndexOfDependency(dep Dependency) (int, bool) {
+	p, ok := dep.(*funcParam)
+	if !ok || p == nil {
+		return 0, false
+	}
+	return p.index, true
+}
+
 type paramByIndex map[int]*funcParam
 
 func (m paramByIndex) Iterate(f func(Dependency) bool) bool {
@@ -241,9 +251,9 @@ func (fc *funcConsumer) SetLocation(loc location.Location) FuncConsumerBuilder {
 }
 
 func (fc *funcConsumer) UpdateCallLocation(loc location.Location) FuncConsumerBuilder {
-	if fc.Location() == nil {
+	if fc.Location() == nil && !fc.noLocation {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		fc.SetLocation(loc)
 	}
@@ -328,3 +338,9 @@ func (o LocationOption) ApplyFuncConsumer(b FuncConsumerBuilder) {
 func (o UpdateCallLocationOption) ApplyFuncConsumer(b FuncConsumerBuilder) {
 	b.UpdateCallLocation(o.Location)
 }
+
+func (o NoLocationOption) ApplyFuncConsumer(b FuncConsumerBuilder) {
+	if c, ok := b.(*funcConsumer); ok {
+		c.noLocation = true
+	}
+}
@@ -763,3 +763,22 @@ func Test_funcConsumer_Equal(t *testing.T) {
 		})
 	})
 }
+
+func TestParamIndexOfDependency(t *testing.T) {
+	FuncConsumer(func(a int, b string) {}).Consumer().Dependencies().Iterate(func(dep Dependency) bool {
+		index, ok := ParamIndexOfDependency(dep)
+		assert.True(t, ok)
+		if dep.Type() == TypeOf(0) {
+			assert.Equal(t, 0, index)
+		} else {
+			assert.Equal(t, 1, index)
+		}
+		return true
+	})
+
+	StructConsumer(struct{ A int }{}).Consumer().Dependencies().Iterate(func(dep Dependency) bool {
+		_, ok := ParamIndexOfDependency(dep)
+		assert.False(t, ok)
+		return true
+	})
+}
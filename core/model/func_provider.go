@@ -36,6 +36,30 @@ func (fp *funcProvider) Components() ComponentCollection {
 	return ComponentsOfIterator(fp.components)
 }
 
+// FuncOfProvider returns the function called by the provider, if it is a func provider
+func FuncOfProvider(p Provider) (reflect.Value, bool) {
+	fp, ok := p.(*funcProvider)
+	if !ok || fp == nil {
+		return reflect.Value{}, false
+	}
+	return fp.funcVal, true
+}
+
+// ReturnIndexOfComponent returns the index of the return value of function providing the component,
+// if it is provided by a func provider
+func ReturnIndexOfComponent(com Component) (int, bool) {
+	fp, ok := com.Provider().(*funcProvider)
+	if !ok || fp == nil {
+		return 0, false
+	}
+	for index, c := range fp.components {
+		if c == com {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
 func (fp *funcProvider) Validate() error {
 	errs := errors.Empty()
 
@@ -165,6 +189,7 @@ type FuncProviderBuilder interface {
 	Param(index int, opts ...DependencyOption) FuncProviderBuilder
 	Return(index int, opts ...ComponentOption) FuncProviderBuilder
 	SetScope(scope Scope) FuncProviderBuilder
+	SetCachePolicy(policy CachePolicy) FuncProviderBuilder
 	SetLocation(loc location.Location) FuncProviderBuilder
 	UpdateCallLocation(loc location.Location) FuncProviderBuilder
 }
@@ -205,6 +230,11 @@ func (fp *funcProvider) Return(index int, opts ...ComponentOption) FuncProviderB
 	return fp
 }
 
+func (fp *funcProvider) SetCachePolicy(policy CachePolicy) FuncProviderBuilder {
+	fp.setCachePolicy(policy)
+	return fp
+}
+
 func (fp *funcProvider) SetScope(scope Scope) FuncProviderBuilder {
 	fp.baseConsumer.SetScope(scope)
 	return fp
@@ -218,7 +248,7 @@ func (fp *funcProvider) SetLocation(loc location.Location) FuncProviderBuilder {
 func (fp *funcProvider) UpdateCallLocation(loc location.Location) FuncProviderBuilder {
 	if fp.Location() == nil {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		fp.SetLocation(loc)
 	}
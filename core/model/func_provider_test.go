@@ -967,3 +967,32 @@ func Test_funcProvider_Equal(t *testing.T) {
 		})
 	})
 }
+
+func TestFuncOfProvider(t *testing.T) {
+	f := func() (int, error, string) { return 0, nil, "" }
+	p := Func(f).Provider()
+	funcVal, ok := FuncOfProvider(p)
+	assert.True(t, ok)
+	assert.Equal(t, reflect.ValueOf(f).Pointer(), funcVal.Pointer())
+
+	_, ok = FuncOfProvider(Value(1).Provider())
+	assert.False(t, ok)
+}
+
+func TestReturnIndexOfComponent(t *testing.T) {
+	p := Func(func() (int, error, string) { return 0, nil, "" }).Provider()
+	p.Components().Each(func(com Component) {
+		index, ok := ReturnIndexOfComponent(com)
+		assert.True(t, ok)
+		if com.Type() == TypeOf(0) {
+			assert.Equal(t, 0, index)
+		} else {
+			assert.Equal(t, 2, index)
+		}
+	})
+
+	Value(1).Provider().Components().Each(func(com Component) {
+		_, ok := ReturnIndexOfComponent(com)
+		assert.False(t, ok)
+	})
+}
@@ -220,7 +220,7 @@ func (c *loadCriteriaConsumer) SetLocation(loc location.Location) LoadCriteriaCo
 func (c *loadCriteriaConsumer) UpdateCallLocation(loc location.Location) LoadCriteriaConsumerBuilder {
 	if c.Location() == nil {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		c.SetLocation(loc)
 	}
@@ -341,7 +341,7 @@ func (l *loadAllConsumer) SetLocation(loc location.Location) LoadAllConsumerBuil
 func (l *loadAllConsumer) UpdateCallLocation(loc location.Location) LoadAllConsumerBuilder {
 	if l.Location() == nil {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		l.SetLocation(loc)
 	}
@@ -1,9 +1,11 @@
 package model
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
 )
 
 type ModuleIterator interface {
@@ -44,12 +46,31 @@ type Module interface {
 	AllProviders() ProviderIterator
 	AllComponents() ComponentCollection
 
+	// Private components in a private module can only be injected into providers in the same module,
+	// unless they are exported
+	Private() bool
+	Exports() []Criteria
+	// Inputs are components the module needs from outside
+	Inputs() []ModuleInput
+
+	Condition() Condition
+	DeclaredProfiles() []string
+	// Activate returns a module without the sub modules whose condition is not satisfied in env
+	Activate(env Environment) (Module, error)
+
+	Location() location.Location
 	Validate() error
 }
 
 type module struct {
 	subModules moduleSet
 	providers  providerSet
+	private    bool
+	exports    []Criteria
+	inputs     []ModuleInput
+	conditions allConditions
+	profiles   map[string]struct{}
+	loc        location.Location
 }
 
 func newModule(modules []Module, pbs []ProviderBuilder) *module {
@@ -89,6 +110,153 @@ func (m *module) Providers() ProviderIterator {
 	return m.providers
 }
 
+func (m *module) Private() bool {
+	return m.private
+}
+
+func (m *module) Exports() []Criteria {
+	return m.exports
+}
+
+// IsExported checks if the component is exported by the module
+func IsExported(m Module, com Component) bool {
+	for _, cri := range m.Exports() {
+		if componentMatch(com, cri) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *module) Inputs() []ModuleInput {
+	return m.inputs
+}
+
+func (m *module) Condition() Condition {
+	if len(m.conditions) == 0 {
+		return nil
+	}
+	return m.conditions
+}
+
+func (m *module) DeclaredProfiles() []string {
+	return sortedProfiles(m.profiles)
+}
+
+func (m *module) Location() location.Location {
+	return m.loc
+}
+
+func (m *module) Format(f fmt.State, r rune) {
+	_, _ = fmt.Fprint(f, "Module")
+	if m.loc != nil {
+		if f.Flag('+') && r == 'v' {
+			_, _ = fmt.Fprintf(f, " at %+v", m.loc)
+		} else {
+			_, _ = fmt.Fprintf(f, " at %v", m.loc)
+		}
+	}
+}
+
+func allDeclaredProfiles(m Module) map[string]struct{} {
+	profiles := map[string]struct{}{}
+	m.AllModules().Iterate(func(sm Module) bool {
+		for _, p := range sm.DeclaredProfiles() {
+			profiles[p] = struct{}{}
+		}
+		return true
+	})
+	return profiles
+}
+
+func (m *module) Activate(env Environment) (Module, error) {
+	return m.activateWithin(env, nil)
+}
+
+// ActivateWithin is like Activate, but inputs of modules may be provided by components in outer,
+// such as components of the parent container, then their defaults are not used
+func ActivateWithin(m Module, env Environment, outer ComponentRepository) (Module, error) {
+	if mm, ok := m.(*module); ok {
+		return mm.activateWithin(env, outer)
+	}
+	return m.Activate(env)
+}
+
+func (m *module) activateWithin(env Environment, outer ComponentRepository) (Module, error) {
+	if env == nil {
+		env = NewEnvironment()
+	}
+
+	declared := allDeclaredProfiles(m)
+	errs := errors.Empty()
+	for _, p := range env.ActiveProfiles() {
+		if _, ok := declared[p]; !ok {
+			errs = errs.AddErrorf("profile %q is not declared in any module", p)
+		}
+	}
+	if errs.HasError() {
+		return nil, errs.WithMainf("unknown active profiles")
+	}
+
+	if !conditionSatisfied(m.Condition(), env) {
+		return newModule(nil, nil), nil
+	}
+
+	am := m.activate(env, map[Module]Module{})
+	return withDefaultInputs(am, outer), nil
+}
+
+// withDefaultInputs adds default providers of inputs that are not provided to the module
+func withDefaultInputs(m Module, outer ComponentRepository) Module {
+	defaults := defaultProvidersOfInputs(m, outer)
+	if len(defaults) == 0 {
+		return m
+	}
+
+	wrapper := newModule([]Module{m}, nil)
+	for _, p := range defaults {
+		wrapper.providers[p] = struct{}{}
+	}
+	wrapper.loc = m.Location()
+	return wrapper
+}
+
+func (m *module) activate(env Environment, activated map[Module]Module) Module {
+	if am, ok := activated[m]; ok {
+		return am
+	}
+
+	// conditions have been satisfied
+	cloned := *m
+	cloned.conditions = nil
+	cloned.subModules = moduleSet{}
+	activated[m] = &cloned
+
+	changed := len(m.conditions) > 0
+	m.subModules.Iterate(func(sm Module) bool {
+		if !conditionSatisfied(sm.Condition(), env) {
+			changed = true
+			return true
+		}
+
+		asm := sm
+		if smm, ok := sm.(*module); ok {
+			asm = smm.activate(env, activated)
+		}
+		if asm != sm {
+			changed = true
+		}
+		cloned.subModules[asm] = struct{}{}
+		return true
+	})
+
+	if !changed {
+		activated[m] = m
+		return m
+	}
+	return &cloned
+}
+
 func (m *module) AllModules() ModuleIterator {
 	ms := moduleSet{}
 
@@ -129,32 +297,93 @@ func (m *module) AllComponents() ComponentCollection {
 	return cs
 }
 
+type guardedComponent struct {
+	com Component
+	// conditional modules on the path from root module to the provider of component
+	guards []Module
+}
+
+func isGuardsPrefix(prefix []Module, guards []Module) bool {
+	if len(prefix) > len(guards) {
+		return false
+	}
+	for i, g := range prefix {
+		if guards[i] != g {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *module) guardedComponents() []guardedComponent {
+	var coms []guardedComponent
+	visitedProviders := providerSet{}
+
+	var walk func(sm Module, guards []Module)
+	walk = func(sm Module, guards []Module) {
+		if sm.Condition() != nil {
+			guards = append(guards[:len(guards):len(guards)], sm)
+		}
+
+		sm.Providers().Iterate(func(p Provider) bool {
+			if _, ok := visitedProviders[p]; ok {
+				return true
+			}
+			visitedProviders[p] = struct{}{}
+
+			p.Components().Each(func(c Component) {
+				coms = append(coms, guardedComponent{com: c, guards: guards})
+			})
+			return true
+		})
+
+		sm.SubModules().Iterate(func(subModule Module) bool {
+			walk(subModule, guards)
+			return true
+		})
+	}
+	walk(m, nil)
+
+	return coms
+}
+
+// components with the same name are duplicate only if they are active at the same time,
+// that is the guards of one of them are the prefix of the guards of the other one,
+// components in different conditional modules will be checked again after activating.
 func (m *module) validateDuplicateComponentName() error {
 	type dupNameKey struct {
 		rType reflect.Type
 		name  string
 	}
 
-	componentsWithSameName := map[dupNameKey]componentSet{}
-	m.AllComponents().Each(func(c Component) {
-		if c.Name() != "" {
-			key := dupNameKey{c.Type(), c.Name()}
-			if comSet, ok := componentsWithSameName[key]; ok {
-				comSet.Add(c)
-			} else {
-				comSet = newComponentSet()
-				comSet.Add(c)
-				componentsWithSameName[key] = comSet
-			}
+	componentsWithSameName := map[dupNameKey][]guardedComponent{}
+	for _, gc := range m.guardedComponents() {
+		if gc.com.Name() != "" {
+			key := dupNameKey{gc.com.Type(), gc.com.Name()}
+			componentsWithSameName[key] = append(componentsWithSameName[key], gc)
 		}
-	})
+	}
 
 	errs := errors.Empty()
 	for k, v := range componentsWithSameName {
-		if len(v) > 1 {
+		if len(v) <= 1 {
+			continue
+		}
+
+		dupSet := newComponentSet()
+		for i := 0; i < len(v); i++ {
+			for j := i + 1; j < len(v); j++ {
+				if isGuardsPrefix(v[i].guards, v[j].guards) || isGuardsPrefix(v[j].guards, v[i].guards) {
+					dupSet.Add(v[i].com)
+					dupSet.Add(v[j].com)
+				}
+			}
+		}
+
+		if len(dupSet) > 1 {
 			err := errors.Empty()
 
-			v.Each(func(com Component) {
+			dupSet.Each(func(com Component) {
 				err = err.AddErrorf("%v at %v", com, com.Provider().Location())
 			})
 
@@ -169,11 +398,64 @@ func (m *module) validateDuplicateComponentName() error {
 	return nil
 }
 
+func (m *module) validateExports() error {
+	errs := errors.Empty()
+	m.AllModules().Iterate(func(sm Module) bool {
+		for _, cri := range sm.Exports() {
+			found := false
+			sm.AllComponents().Iterate(func(com Component) bool {
+				if componentMatch(com, cri) {
+					found = true
+					return false
+				}
+				return true
+			})
+
+			if !found {
+				errs = errs.AddErrorf("%v exports %v, but there is no component match it", sm, cri)
+			}
+		}
+		return true
+	})
+
+	if errs.HasError() {
+		return errs.WithMainf("there are exports match nothing")
+	}
+	return nil
+}
+
+func (m *module) validateConditions() error {
+	declared := allDeclaredProfiles(m)
+
+	errs := errors.Empty()
+	m.AllModules().Iterate(func(sm Module) bool {
+		cond := sm.Condition()
+		if cond == nil {
+			return true
+		}
+
+		for _, p := range cond.Profiles() {
+			if _, ok := declared[p]; !ok {
+				errs = errs.AddErrorf("condition %v of %v references unknown profile %q", cond, sm, p)
+			}
+		}
+		return true
+	})
+
+	if errs.HasError() {
+		return errs.WithMainf("there are conditions reference profiles that are not declared")
+	}
+	return nil
+}
+
 func (m *module) validateProviders() error {
 	providersByPackage := map[string]map[Provider]struct{}{}
 
 	m.AllProviders().Iterate(func(p Provider) bool {
-		pkg := p.Location().PkgName()
+		pkg := ""
+		if loc := p.Location(); loc != nil {
+			pkg = loc.PkgName()
+		}
 
 		providers := providersByPackage[pkg]
 		if providers == nil {
@@ -215,6 +497,18 @@ func (m *module) validateProviders() error {
 func (m *module) Validate() error {
 	errs := errors.Empty()
 
+	if err := m.validateConditions(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if err := m.validateExports(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if err := m.validateDefaults(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
 	if err := m.validateDuplicateComponentName(); err != nil {
 		errs = errs.AddErrors(err)
 	}
@@ -232,6 +526,7 @@ func (m *module) Validate() error {
 
 func NewModule(opts ...ModuleOption) Module {
 	mb := &moduleBuilder{}
+	mb.SetLocation(location.GetLocation(2))
 	for _, o := range opts {
 		if o == nil {
 			continue
@@ -244,6 +539,12 @@ func NewModule(opts ...ModuleOption) Module {
 type ModuleBuilder interface {
 	AddModule(m Module) ModuleBuilder
 	AddProvider(p ProviderBuilder) ModuleBuilder
+	SetPrivate(private bool) ModuleBuilder
+	AddExports(criteriaList ...CriteriaBuilder) ModuleBuilder
+	AddInput(cri CriteriaBuilder, defaultProvider ProviderBuilder, loc location.Location) ModuleBuilder
+	AddCondition(cond Condition) ModuleBuilder
+	DeclareProfiles(profiles ...string) ModuleBuilder
+	SetLocation(loc location.Location) ModuleBuilder
 	Module() Module
 }
 
@@ -254,6 +555,12 @@ func NewModuleBuilder() ModuleBuilder {
 type moduleBuilder struct {
 	modules          map[Module]struct{}
 	providerBuilders map[ProviderBuilder]struct{}
+	private          bool
+	exports          []Criteria
+	inputs           []ModuleInput
+	conditions       allConditions
+	profiles         map[string]struct{}
+	loc              location.Location
 }
 
 func (mb *moduleBuilder) AddModule(m Module) ModuleBuilder {
@@ -280,6 +587,59 @@ func (mb *moduleBuilder) AddProvider(p ProviderBuilder) ModuleBuilder {
 	return mb
 }
 
+func (mb *moduleBuilder) SetPrivate(private bool) ModuleBuilder {
+	mb.private = private
+	return mb
+}
+
+func (mb *moduleBuilder) AddExports(criteriaList ...CriteriaBuilder) ModuleBuilder {
+	for _, cb := range criteriaList {
+		if cb == nil {
+			continue
+		}
+		mb.exports = append(mb.exports, cb.Criteria())
+	}
+	return mb
+}
+
+func (mb *moduleBuilder) AddInput(cri CriteriaBuilder, defaultProvider ProviderBuilder,
+	loc location.Location) ModuleBuilder {
+	if cri == nil {
+		return mb
+	}
+
+	input := &moduleInput{criteria: cri.Criteria(), loc: loc}
+	if defaultProvider != nil {
+		input.defaultVal = defaultProvider.Provider()
+	}
+	mb.inputs = append(mb.inputs, input)
+	return mb
+}
+
+func (mb *moduleBuilder) AddCondition(cond Condition) ModuleBuilder {
+	if cond == nil {
+		return mb
+	}
+
+	mb.conditions = append(mb.conditions, cond)
+	return mb
+}
+
+func (mb *moduleBuilder) DeclareProfiles(profiles ...string) ModuleBuilder {
+	if mb.profiles == nil {
+		mb.profiles = map[string]struct{}{}
+	}
+	for _, p := range profiles {
+		mb.profiles[p] = struct{}{}
+	}
+	return mb
+}
+
+func (mb *moduleBuilder) SetLocation(loc location.Location) ModuleBuilder {
+	mb.loc = loc
+	return mb
+}
+
 func (mb *moduleBuilder) Module() Module {
 	var modules []Module
 	for m := range mb.modules {
@@ -291,7 +651,23 @@ func (mb *moduleBuilder) Module() Module {
 		providers = append(providers, pb)
 	}
 
-	return newModule(modules, providers)
+	m := newModule(modules, providers)
+	// a module with exports is private
+	m.private = mb.private || len(mb.exports) > 0
+	m.exports = append([]Criteria{}, mb.exports...)
+	m.inputs = append([]ModuleInput{}, mb.inputs...)
+	m.conditions = append(allConditions{}, mb.conditions...)
+	m.profiles = map[string]struct{}{}
+	for p := range mb.profiles {
+		m.profiles[p] = struct{}{}
+	}
+	m.loc = mb.loc
+	if m.loc == nil {
+		m.loc = location.GetLocation(2)
+	}
+	inheritScopesOfBinds(m)
+
+	return m
 }
 
 type ModuleOption interface {
@@ -304,14 +680,116 @@ func (mo moduleOption) ApplyModule(builder ModuleBuilder) {
 	mo(builder)
 }
 
-func SubModule(subModule Module) ModuleOption {
+func SubModule(subModule Module, opts ...SubModuleOption) ModuleOption {
 	return moduleOption(func(builder ModuleBuilder) {
-		builder.AddModule(subModule)
+		if subModule == nil {
+			return
+		}
+
+		smb := &subModuleBuilder{module: subModule}
+		for _, o := range opts {
+			if o == nil {
+				continue
+			}
+			o.ApplySubModule(smb)
+		}
+		builder.AddModule(smb.Module())
 	})
 }
 
+type SubModuleBuilder interface {
+	SetNamespace(ns string) SubModuleBuilder
+	Module() Module
+}
+
+type subModuleBuilder struct {
+	module    Module
+	namespace string
+}
+
+func (b *subModuleBuilder) SetNamespace(ns string) SubModuleBuilder {
+	b.namespace = ns
+	return b
+}
+
+func (b *subModuleBuilder) Module() Module {
+	return WithNamespace(b.module, b.namespace)
+}
+
+type SubModuleOption interface {
+	ApplySubModule(SubModuleBuilder)
+}
+
 func Provide(p ProviderBuilder) ModuleOption {
 	return moduleOption(func(builder ModuleBuilder) {
 		builder.AddProvider(p)
 	})
 }
+
+func Private() ModuleOption {
+	return moduleOption(func(builder ModuleBuilder) {
+		builder.SetPrivate(true)
+	})
+}
+
+// Export makes the components match any of the criteria visible outside the module,
+// and makes the module private
+func Export(criteriaList ...CriteriaBuilder) ModuleOption {
+	return moduleOption(func(builder ModuleBuilder) {
+		builder.AddExports(criteriaList...)
+	})
+}
+
+// Require declares inputs of the module, components match the criteria must be provided
+// outside the module, otherwise Validate of the root module fails
+func Require(criteriaList ...CriteriaBuilder) ModuleOption {
+	loc := location.GetLocation(2)
+	return moduleOption(func(builder ModuleBuilder) {
+		for _, cri := range criteriaList {
+			builder.AddInput(cri, nil, loc)
+		}
+	})
+}
+
+// Default declares an optional input of the module, the provider is used
+// if no component matches the criteria outside the module
+func Default(cri CriteriaBuilder, defaultProvider ProviderBuilder) ModuleOption {
+	loc := location.GetLocation(2)
+	return moduleOption(func(builder ModuleBuilder) {
+		builder.AddInput(cri, defaultProvider, loc)
+	})
+}
+
+// When adds the providers and modules in opts as a sub module,
+// which is active only if cond is satisfied
+func When(cond Condition, opts ...ModuleOption) ModuleOption {
+	loc := location.GetLocation(2)
+	return moduleOption(func(builder ModuleBuilder) {
+		mb := &moduleBuilder{}
+		mb.SetLocation(loc)
+		mb.AddCondition(cond)
+		for _, o := range opts {
+			if o == nil {
+				continue
+			}
+			o.ApplyModule(mb)
+		}
+		builder.AddModule(mb.Module())
+	})
+}
+
+// Profile makes the module active only if any of the profiles is active, and declares them
+func Profile(profiles ...string) ModuleOption {
+	return moduleOption(func(builder ModuleBuilder) {
+		builder.AddCondition(OnProfile(profiles...))
+		builder.DeclareProfiles(profiles...)
+	})
+}
+
+// DeclareProfiles declares profiles which can be active, profiles referenced by Profile are
+// declared by it, others such as ones only referenced by OnProfile must be declared by this
+func DeclareProfiles(profiles ...string) ModuleOption {
+	return moduleOption(func(builder ModuleBuilder) {
+		builder.DeclareProfiles(profiles...)
+	})
+}
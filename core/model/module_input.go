@@ -0,0 +1,203 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+)
+
+// ModuleInput is a component the module needs from outside, such as its parent module
+type ModuleInput interface {
+	Criteria() Criteria
+	// Default is used if the input is not provided, the input is required if it is nil
+	Default() Provider
+	Location() location.Location
+}
+
+type moduleInput struct {
+	criteria   Criteria
+	fallbacks  []Criteria
+	defaultVal Provider
+	loc        location.Location
+}
+
+var _ ModuleInput = &moduleInput{}
+
+func (i *moduleInput) Criteria() Criteria {
+	return i.criteria
+}
+
+func (i *moduleInput) Fallbacks() []Criteria {
+	return i.fallbacks
+}
+
+func (i *moduleInput) Default() Provider {
+	return i.defaultVal
+}
+
+func (i *moduleInput) Location() location.Location {
+	return i.loc
+}
+
+func (i *moduleInput) Format(f fmt.State, r rune) {
+	if i.defaultVal == nil {
+		_, _ = fmt.Fprint(f, "Input")
+	} else {
+		_, _ = fmt.Fprint(f, "OptionalInput")
+	}
+	_, _ = fmt.Fprintf(f, "%v", i.criteria)
+	if i.loc != nil {
+		if f.Flag('+') && r == 'v' {
+			_, _ = fmt.Fprintf(f, " at %+v", i.loc)
+		} else {
+			_, _ = fmt.Fprintf(f, " at %v", i.loc)
+		}
+	}
+}
+
+func (i *moduleInput) criteriaList() []Criteria {
+	return append([]Criteria{i.criteria}, i.fallbacks...)
+}
+
+func (i *moduleInput) applyNamespace(ns *namespace) {
+	i.fallbacks = append([]Criteria{i.criteria}, i.fallbacks...)
+	i.criteria = ns.criteriaOf(i.criteria)
+	if i.defaultVal != nil {
+		i.defaultVal = namespaceProvider(i.defaultVal, ns)
+	}
+}
+
+func inputCriteriaList(input ModuleInput) []Criteria {
+	if mi, ok := input.(*moduleInput); ok {
+		return mi.criteriaList()
+	}
+	return []Criteria{input.Criteria()}
+}
+
+// inputProvided checks if there is a component outside the module m matching the input, in the root
+// module or in the outer repository
+func inputProvided(root Module, m Module, input ModuleInput, outer ComponentRepository) bool {
+	inner := newComponentSet()
+	m.AllComponents().Each(func(com Component) {
+		inner.Add(com)
+	})
+
+	for _, cri := range inputCriteriaList(input) {
+		found := false
+		root.AllComponents().Iterate(func(com Component) bool {
+			if !inner.Contains(com) && componentMatch(com, cri) {
+				found = true
+				return false
+			}
+			return true
+		})
+		if found {
+			return true
+		}
+		if outer != nil && len(outer.ComponentsMatch(cri).ToArray()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDefaults checks that default providers provide components matching their inputs
+func (m *module) validateDefaults() error {
+	errs := errors.Empty()
+	m.AllModules().Iterate(func(sm Module) bool {
+		for _, input := range sm.Inputs() {
+			def := input.Default()
+			if def == nil {
+				continue
+			}
+			found := false
+			def.Components().Iterate(func(com Component) bool {
+				if componentMatch(com, input.Criteria()) {
+					found = true
+					return false
+				}
+				return true
+			})
+			if !found {
+				errs = errs.AddErrorf("default %v of %v in %v provides no component match it",
+					def, input, sm)
+			}
+		}
+		return true
+	})
+
+	if errs.HasError() {
+		return errs.WithMainf("there are defaults of module inputs not valid")
+	}
+	return nil
+}
+
+// ValidateInputs checks that required inputs of modules in m are provided by components outside
+// them, in m or in outer. It is checked at the boundary of a container rather than by Validate,
+// so a reusable module requiring inputs can be validated alone.
+func ValidateInputs(m Module, outer ComponentRepository) error {
+	errs := errors.Empty()
+	m.AllModules().Iterate(func(sm Module) bool {
+		for _, input := range sm.Inputs() {
+			if input.Default() == nil && !inputProvided(m, sm, input, outer) {
+				errs = errs.AddErrorf("%v required by %v is not provided", input, sm)
+			}
+		}
+		return true
+	})
+
+	if errs.HasError() {
+		return errs.WithMainf("there are inputs of modules not provided")
+	}
+	return nil
+}
+
+// defaultProvidersOfInputs returns the default providers of inputs which are not provided, each
+// module has its own defaults, which are only visible in the module
+func defaultProvidersOfInputs(root Module, outer ComponentRepository) []Provider {
+	var providers []Provider
+	root.AllModules().Iterate(func(sm Module) bool {
+		var moduleDefaults []Provider
+		for _, input := range sm.Inputs() {
+			if input.Default() == nil || inputProvided(root, sm, input, outer) {
+				continue
+			}
+			if !providedByAny(moduleDefaults, input) {
+				moduleDefaults = append(moduleDefaults, input.Default())
+			}
+		}
+		providers = append(providers, moduleDefaults...)
+		return true
+	})
+	return providers
+}
+
+// defaultOwners returns modules using each default provider
+func defaultOwners(root Module) map[Provider][]Module {
+	owners := map[Provider][]Module{}
+	root.AllModules().Iterate(func(sm Module) bool {
+		for _, input := range sm.Inputs() {
+			if def := input.Default(); def != nil {
+				owners[def] = append(owners[def], sm)
+			}
+		}
+		return true
+	})
+	return owners
+}
+func providedByAny(providers []Provider, input ModuleInput) bool {
+	for _, p := range providers {
+		for _, cri := range inputCriteriaList(input) {
+			found := false
+			p.Components().Iterate(func(com Component) bool {
+				found = componentMatch(com, cri)
+				return !found
+			})
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
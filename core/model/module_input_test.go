@@ -0,0 +1,175 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	type config struct{ addr string }
+	type client struct{ addr string }
+
+	redisModule := NewModule(
+		Require(NewCriteria(&config{})),
+		Func(func(cfg *config) *client { return &client{cfg.addr} }),
+	)
+
+	t.Run("inputs", func(t *testing.T) {
+		inputs := redisModule.Inputs()
+		assert.Equal(t, 1, len(inputs))
+		assert.Equal(t, TypeOf(&config{}), inputs[0].Criteria().Type())
+		assert.Nil(t, inputs[0].Default())
+		assert.Contains(t, inputs[0].Location().FileName(), "module_input_test.go")
+	})
+
+	t.Run("validate alone", func(t *testing.T) {
+		assert.Nil(t, redisModule.Validate())
+		assert.NotNil(t, ValidateInputs(redisModule, nil))
+	})
+
+	t.Run("provided", func(t *testing.T) {
+		m := NewModule(
+			Value(&config{"localhost"}),
+			SubModule(redisModule),
+		)
+		assert.Nil(t, m.Validate())
+		assert.Nil(t, ValidateInputs(m, nil))
+	})
+
+	t.Run("provided by outer", func(t *testing.T) {
+		outer := NewRepository(NewModule(Value(&config{"localhost"})).AllComponents())
+		assert.Nil(t, ValidateInputs(NewModule(SubModule(redisModule)), outer))
+	})
+
+	t.Run("not provided", func(t *testing.T) {
+		m := NewModule(SubModule(redisModule))
+		assert.Nil(t, m.Validate())
+		err := ValidateInputs(m, nil)
+		assert.NotNil(t, err)
+		assert.Contains(t, fmt.Sprintf("%v", err), "is not provided")
+		assert.Contains(t, fmt.Sprintf("%v", err), "module_input_test.go")
+	})
+
+	t.Run("provided inside the module", func(t *testing.T) {
+		m := NewModule(
+			Require(NewCriteria(&config{})),
+			Value(&config{"localhost"}),
+		)
+		assert.NotNil(t, ValidateInputs(m, nil))
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		m := NewModule(
+			Value(&config{"replica"}, Name("replica")),
+			SubModule(redisModule, Namespace("replica")),
+		)
+		assert.Nil(t, ValidateInputs(m, nil))
+
+		m2 := NewModule(
+			Value(&config{"primary"}),
+			SubModule(redisModule, Namespace("replica")),
+		)
+		assert.Nil(t, ValidateInputs(m2, nil))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	type config struct{ addr string }
+	type client struct{ addr string }
+
+	redisModule := NewModule(
+		Default(NewCriteria(&config{}), Value(&config{"default"})),
+		Func(func(cfg *config) *client { return &client{cfg.addr} }),
+	)
+
+	t.Run("not provided", func(t *testing.T) {
+		m := NewModule(SubModule(redisModule))
+		assert.Nil(t, m.Validate())
+
+		am, err := m.Activate(nil)
+		assert.Nil(t, err)
+		assert.NotSame(t, m, am)
+		assert.Equal(t, 2, len(am.AllComponents().Filter(func(com Component) bool {
+			return com.Type() == TypeOf(&config{}) || com.Type() == TypeOf(&client{})
+		}).ToArray()))
+		assert.Nil(t, am.Validate())
+	})
+
+	t.Run("provided", func(t *testing.T) {
+		m := NewModule(
+			Value(&config{"localhost"}),
+			SubModule(redisModule),
+		)
+		am, err := m.Activate(nil)
+		assert.Nil(t, err)
+		assert.Same(t, m, am)
+	})
+
+	t.Run("provided by outer", func(t *testing.T) {
+		m := NewModule(SubModule(redisModule))
+		outer := NewRepository(NewModule(Value(&config{"localhost"})).AllComponents())
+		am, err := ActivateWithin(m, nil, outer)
+		assert.Nil(t, err)
+		assert.Same(t, m, am)
+	})
+
+	t.Run("default is only visible in the module", func(t *testing.T) {
+		type other struct{ addr string }
+		otherModule := NewModule(
+			Default(NewCriteria(&config{}), Value(&config{"other"})),
+			Func(func(cfg *config) *other { return &other{cfg.addr} }),
+		)
+		m := NewModule(
+			SubModule(redisModule),
+			SubModule(otherModule),
+			Func(func(cfg *config) int { return 0 }),
+		)
+		am, err := m.Activate(nil)
+		assert.Nil(t, err)
+		rep := NewRepositoryOfModule(am)
+
+		depOf := func(t reflect.Type) Dependency {
+			var dep Dependency
+			rep.AllComponents().Each(func(com Component) {
+				if com.Type() == t {
+					dep = dependencyIteratorToArray(com.Provider().Dependencies())[0]
+				}
+			})
+			return dep
+		}
+		coms := rep.ComponentsMatchDependency(depOf(TypeOf(&client{}))).ToArray()
+		assert.Equal(t, 1, len(coms))
+		assert.Same(t, redisModule.Inputs()[0].Default(), coms[0].Provider())
+		coms = rep.ComponentsMatchDependency(depOf(TypeOf(&other{}))).ToArray()
+		assert.Equal(t, 1, len(coms))
+		assert.Same(t, otherModule.Inputs()[0].Default(), coms[0].Provider())
+
+		dep := depOf(TypeOf(0))
+		assert.Equal(t, 0, len(rep.ComponentsMatchDependency(dep).ToArray()))
+		assert.Equal(t, 2, len(rep.BlockedComponentsOfDependency(dep)))
+	})
+
+	t.Run("default provides nothing match", func(t *testing.T) {
+		m := NewModule(
+			Default(NewCriteria(&config{}), Value(1)),
+		)
+		err := m.Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, fmt.Sprintf("%v", err), "provides no component match it")
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		m := NewModule(SubModule(redisModule, Namespace("replica")))
+		am, err := m.Activate(nil)
+		assert.Nil(t, err)
+		coms := am.AllComponents().Filter(func(com Component) bool {
+			return com.Type() == TypeOf(&config{})
+		}).ToArray()
+		assert.Equal(t, 1, len(coms))
+		assert.Equal(t, "replica", coms[0].Name())
+		assert.True(t, coms[0].Hidden())
+	})
+}
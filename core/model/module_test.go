@@ -760,3 +760,211 @@ func Test_newModule(t *testing.T) {
 		assert.True(t, meetFunc)
 	})
 }
+
+func TestModule_conditions(t *testing.T) {
+	t.Run("Profile", func(t *testing.T) {
+		m := NewModule(Profile("dev"), Value(1))
+		assert.Equal(t, allConditions{OnProfile("dev")}.Profiles(), m.Condition().Profiles())
+		assert.Nil(t, NewModule().Condition())
+	})
+
+	t.Run("DeclareProfiles", func(t *testing.T) {
+		m := NewModule(DeclareProfiles("prod", "dev"), DeclareProfiles("dev"))
+		assert.Equal(t, []string{"dev", "prod"}, m.DeclaredProfiles())
+	})
+
+	t.Run("Profile declares profiles", func(t *testing.T) {
+		m := NewModule(Profile("prod", "staging"), DeclareProfiles("dev"))
+		assert.Equal(t, []string{"dev", "prod", "staging"}, m.DeclaredProfiles())
+	})
+
+	t.Run("When", func(t *testing.T) {
+		m := NewModule(When(OnProfile("dev"), Value(1), nil))
+
+		var subModules []Module
+		m.SubModules().Iterate(func(sm Module) bool {
+			subModules = append(subModules, sm)
+			return true
+		})
+		assert.Equal(t, 1, len(subModules))
+		assert.Equal(t, []string{"dev"}, subModules[0].Condition().Profiles())
+		assert.Equal(t, 1, len(subModules[0].AllComponents().ToArray()))
+	})
+
+	t.Run("Location", func(t *testing.T) {
+		m := NewModule()
+		assert.Equal(t, "TestModule_conditions.func5", m.Location().FuncName())
+		assert.Contains(t, fmt.Sprintf("%v", m), "module_test.go")
+
+		m2 := NewModuleBuilder().Module()
+		assert.Equal(t, "TestModule_conditions.func5", m2.Location().FuncName())
+	})
+}
+
+func TestModule_Activate(t *testing.T) {
+	devModule := NewModule(Profile("dev"), Value(1, Name("db")))
+	prodModule := NewModule(Profile("prod"), Value(2, Name("db")))
+	common := NewModule(Value("abc"))
+
+	m := NewModule(
+		DeclareProfiles("dev", "prod"),
+		SubModule(devModule),
+		SubModule(prodModule),
+		SubModule(common),
+		When(OnSwitch(false), Value(3.0)),
+	)
+
+	activeValues := func(t *testing.T, m Module) []interface{} {
+		var values []interface{}
+		m.AllComponents().Each(func(com Component) {
+			values = append(values, com.Provider().(*valueProvider).value.Interface())
+		})
+		return values
+	}
+
+	t.Run("dev", func(t *testing.T) {
+		am, err := m.Activate(NewEnvironment("dev"))
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []interface{}{1, "abc"}, activeValues(t, am))
+		assert.Nil(t, am.Validate())
+	})
+
+	t.Run("prod", func(t *testing.T) {
+		am, err := m.Activate(NewEnvironment("prod"))
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []interface{}{2, "abc"}, activeValues(t, am))
+	})
+
+	t.Run("dev and prod", func(t *testing.T) {
+		am, err := m.Activate(NewEnvironment("prod", "dev"))
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []interface{}{1, 2, "abc"}, activeValues(t, am))
+		assert.NotNil(t, am.Validate())
+	})
+
+	t.Run("nil environment", func(t *testing.T) {
+		am, err := m.Activate(nil)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []interface{}{"abc"}, activeValues(t, am))
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := m.Activate(NewEnvironment("staging"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `"staging"`)
+	})
+
+	t.Run("module without conditions", func(t *testing.T) {
+		am, err := common.Activate(NewEnvironment())
+		assert.Nil(t, err)
+		assert.Same(t, common, am)
+	})
+
+	t.Run("profiles of sub modules are declared", func(t *testing.T) {
+		m2 := NewModule(
+			SubModule(NewModule(Profile("dev"), Value(1, Name("db")))),
+			SubModule(NewModule(Profile("prod"), Value(2, Name("db")))),
+		)
+		assert.Nil(t, m2.Validate())
+
+		for _, p := range []string{"dev", "prod"} {
+			am, err := m2.Activate(NewEnvironment(p))
+			assert.Nil(t, err)
+			assert.Nil(t, am.Validate())
+			assert.Equal(t, 1, len(am.AllComponents().ToArray()))
+		}
+
+		_, err := m2.Activate(NewEnvironment("staging"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `profile "staging" is not declared in any module`)
+	})
+
+	t.Run("root module is inactive", func(t *testing.T) {
+		m2 := NewModule(DeclareProfiles("dev"), Profile("dev"), Value(1))
+		am, err := m2.Activate(NewEnvironment())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(am.AllComponents().ToArray()))
+	})
+}
+
+func TestModule_Validate_conditions(t *testing.T) {
+	t.Run("unknown profile", func(t *testing.T) {
+		m := NewModule(
+			DeclareProfiles("dev", "prod"),
+			When(OnProfile("prdo"), Value(1)),
+		)
+		err := m.Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `unknown profile "prdo"`)
+	})
+
+	t.Run("declared in sub module", func(t *testing.T) {
+		m := NewModule(
+			SubModule(NewModule(DeclareProfiles("dev"))),
+			When(OnProfile("dev"), Value(1)),
+		)
+		assert.Nil(t, m.Validate())
+	})
+
+	t.Run("duplicate names in exclusive modules", func(t *testing.T) {
+		m := NewModule(
+			DeclareProfiles("dev", "prod"),
+			When(OnProfile("dev"), Value(1, Name("db"))),
+			When(OnProfile("prod"), Value(2, Name("db"))),
+		)
+		assert.Nil(t, m.Validate())
+	})
+
+	t.Run("duplicate names with unconditional module", func(t *testing.T) {
+		m := NewModule(
+			DeclareProfiles("dev"),
+			Value(0, Name("db")),
+			When(OnProfile("dev"), Value(1, Name("db"))),
+			When(OnProfile("dev"), Value(2, Name("other"))),
+		)
+		err := m.Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `duplicate name "db"`)
+	})
+
+	t.Run("duplicate names in nested module", func(t *testing.T) {
+		m := NewModule(
+			DeclareProfiles("dev"),
+			When(OnProfile("dev"),
+				Value(1, Name("db")),
+				When(OnSwitch(true), Value(2, Name("db"))),
+			),
+		)
+		assert.NotNil(t, m.Validate())
+	})
+}
+
+func TestModule_Private(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		m := NewModule()
+		assert.False(t, m.Private())
+		assert.Equal(t, 0, len(m.Exports()))
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		m := NewModule(Private())
+		assert.True(t, m.Private())
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		m := NewModule(Export(NewCriteria(0), nil), Value(1), Value("a", Name("a")))
+		assert.True(t, m.Private())
+		assert.Equal(t, 1, len(m.Exports()))
+
+		m.AllComponents().Each(func(com Component) {
+			assert.Equal(t, com.Type() == TypeOf(0), IsExported(m, com))
+		})
+	})
+
+	t.Run("export match nothing", func(t *testing.T) {
+		m := NewModule(Export(NewCriteria(0, ByName("abc"))), Value(1))
+		err := m.Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "there is no component match it")
+	})
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"fmt"
+	"sync"
+)
+
+type namespace struct {
+	name string
+}
+
+func (ns *namespace) nameOf(name string) string {
+	if name == "" {
+		return ns.name
+	}
+	return ns.name + "." + name
+}
+
+func (ns *namespace) tagOf(tag Symbol) Symbol {
+	return NamespacedSymbol(ns.name, tag)
+}
+
+func (ns *namespace) tagsOf(tags *symbolSet) *symbolSet {
+	if tags == nil {
+		return nil
+	}
+
+	set := newSymbolSet()
+	for _, tag := range tags.symbols() {
+		set.Add(ns.tagOf(tag))
+	}
+	return set
+}
+
+func (ns *namespace) criteriaOf(cri Criteria) Criteria {
+	c := &criteria{
+		rType: cri.Type(),
+		name:  ns.nameOf(cri.Name()),
+	}
+	cri.Tags().Iterate(func(tag Symbol) bool {
+		c.AddTags(ns.tagOf(tag))
+		return true
+	})
+	return c
+}
+
+type namespacedSymbolKey struct {
+	namespace string
+	symbol    Symbol
+}
+
+var namespacedSymbols = map[namespacedSymbolKey]Symbol{}
+var namespacedSymbolsMutex sync.Mutex
+
+// NamespacedSymbol returns the tag in the namespace, which is used to tag components of
+// the module instance with the namespace
+func NamespacedSymbol(ns string, tag Symbol) Symbol {
+	if tag == nil {
+		return nil
+	}
+
+	namespacedSymbolsMutex.Lock()
+	defer namespacedSymbolsMutex.Unlock()
+
+	key := namespacedSymbolKey{ns, tag}
+	if s, ok := namespacedSymbols[key]; ok {
+		return s
+	}
+
+	s := &symbol{name: fmt.Sprintf("%v.%v", ns, tag)}
+	s.value = s
+	if ts, ok := tag.(*symbol); ok {
+		s.name = ns + "." + ts.name
+		s.loc = ts.loc
+	}
+	namespacedSymbols[key] = s
+
+	return s
+}
+
+type namespaceable interface {
+	applyNamespace(ns *namespace)
+}
+
+// applyNamespace makes the component only can be matched by the namespaced name or tags
+func (c *component) applyNamespace(ns *namespace) {
+	c.name = ns.nameOf(c.name)
+	c.tags = ns.tagsOf(c.tags)
+	c.hidden = true
+}
+
+// applyNamespace looks for namespaced components first,
+// and the criteria before applying namespace is used as fallback
+func (d *dependency) applyNamespace(ns *namespace) {
+	origin := &criteria{
+		rType: d.Type(),
+		name:  d.name,
+		tags:  d.tags.clone(),
+	}
+	d.fallbacks = append([]Criteria{origin}, d.fallbacks...)
+
+	d.name = ns.nameOf(d.name)
+	d.tags = ns.tagsOf(d.tags)
+}
+
+func namespaceProvider(p Provider, ns *namespace) Provider {
+	pb, ok := p.(ProviderBuilder)
+	if !ok {
+		return p
+	}
+
+	cloned := pb.Provider()
+	cloned.Components().Each(func(com Component) {
+		if nc, ok := com.(namespaceable); ok {
+			nc.applyNamespace(ns)
+		}
+	})
+	cloned.Dependencies().Iterate(func(dep Dependency) bool {
+		if nd, ok := dep.(namespaceable); ok {
+			nd.applyNamespace(ns)
+		}
+		return true
+	})
+
+	return cloned
+}
+
+func namespaceModule(m Module, ns *namespace, namespaced map[Module]Module) Module {
+	if nm, ok := namespaced[m]; ok {
+		return nm
+	}
+
+	mm, ok := m.(*module)
+	if !ok {
+		return m
+	}
+
+	cloned := *mm
+	cloned.providers = providerSet{}
+	cloned.subModules = moduleSet{}
+	cloned.exports = nil
+	namespaced[m] = &cloned
+
+	for p := range mm.providers {
+		cloned.providers[namespaceProvider(p, ns)] = struct{}{}
+	}
+	for sm := range mm.subModules {
+		cloned.subModules[namespaceModule(sm, ns, namespaced)] = struct{}{}
+	}
+	for _, cri := range mm.exports {
+		cloned.exports = append(cloned.exports, ns.criteriaOf(cri))
+	}
+	cloned.inputs = nil
+	for _, input := range mm.inputs {
+		if mi, ok := input.(*moduleInput); ok {
+			ni := *mi
+			ni.applyNamespace(ns)
+			input = &ni
+		}
+		cloned.inputs = append(cloned.inputs, input)
+	}
+
+	return &cloned
+}
+
+// WithNamespace returns a new instance of the module, names and tags of all components
+// and dependencies are prefixed with the namespace. Components in the instance are
+// hidden, so they can only be matched by the namespaced names or tags, and the unnamed
+// components are named as the namespace.
+func WithNamespace(m Module, ns string) Module {
+	if m == nil || ns == "" {
+		return m
+	}
+
+	return namespaceModule(m, &namespace{name: ns}, map[Module]Module{})
+}
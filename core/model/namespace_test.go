@@ -0,0 +1,190 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacedSymbol(t *testing.T) {
+	tag1 := NewSymbol("tag1")
+	tag2 := NewSymbol("tag2")
+
+	s1 := NamespacedSymbol("ns", tag1)
+	assert.Same(t, s1, NamespacedSymbol("ns", tag1))
+	assert.NotSame(t, s1, NamespacedSymbol("ns2", tag1))
+	assert.NotSame(t, s1, NamespacedSymbol("ns", tag2))
+	assert.Equal(t, "ns.tag1", fmt.Sprintf("%v", s1))
+
+	assert.Nil(t, NamespacedSymbol("ns", nil))
+}
+
+func Test_namespace(t *testing.T) {
+	ns := &namespace{name: "replica"}
+	tag := NewSymbol("tag")
+
+	t.Run("nameOf", func(t *testing.T) {
+		assert.Equal(t, "replica", ns.nameOf(""))
+		assert.Equal(t, "replica.db", ns.nameOf("db"))
+	})
+
+	t.Run("tagsOf", func(t *testing.T) {
+		assert.Nil(t, ns.tagsOf(nil))
+		tags := ns.tagsOf(newSymbolSet(tag))
+		assert.True(t, tags.Has(NamespacedSymbol("replica", tag)))
+		assert.False(t, tags.Has(tag))
+	})
+
+	t.Run("criteriaOf", func(t *testing.T) {
+		cri := ns.criteriaOf(NewCriteria(0, ByName("a"), ByTags(tag)).Criteria())
+		assert.Equal(t, TypeOf(0), cri.Type())
+		assert.Equal(t, "replica.a", cri.Name())
+		assert.True(t, cri.Tags().Has(NamespacedSymbol("replica", tag)))
+	})
+}
+
+func TestWithNamespace(t *testing.T) {
+	type config struct{ dsn string }
+	type db struct{ dsn string }
+	tag := NewSymbol("tag")
+
+	dbModule := NewModule(
+		Export(NewCriteria(&db{})),
+		Func(func(cfg *config) *db { return &db{cfg.dsn} }),
+		Value(1, Name("size"), Tags(tag)),
+	)
+
+	t.Run("nil or empty namespace", func(t *testing.T) {
+		assert.Nil(t, WithNamespace(nil, "a"))
+		assert.Same(t, dbModule, WithNamespace(dbModule, ""))
+	})
+
+	m := WithNamespace(dbModule, "replica")
+
+	t.Run("components", func(t *testing.T) {
+		coms := m.AllComponents().ToArray()
+		assert.Equal(t, 2, len(coms))
+		for _, com := range coms {
+			assert.True(t, com.Hidden())
+			if com.Type() == TypeOf(&db{}) {
+				assert.Equal(t, "replica", com.Name())
+			} else {
+				assert.Equal(t, "replica.size", com.Name())
+				assert.True(t, com.Tags().Has(NamespacedSymbol("replica", tag)))
+			}
+		}
+
+		dbModule.AllComponents().Each(func(com Component) {
+			assert.False(t, com.Hidden())
+		})
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		m.AllProviders().Iterate(func(p Provider) bool {
+			p.Dependencies().Iterate(func(dep Dependency) bool {
+				assert.Equal(t, "replica", dep.Name())
+
+				fallbacks := dep.(withFallbacks).Fallbacks()
+				assert.Equal(t, 1, len(fallbacks))
+				assert.Equal(t, "", fallbacks[0].Name())
+				assert.Equal(t, TypeOf(&config{}), fallbacks[0].Type())
+				return true
+			})
+			return true
+		})
+	})
+
+	t.Run("exports", func(t *testing.T) {
+		assert.Equal(t, 1, len(m.Exports()))
+		assert.Equal(t, "replica", m.Exports()[0].Name())
+		assert.Nil(t, m.Validate())
+	})
+
+	t.Run("nested namespace", func(t *testing.T) {
+		m2 := WithNamespace(NewModule(SubModule(dbModule, Namespace("b"))), "a")
+		m2.AllProviders().Iterate(func(p Provider) bool {
+			p.Dependencies().Iterate(func(dep Dependency) bool {
+				assert.Equal(t, "a.b", dep.Name())
+				fallbacks := dep.(withFallbacks).Fallbacks()
+				assert.Equal(t, 2, len(fallbacks))
+				assert.Equal(t, "b", fallbacks[0].Name())
+				assert.Equal(t, "", fallbacks[1].Name())
+				return true
+			})
+			return true
+		})
+	})
+}
+
+func TestSubModule_Namespace(t *testing.T) {
+	type config struct{ dsn string }
+	type db struct{ dsn string }
+
+	dbModule := NewModule(
+		Func(func(cfg *config) *db { return &db{cfg.dsn} }),
+	)
+
+	m := NewModule(
+		Value(&config{"primary"}),
+		Value(&config{"replica"}, Name("replica"), Hide()),
+		SubModule(dbModule),
+		SubModule(dbModule, Namespace("replica"), nil),
+	)
+	assert.Nil(t, m.Validate())
+
+	rep := NewRepositoryOfModule(m)
+
+	dbOf := func(opts ...ValueConsumerOption) []Component {
+		vc := ValueConsumer(&db{}, opts...).Consumer()
+		var coms []Component
+		vc.Dependencies().Iterate(func(d Dependency) bool {
+			coms = rep.ComponentsMatchDependency(d).ToArray()
+			return true
+		})
+		return coms
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		coms := dbOf()
+		assert.Equal(t, 1, len(coms))
+		assert.Equal(t, "", coms[0].Name())
+	})
+
+	t.Run("replica", func(t *testing.T) {
+		coms := dbOf(ByName("replica"))
+		assert.Equal(t, 1, len(coms))
+		assert.Equal(t, "replica", coms[0].Name())
+
+		coms[0].Provider().Dependencies().Iterate(func(d Dependency) bool {
+			inputs := rep.ComponentsMatchDependency(d).ToArray()
+			assert.Equal(t, 1, len(inputs))
+			assert.Equal(t, "replica", inputs[0].Name())
+			return true
+		})
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		m2 := NewModule(
+			Value(&config{"primary"}),
+			SubModule(dbModule, Namespace("replica")),
+		)
+		rep2 := NewRepositoryOfModule(m2)
+		m2.AllComponents().Each(func(com Component) {
+			com.Provider().Dependencies().Iterate(func(d Dependency) bool {
+				inputs := rep2.ComponentsMatchDependency(d).ToArray()
+				assert.Equal(t, 1, len(inputs))
+				assert.Equal(t, "", inputs[0].Name())
+				return true
+			})
+		})
+	})
+
+	t.Run("duplicate namespace", func(t *testing.T) {
+		m2 := NewModule(
+			SubModule(dbModule, Namespace("replica")),
+			SubModule(dbModule, Namespace("replica")),
+		)
+		assert.NotNil(t, m2.Validate())
+	})
+}
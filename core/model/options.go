@@ -78,8 +78,15 @@ type LocationOption struct {
 	location.Location
 }
 
+// CaptureLocations enables or disables capturing locations of declarations and calls, which are
+// shown in errors. It is enabled by default, disabling it saves the cost of walking the stack, such
+// as in ValueOf and FuncOf in hot paths.
+func CaptureLocations(enabled bool) {
+	location.SetEnabled(enabled)
+}
+
 func UpdateCallLocation() UpdateCallLocationOption {
-	loc := location.GetCallLocation(3).Callee()
+	loc := location.GetLocation(3)
 	return UpdateCallLocationOption{loc}
 }
 
@@ -87,6 +94,13 @@ type UpdateCallLocationOption struct {
 	location.Location
 }
 
+// NoLocation stops capturing the location of the call creating the consumer
+func NoLocation() NoLocationOption {
+	return NoLocationOption{}
+}
+
+type NoLocationOption struct{}
+
 func InScope(scope Scope) ScopeOption {
 	return ScopeOption{scope}
 }
@@ -136,6 +150,16 @@ type ReturnOption struct {
 	opts  []ComponentOption
 }
 
+func Namespace(ns string) NamespaceOption {
+	return NamespaceOption(ns)
+}
+
+type NamespaceOption string
+
+func (o NamespaceOption) ApplySubModule(b SubModuleBuilder) {
+	b.SetNamespace(string(o))
+}
+
 type WithScopeOption struct {
 	scope Scope
 	pbs   []ProviderBuilder
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -153,6 +154,28 @@ func TestUpdateCallLocation(t *testing.T) {
 	assert.Equal(t, baseLoc.FileLine()+4, opt.FileLine())
 }
 
+func TestNoLocation(t *testing.T) {
+	assert.Nil(t, FuncConsumer(func() {}, NoLocation()).Consumer().Location())
+	assert.Nil(t, StructConsumer(struct{}{}, NoLocation()).Consumer().Location())
+	assert.Nil(t, ValueConsumer(0, NoLocation()).Consumer().Location())
+	assert.NotNil(t, ValueConsumer(0).Consumer().Location())
+
+	vc := ValueConsumer(0, NoLocation()).UpdateCallLocation(nil)
+	assert.Nil(t, vc.Consumer().Location())
+}
+
+func TestCaptureLocations(t *testing.T) {
+	CaptureLocations(false)
+	defer CaptureLocations(true)
+
+	p := Func(func() int { return 0 }).Provider()
+	assert.Nil(t, p.Location())
+	assert.Nil(t, ValueConsumer(0).Consumer().Location())
+	assert.Nil(t, UpdateCallLocation().Location)
+	assert.Equal(t, "scope", fmt.Sprintf("%+v", NewScope("scope")))
+	assert.Nil(t, NewModule(Func(func() int { return 0 })).Validate())
+}
+
 func TestName(t *testing.T) {
 	type args struct {
 		n string
@@ -5,11 +5,25 @@ type Provider interface {
 
 	Components() ComponentCollection
 	Scope() Scope
+	// CachePolicy returns the policy of caching values of the provider, if it has one
+	CachePolicy() (CachePolicy, bool)
 	Validate() error
 }
 
 type baseProvider struct {
 	//baseConsumer
+	cache *CachePolicy
+}
+
+func (p baseProvider) CachePolicy() (CachePolicy, bool) {
+	if p.cache == nil {
+		return CachePolicy{}, false
+	}
+	return *p.cache, true
+}
+
+func (p *baseProvider) setCachePolicy(policy CachePolicy) {
+	p.cache = &policy
 }
 
 type ProviderBuilder interface {
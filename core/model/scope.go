@@ -2,11 +2,18 @@ package model
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/internal/errors"
 	"github.com/jison/uni/internal/location"
 )
 
 type Scope interface {
+	// a scope used as an option of NewScope is a parent of the new scope
+	NewScopeOption
+
 	// Name a word to recognize the scope
 	Name() string
 	// ID makes sure every scope is unique
@@ -17,6 +24,13 @@ type Scope interface {
 	// other scope can enter directly into this scope,
 	// only if the other scope is the parent of this scope
 	CanEnterDirectlyFrom(other Scope) bool
+	Parents() []Scope
+	// Inputs are providers of components seeded when entering the scope
+	Inputs() []Provider
+	// Location where the scope is declared
+	Location() location.Location
+	// CachePolicy returns the policy of caching values of providers in the scope, if it has one
+	CachePolicy() (CachePolicy, bool)
 }
 
 type scope struct {
@@ -24,6 +38,10 @@ type scope struct {
 	id      *scope
 	loc     location.Location
 	parents map[Scope]struct{}
+	// parents in the order of declaration
+	parentList []Scope
+	inputs     []Provider
+	cache      *CachePolicy
 }
 
 func (s *scope) Name() string {
@@ -61,8 +79,31 @@ func (s *scope) CanEnterDirectlyFrom(other Scope) bool {
 	return ok
 }
 
+func (s *scope) Parents() []Scope {
+	return append([]Scope(nil), s.parentList...)
+}
+
+func (s *scope) Inputs() []Provider {
+	return s.inputs
+}
+
+func (s *scope) Location() location.Location {
+	return s.loc
+}
+
+func (s *scope) CachePolicy() (CachePolicy, bool) {
+	if s.cache == nil {
+		return CachePolicy{}, false
+	}
+	return *s.cache, true
+}
+
+func (s *scope) ApplyNewScope(b ScopeBuilder) {
+	b.AddParent(s)
+}
+
 func (s *scope) Format(f fmt.State, c rune) {
-	if f.Flag('+') && c == 'v' {
+	if f.Flag('+') && c == 'v' && s.loc != nil {
 		_, _ = fmt.Fprintf(f, "%v.%v", s.loc.FullName(), s.Name())
 	} else {
 		_, _ = fmt.Fprint(f, s.Name())
@@ -89,25 +130,312 @@ func (g *globalScope) CanEnterDirectlyFrom(_ Scope) bool {
 	return false
 }
 
+func (g *globalScope) Parents() []Scope {
+	return nil
+}
+
+func (g *globalScope) Inputs() []Provider {
+	return nil
+}
+
+func (g *globalScope) Location() location.Location {
+	return nil
+}
+
+func (g *globalScope) CachePolicy() (CachePolicy, bool) {
+	return CachePolicy{}, false
+}
+
+func (g *globalScope) ApplyNewScope(b ScopeBuilder) {
+	b.AddParent(g)
+}
+
 func (g *globalScope) Format(f fmt.State, _ rune) {
 	_, _ = fmt.Fprint(f, g.Name())
 }
 
+// ScopePaths returns all paths to enter the scope `to` from the scope `from`,
+// each path contains the scopes to enter in order, `from` is excluded
+func ScopePaths(from Scope, to Scope) [][]Scope {
+	if from == nil || to == nil || from == to {
+		return nil
+	}
+
+	var paths [][]Scope
+	for _, p := range to.Parents() {
+		if p == from {
+			paths = append(paths, []Scope{to})
+			continue
+		}
+		if !p.CanEnterFrom(from) {
+			continue
+		}
+		for _, path := range ScopePaths(from, p) {
+			paths = append(paths, append(path, to))
+		}
+	}
+	return paths
+}
+
+// ScopesWithAncestors returns the scopes and all their ancestors, sorted by name and location,
+// the global scope is always the first one
+func ScopesWithAncestors(scopes ...Scope) []Scope {
+	visited := map[Scope]struct{}{GlobalScope: {}}
+	var visit func(s Scope)
+	visit = func(s Scope) {
+		if _, ok := visited[s]; ok || s == nil {
+			return
+		}
+		visited[s] = struct{}{}
+		for _, p := range s.Parents() {
+			visit(p)
+		}
+	}
+	for _, s := range scopes {
+		visit(s)
+	}
+
+	var res []Scope
+	for s := range visited {
+		if s != GlobalScope {
+			res = append(res, s)
+		}
+	}
+	sortScopes(res)
+	return append([]Scope{GlobalScope}, res...)
+}
+
+func sortScopes(scopes []Scope) {
+	sort.SliceStable(scopes, func(i, j int) bool {
+		if scopes[i].Name() != scopes[j].Name() {
+			return scopes[i].Name() < scopes[j].Name()
+		}
+		return fmt.Sprintf("%v", scopes[i].Location()) < fmt.Sprintf("%v", scopes[j].Location())
+	})
+}
+
+// FormatScopeHierarchy formats the scopes and their ancestors as a tree from the global scope,
+// a scope with multiple parents is shown under each parent, and its children are only shown
+// under the first one
+func FormatScopeHierarchy(scopes ...Scope) string {
+	all := ScopesWithAncestors(scopes...)
+	children := map[Scope][]Scope{}
+	for _, s := range all {
+		for _, p := range s.Parents() {
+			children[p] = append(children[p], s)
+		}
+	}
+	for _, cs := range children {
+		sortScopes(cs)
+	}
+
+	sb := &strings.Builder{}
+	printed := map[Scope]struct{}{}
+	var write func(s Scope, depth int)
+	write = func(s Scope, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString(s.Name())
+		if loc := s.Location(); loc != nil {
+			_, _ = fmt.Fprintf(sb, " at %v", loc)
+		}
+		if parents := s.Parents(); len(parents) > 1 {
+			sortScopes(parents)
+			_, _ = fmt.Fprintf(sb, " (parents: %v)", parents)
+		}
+		for _, input := range s.Inputs() {
+			input.Components().Each(func(com Component) {
+				if com.Name() != "" {
+					_, _ = fmt.Fprintf(sb, " [input %v name=%q]", com.Type(), com.Name())
+				} else {
+					_, _ = fmt.Fprintf(sb, " [input %v]", com.Type())
+				}
+			})
+		}
+
+		if _, ok := printed[s]; ok {
+			if len(children[s]) > 0 {
+				sb.WriteString(" ...")
+			}
+			sb.WriteString("\n")
+			return
+		}
+		printed[s] = struct{}{}
+		sb.WriteString("\n")
+
+		for _, c := range children[s] {
+			write(c, depth+1)
+		}
+	}
+	write(GlobalScope, 0)
+
+	return sb.String()
+}
+
+type ScopeBuilder interface {
+	AddParent(parent Scope) ScopeBuilder
+	AddInput(t TypeVal, loc location.Location, opts ...ComponentOption) ScopeBuilder
+	SetCachePolicy(policy CachePolicy) ScopeBuilder
+}
+
+type NewScopeOption interface {
+	ApplyNewScope(b ScopeBuilder)
+}
+
+type newScopeOption func(b ScopeBuilder)
+
+func (o newScopeOption) ApplyNewScope(b ScopeBuilder) {
+	o(b)
+}
+
+// scopeBuilder builds the scope in NewScopeWith, it does nothing after the scope is created,
+// so scopes can not be changed once they are shared
+type scopeBuilder struct {
+	s *scope
+}
+
+func (b *scopeBuilder) AddParent(parent Scope) ScopeBuilder {
+	s := b.s
+	if s == nil || parent == nil {
+		return b
+	}
+	if _, ok := s.parents[parent]; !ok {
+		s.parents[parent] = struct{}{}
+		s.parentList = append(s.parentList, parent)
+	}
+	return b
+}
+
+func (b *scopeBuilder) AddInput(t TypeVal, loc location.Location, opts ...ComponentOption) ScopeBuilder {
+	if b.s != nil {
+		b.s.inputs = append(b.s.inputs, scopeInputProviderOf(b.s, t, loc, opts...))
+	}
+	return b
+}
+
+func (b *scopeBuilder) SetCachePolicy(policy CachePolicy) ScopeBuilder {
+	if b.s != nil {
+		b.s.cache = &policy
+	}
+	return b
+}
+
+// NewScope creates a scope which can be entered directly from the parents,
+// or from the global scope if there is no parent
 func NewScope(name string, parents ...Scope) Scope {
+	opts := make([]NewScopeOption, 0, len(parents))
+	for _, p := range parents {
+		if p != nil {
+			opts = append(opts, p)
+		}
+	}
+	return newScope(name, location.GetCallLocation(1), opts)
+}
+
+// NewScopeWith creates a scope with options, such as parents, inputs and the cache policy,
+// it can be entered directly from the parents, or from the global scope if there is no parent
+func NewScopeWith(name string, opts ...NewScopeOption) Scope {
+	return newScope(name, location.GetCallLocation(1), opts)
+}
+
+func newScope(name string, loc location.Location, opts []NewScopeOption) Scope {
 	s := scope{
 		name: name,
 	}
 	s.id = &s // prevent the scope clean by gc
-	s.loc = location.GetCallLocation(1)
+	s.loc = loc
 	s.parents = map[Scope]struct{}{}
 
-	if len(parents) > 0 {
-		for _, p := range parents {
-			s.parents[p] = struct{}{}
+	b := &scopeBuilder{s: s.id}
+	for _, o := range opts {
+		if o == nil {
+			continue
 		}
-	} else {
-		s.parents[GlobalScope] = struct{}{}
+		o.ApplyNewScope(b)
 	}
+	if len(s.parents) == 0 {
+		b.AddParent(GlobalScope)
+	}
+	b.s = nil
 
 	return s.id
 }
+
+// ScopeInput declares a component which is seeded when entering the scope
+func ScopeInput(t TypeVal, opts ...ComponentOption) NewScopeOption {
+	loc := location.GetLocation(2)
+	return newScopeOption(func(b ScopeBuilder) {
+		b.AddInput(t, loc, opts...)
+	})
+}
+
+// scopeInputProvider provides the component seeded when entering the scope,
+// its value is stored in the storage of the scope directly
+type scopeInputProvider struct {
+	*baseConsumer
+	baseProvider
+	com *component
+}
+
+var _ Provider = &scopeInputProvider{}
+
+func scopeInputProviderOf(s Scope, t TypeVal, loc location.Location,
+	opts ...ComponentOption) *scopeInputProvider {
+	p := &scopeInputProvider{
+		baseConsumer: &baseConsumer{
+			scope: s,
+			loc:   loc,
+		},
+		com: &component{
+			val:   valuer.Identity(),
+			rType: TypeOf(t),
+		},
+	}
+	p.com.provider = p
+
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		o.ApplyComponent(p.com)
+	}
+
+	p.val = valuer.Error(errors.Newf("%v is not seeded when entering scope `%v`", p, s))
+	return p
+}
+
+func (p *scopeInputProvider) Dependencies() DependencyIterator {
+	return emptyDependencyIterator{}
+}
+
+func (p *scopeInputProvider) Components() ComponentCollection {
+	return ComponentsOfIterator(p.com)
+}
+
+// IsScopeInputProvider returns whether the provider provides a component seeded when entering its scope
+func IsScopeInputProvider(p Provider) bool {
+	sp, ok := p.(*scopeInputProvider)
+	return ok && sp != nil
+}
+
+func (p *scopeInputProvider) Validate() error {
+	return p.com.Validate()
+}
+
+func (p *scopeInputProvider) Format(f fmt.State, r rune) {
+	_, _ = fmt.Fprintf(f, "ScopeInput[%v]", p.com.Type())
+	if p.com.Name() != "" {
+		_, _ = fmt.Fprintf(f, "{name=%q}", p.com.Name())
+	}
+
+	if f.Flag('+') && r == 'v' {
+		_, _ = fmt.Fprintf(f, " in %v at %v", p.Scope(), p.Location())
+	}
+}
+
+func (p *scopeInputProvider) Equal(other interface{}) bool {
+	o, ok := other.(*scopeInputProvider)
+	if !ok {
+		return false
+	}
+	return p == o
+}
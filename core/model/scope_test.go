@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,74 @@ func TestNewScope(t *testing.T) {
 		assert.True(t, s2.CanEnterFrom(s1))
 		assert.False(t, s1.CanEnterFrom(s2))
 	})
+
+	t.Run("parents in a slice", func(t *testing.T) {
+		parents := []Scope{NewScope("scope1"), NewScope("scope2")}
+		s := NewScope("scope3", parents...)
+		assert.Equal(t, parents, s.Parents())
+	})
+
+	t.Run("can not be changed after created", func(t *testing.T) {
+		var builder ScopeBuilder
+		s := NewScopeWith("scope1", newScopeOption(func(b ScopeBuilder) {
+			builder = b
+		}))
+		_, ok := s.(ScopeBuilder)
+		assert.False(t, ok)
+
+		builder.AddParent(NewScope("scope2")).AddInput(0, nil).SetCachePolicy(CachePolicy{MaxEntries: 1})
+		assert.Equal(t, []Scope{GlobalScope}, s.Parents())
+		assert.Empty(t, s.Inputs())
+		_, hasPolicy := s.CachePolicy()
+		assert.False(t, hasPolicy)
+	})
+}
+
+func TestScopeInput(t *testing.T) {
+	type request struct{}
+	tag := NewSymbol("tag")
+	parent := NewScope("parent")
+	s := NewScopeWith("request",
+		parent,
+		ScopeInput(&request{}),
+		ScopeInput("", Name("userID"), Tags(tag)),
+		nil,
+	)
+
+	assert.Equal(t, []Scope{parent}, s.Parents())
+	assert.True(t, s.CanEnterDirectlyFrom(parent))
+	assert.False(t, s.CanEnterDirectlyFrom(GlobalScope))
+
+	inputs := s.Inputs()
+	assert.Equal(t, 2, len(inputs))
+	for _, input := range inputs {
+		assert.Nil(t, input.Validate())
+		assert.Equal(t, s, input.Scope())
+		assert.Equal(t, 0, len(dependencyIteratorToArray(input.Dependencies())))
+		assert.Contains(t, input.Location().FileName(), "scope_test.go")
+		assert.True(t, input.Equal(input))
+	}
+
+	com1 := inputs[0].Components().ToArray()[0]
+	assert.Equal(t, TypeOf(&request{}), com1.Type())
+	assert.Same(t, inputs[0], com1.Provider())
+
+	com2 := inputs[1].Components().ToArray()[0]
+	assert.Equal(t, "userID", com2.Name())
+	assert.True(t, com2.Tags().Has(tag))
+	assert.Equal(t, `ScopeInput[string]{name="userID"}`, fmt.Sprintf("%v", inputs[1]))
+
+	_, isErr := inputs[0].Valuer().Value(nil).AsError()
+	assert.True(t, isErr)
+
+	t.Run("repository", func(t *testing.T) {
+		rep := NewRepository(Value(1, InScope(s)).Provider().Components())
+		assert.True(t, rep.AllComponents().ToSet().Contains(com1))
+		assert.True(t, rep.AllComponents().ToSet().Contains(com2))
+
+		rep2 := NewRepository(Value(1).Provider().Components())
+		assert.False(t, rep2.AllComponents().ToSet().Contains(com1))
+	})
 }
 
 func TestScope(t *testing.T) {
@@ -67,6 +136,54 @@ func TestScope_canEnterFrom(t *testing.T) {
 	})
 }
 
+func TestScopePaths(t *testing.T) {
+	s1 := NewScope("scope1")
+	s2 := NewScope("scope2", s1)
+	s3 := NewScope("scope3", s2)
+	s4 := NewScope("scope4", s1)
+	s5 := NewScope("scope5", s3, s4)
+
+	assert.Equal(t, [][]Scope{{s1, s2, s3}}, ScopePaths(GlobalScope, s3))
+	assert.Equal(t, [][]Scope{{s3}}, ScopePaths(s2, s3))
+	assert.Equal(t, [][]Scope{{s2, s3, s5}, {s4, s5}}, ScopePaths(s1, s5))
+	assert.Equal(t, [][]Scope{{s5}}, ScopePaths(s4, s5))
+	assert.Nil(t, ScopePaths(s3, s1))
+	assert.Nil(t, ScopePaths(s3, s3))
+	assert.Nil(t, ScopePaths(nil, s3))
+}
+
+func TestScopesWithAncestors(t *testing.T) {
+	s1 := NewScope("scope1")
+	s2 := NewScope("scope2", s1)
+	s3 := NewScope("scope3", s2)
+	s4 := NewScope("a", s1)
+
+	assert.Equal(t, []Scope{GlobalScope, s4, s1, s2, s3}, ScopesWithAncestors(s3, s4, nil))
+	assert.Equal(t, []Scope{GlobalScope, s1}, ScopesWithAncestors(s1, GlobalScope))
+	assert.Equal(t, []Scope{GlobalScope}, ScopesWithAncestors())
+}
+
+func TestFormatScopeHierarchy(t *testing.T) {
+	s1 := NewScope("scope1")
+	s2 := NewScopeWith("scope2", s1, ScopeInput(""), ScopeInput(0, Name("id")))
+	s3 := NewScope("scope3", s1)
+	s4 := NewScope("scope4", s2, s3)
+	s5 := NewScope("scope5", s4)
+
+	str := FormatScopeHierarchy(s5)
+	lines := strings.Split(strings.TrimSpace(str), "\n")
+	assert.Equal(t, 7, len(lines))
+	assert.Equal(t, "Global", lines[0])
+	assert.Equal(t, fmt.Sprintf("  scope1 at %v", s1.Location()), lines[1])
+	assert.Equal(t, fmt.Sprintf("    scope2 at %v [input string] [input int name=\"id\"]", s2.Location()),
+		lines[2])
+	assert.Equal(t, fmt.Sprintf("      scope4 at %v (parents: [scope2 scope3])", s4.Location()), lines[3])
+	assert.Equal(t, fmt.Sprintf("        scope5 at %v", s5.Location()), lines[4])
+	assert.Equal(t, fmt.Sprintf("    scope3 at %v", s3.Location()), lines[5])
+	assert.Equal(t, fmt.Sprintf("      scope4 at %v (parents: [scope2 scope3]) ...", s4.Location()), lines[6])
+	assert.Contains(t, lines[1], "scope_test.go")
+}
+
 func TestScope_canEnterDirectlyFrom(t *testing.T) {
 	t.Run("CanEnterDirectlyFrom", func(t *testing.T) {
 		s1 := NewScope("scope1")
@@ -126,4 +243,18 @@ func TestGlobalScope(t *testing.T) {
 	t.Run("Format", func(t *testing.T) {
 		assert.Equal(t, "Global", fmt.Sprintf("%v", GlobalScope))
 	})
+
+	t.Run("Parents and Inputs", func(t *testing.T) {
+		assert.Nil(t, GlobalScope.Parents())
+		assert.Nil(t, GlobalScope.Inputs())
+
+		s := NewScope("scope1", GlobalScope)
+		assert.Equal(t, []Scope{GlobalScope}, s.Parents())
+	})
+}
+
+func TestIsScopeInputProvider(t *testing.T) {
+	scope1 := NewScopeWith("scope1", ScopeInput(0))
+	assert.True(t, IsScopeInputProvider(scope1.Inputs()[0]))
+	assert.False(t, IsScopeInputProvider(Value(1).Provider()))
 }
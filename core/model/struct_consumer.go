@@ -66,6 +66,15 @@ func (sf *structField) clone() *structField {
 	}
 }
 
+// FieldOfDependency returns the field of struct injected by the dependency, if it is a field
+func FieldOfDependency(dep Dependency) (reflect.StructField, bool) {
+	sf, ok := dep.(*structField)
+	if !ok || sf == nil {
+		return reflect.StructField{}, false
+	}
+	return sf.field, true
+}
+
 type fieldByName map[string]*structField
 
 func (m fieldByName) Iterate(f func(Dependency) bool) bool {
@@ -271,9 +280,9 @@ func (sc *structConsumer) SetLocation(loc location.Location) StructConsumerBuild
 }
 
 func (sc *structConsumer) UpdateCallLocation(loc location.Location) StructConsumerBuilder {
-	if sc.Location() == nil {
+	if sc.Location() == nil && !sc.noLocation {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		sc.SetLocation(loc)
 	}
@@ -352,3 +361,9 @@ func (o LocationOption) ApplyStructConsumer(b StructConsumerBuilder) {
 func (o UpdateCallLocationOption) ApplyStructConsumer(b StructConsumerBuilder) {
 	b.UpdateCallLocation(o.Location)
 }
+
+func (o NoLocationOption) ApplyStructConsumer(b StructConsumerBuilder) {
+	if c, ok := b.(*structConsumer); ok {
+		c.noLocation = true
+	}
+}
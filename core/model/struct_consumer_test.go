@@ -69,6 +69,24 @@ func Test_structField_clone(t *testing.T) {
 	})
 }
 
+func TestFieldOfDependency(t *testing.T) {
+	type testStruct struct {
+		A int
+	}
+	StructConsumer(testStruct{}).Consumer().Dependencies().Iterate(func(dep Dependency) bool {
+		field, ok := FieldOfDependency(dep)
+		assert.True(t, ok)
+		assert.Equal(t, "A", field.Name)
+		return true
+	})
+
+	FuncConsumer(func(a int) {}).Consumer().Dependencies().Iterate(func(dep Dependency) bool {
+		_, ok := FieldOfDependency(dep)
+		assert.False(t, ok)
+		return true
+	})
+}
+
 func Test_structField_Equal(t *testing.T) {
 	//lint:ignore U1000 we need the field name to locate the field
 	type testStruct struct {
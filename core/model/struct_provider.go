@@ -25,6 +25,16 @@ func (sp *structProvider) Components() ComponentCollection {
 	return ComponentsOfIterator(sp.com)
 }
 
+// StructTypeOfProvider returns the type of struct or pointer of struct created by the provider,
+// if it is a struct provider
+func StructTypeOfProvider(p Provider) (reflect.Type, bool) {
+	sp, ok := p.(*structProvider)
+	if !ok || sp == nil {
+		return nil, false
+	}
+	return sp.sType, true
+}
+
 func (sp *structProvider) Validate() error {
 	errs := errors.Empty()
 
@@ -123,6 +133,7 @@ type StructProviderBuilder interface {
 	AddTags(tags ...Symbol) StructProviderBuilder
 
 	SetScope(scope Scope) StructProviderBuilder
+	SetCachePolicy(policy CachePolicy) StructProviderBuilder
 	SetLocation(loc location.Location) StructProviderBuilder
 	UpdateCallLocation(loc location.Location) StructProviderBuilder
 }
@@ -160,6 +171,10 @@ func (sp *structProvider) AddAs(ifs ...TypeVal) StructProviderBuilder {
 	return sp
 }
 
+func (sp *structProvider) addTypeConstraint(tc typeConstraint) {
+	sp.com.addTypeConstraint(tc)
+}
+
 func (sp *structProvider) SetName(name string) StructProviderBuilder {
 	sp.com.SetName(name)
 	return sp
@@ -170,6 +185,11 @@ func (sp *structProvider) AddTags(tags ...Symbol) StructProviderBuilder {
 	return sp
 }
 
+func (sp *structProvider) SetCachePolicy(policy CachePolicy) StructProviderBuilder {
+	sp.setCachePolicy(policy)
+	return sp
+}
+
 func (sp *structProvider) SetScope(scope Scope) StructProviderBuilder {
 	sp.baseConsumer.SetScope(scope)
 	return sp
@@ -183,7 +203,7 @@ func (sp *structProvider) SetLocation(loc location.Location) StructProviderBuild
 func (sp *structProvider) UpdateCallLocation(loc location.Location) StructProviderBuilder {
 	if sp.Location() == nil {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		sp.SetLocation(loc)
 	}
@@ -779,3 +779,13 @@ func Test_structProvider_Equal(t *testing.T) {
 	})
 
 }
+
+func TestStructTypeOfProvider(t *testing.T) {
+	type testStruct struct{}
+	st, ok := StructTypeOfProvider(Struct(&testStruct{}).Provider())
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(&testStruct{}), st)
+
+	_, ok = StructTypeOfProvider(Value(1).Provider())
+	assert.False(t, ok)
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+)
+
+// TypedSymbol is a symbol which can only tag components and dependencies of its type
+type TypedSymbol interface {
+	Symbol
+	SymbolType() reflect.Type
+}
+
+type typedSymbol struct {
+	*symbol
+	rType reflect.Type
+}
+
+func (s *typedSymbol) SymbolType() reflect.Type {
+	return s.rType
+}
+
+// NewTypedSymbol creates a symbol of the type, the location of the caller is used if loc is nil
+func NewTypedSymbol(name string, t TypeVal, loc location.Location) TypedSymbol {
+	s := &symbol{name: name}
+	s.value = s
+	if loc == nil {
+		loc = location.GetCallLocation(1)
+	}
+	s.loc = loc
+
+	return &typedSymbol{symbol: s, rType: TypeOf(t)}
+}
+
+// typeConstraint requires the component or the dependency to be of the type
+type typeConstraint struct {
+	desc  string
+	rType reflect.Type
+}
+
+type typeConstraints []typeConstraint
+
+func (cs typeConstraints) clone() typeConstraints {
+	return append(typeConstraints(nil), cs...)
+}
+
+// check returns an error if the type is not in the types of constraints and tags
+func (cs typeConstraints) check(types func(reflect.Type) bool, tags SymbolSet) error {
+	errs := errors.Empty()
+	for _, c := range cs {
+		if !types(c.rType) {
+			errs = errs.AddErrorf("%v of [%v] can not be applied", c.desc, c.rType)
+		}
+	}
+	tags.Iterate(func(s Symbol) bool {
+		if ts, ok := s.(TypedSymbol); ok && !types(ts.SymbolType()) {
+			errs = errs.AddErrorf("tag %v of [%v] can not be applied", s, ts.SymbolType())
+		}
+		return true
+	})
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+type typeConstrainedBuilder interface {
+	addTypeConstraint(c typeConstraint)
+}
+
+func addTypeConstraint(b interface{}, c typeConstraint) {
+	if cb, ok := b.(typeConstrainedBuilder); ok {
+		cb.addTypeConstraint(c)
+	}
+}
+
+// TypedNameOption names components or dependencies, which must be of its type
+type TypedNameOption struct {
+	name  string
+	rType reflect.Type
+}
+
+func TypedName(name string, t TypeVal) TypedNameOption {
+	return TypedNameOption{name: name, rType: TypeOf(t)}
+}
+
+func (o TypedNameOption) constraint() typeConstraint {
+	return typeConstraint{desc: fmt.Sprintf("name %q", o.name), rType: o.rType}
+}
+
+func (o TypedNameOption) ApplyComponent(b ComponentBuilder) {
+	b.SetName(o.name)
+	addTypeConstraint(b, o.constraint())
+}
+
+func (o TypedNameOption) ApplyValueProvider(b ValueProviderBuilder) {
+	b.SetName(o.name)
+	addTypeConstraint(b, o.constraint())
+}
+
+func (o TypedNameOption) ApplyStructProvider(b StructProviderBuilder) {
+	b.SetName(o.name)
+	addTypeConstraint(b, o.constraint())
+}
+
+func (o TypedNameOption) ApplyDependency(b DependencyBuilder) {
+	b.SetName(o.name)
+	addTypeConstraint(b, o.constraint())
+}
+
+func (o TypedNameOption) ApplyValueConsumer(b ValueConsumerBuilder) {
+	b.SetName(o.name)
+	addTypeConstraint(b, o.constraint())
+}
+
+func (o TypedNameOption) ApplyCriteria(b CriteriaBuilder) {
+	b.SetName(o.name)
+}
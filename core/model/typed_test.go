@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedTestInterface interface {
+	Foo()
+}
+
+type typedTestStruct struct{}
+
+func (s *typedTestStruct) Foo() {}
+
+func TestNewTypedSymbol(t *testing.T) {
+	s := NewTypedSymbol("handler", reflect.TypeOf(0), nil)
+	assert.Equal(t, reflect.TypeOf(0), s.SymbolType())
+	assert.Equal(t, "handler", fmt.Sprintf("%v", s))
+	assert.Contains(t, fmt.Sprintf("%+v", s), "TestNewTypedSymbol.handler")
+	assert.NotEqual(t, s, NewTypedSymbol("handler", reflect.TypeOf(0), nil))
+}
+
+func TestTypedSymbol_Validate(t *testing.T) {
+	intTag := NewTypedSymbol("int", 0, nil)
+	ifaceTag := NewTypedSymbol("iface", TypeOf((*typedTestInterface)(nil)), nil)
+
+	t.Run("component", func(t *testing.T) {
+		assert.Nil(t, Value(1, Tags(intTag)).Provider().Validate())
+		assert.Nil(t, Value(&typedTestStruct{}, As((*typedTestInterface)(nil)), Tags(ifaceTag)).
+			Provider().Validate())
+
+		err := Value("abc", Tags(intTag)).Provider().Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "tag int of [int] can not be applied")
+		assert.NotNil(t, Value(&typedTestStruct{}, Tags(ifaceTag)).Provider().Validate())
+	})
+
+	t.Run("dependency", func(t *testing.T) {
+		assert.Nil(t, ValueConsumer(0, ByTags(intTag)).Consumer().Validate())
+		assert.Nil(t, ValueConsumer([]int{}, AsCollector(true), ByTags(intTag)).Consumer().Validate())
+		assert.NotNil(t, ValueConsumer("", ByTags(intTag)).Consumer().Validate())
+		assert.NotNil(t, Func(func(s string) int { return 0 }, Param(0, ByTags(intTag))).Provider().Validate())
+	})
+}
+
+func TestTypedName(t *testing.T) {
+	name := TypedName("db", 0)
+
+	t.Run("component", func(t *testing.T) {
+		p := Value(1, name).Provider()
+		assert.Nil(t, p.Validate())
+		assert.Equal(t, "db", p.Components().ToArray()[0].Name())
+		assert.Nil(t, Func(func() int { return 1 }, Return(0, name)).Provider().Validate())
+		assert.Nil(t, Struct(typedTestStruct{}, TypedName("s", typedTestStruct{})).Provider().Validate())
+
+		err := Value("abc", name).Provider().Validate()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `name "db" of [int] can not be applied`)
+		assert.NotNil(t, Struct(typedTestStruct{}, name).Provider().Validate())
+		assert.NotNil(t, Func(func() string { return "" }, Return(0, name)).Provider().Validate())
+	})
+
+	t.Run("dependency", func(t *testing.T) {
+		c := ValueConsumer(0, name).Consumer()
+		assert.Nil(t, c.Validate())
+		c.Dependencies().Iterate(func(dep Dependency) bool {
+			assert.Equal(t, "db", dep.Name())
+			return true
+		})
+		assert.NotNil(t, ValueConsumer("", name).Consumer().Validate())
+		assert.NotNil(t, Func(func(s string) int { return 0 }, Param(0, name)).Provider().Validate())
+	})
+
+	t.Run("criteria", func(t *testing.T) {
+		assert.Equal(t, "db", NewCriteria(0, name).Criteria().Name())
+	})
+}
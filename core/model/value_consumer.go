@@ -115,9 +115,9 @@ func (v *valueConsumer) SetLocation(loc location.Location) ValueConsumerBuilder
 }
 
 func (v *valueConsumer) UpdateCallLocation(loc location.Location) ValueConsumerBuilder {
-	if v.Location() == nil {
+	if v.Location() == nil && !v.noLocation {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		v.SetLocation(loc)
 	}
@@ -186,3 +186,9 @@ func (o LocationOption) ApplyValueConsumer(b ValueConsumerBuilder) {
 func (o UpdateCallLocationOption) ApplyValueConsumer(b ValueConsumerBuilder) {
 	b.UpdateCallLocation(o.Location)
 }
+
+func (o NoLocationOption) ApplyValueConsumer(b ValueConsumerBuilder) {
+	if c, ok := b.(*valueConsumer); ok {
+		c.noLocation = true
+	}
+}
@@ -28,6 +28,15 @@ func (vp *valueProvider) Components() ComponentCollection {
 	return ComponentsOfIterator(vp.com)
 }
 
+// ValueOfProvider returns the value provided by the provider, if it is a value provider
+func ValueOfProvider(p Provider) (reflect.Value, bool) {
+	vp, ok := p.(*valueProvider)
+	if !ok || vp == nil {
+		return reflect.Value{}, false
+	}
+	return vp.value, true
+}
+
 func (vp *valueProvider) Validate() error {
 	errs := errors.Empty()
 
@@ -144,6 +153,10 @@ func (vp *valueProvider) AddAs(ifs ...TypeVal) ValueProviderBuilder {
 	vp.com.AddAs(ifs...)
 	return vp
 }
+func (vp *valueProvider) addTypeConstraint(tc typeConstraint) {
+	vp.com.addTypeConstraint(tc)
+}
+
 func (vp *valueProvider) SetName(name string) ValueProviderBuilder {
 	vp.com.SetName(name)
 	return vp
@@ -166,7 +179,7 @@ func (vp *valueProvider) SetLocation(loc location.Location) ValueProviderBuilder
 func (vp *valueProvider) UpdateCallLocation(loc location.Location) ValueProviderBuilder {
 	if vp.Location() == nil {
 		if loc == nil {
-			loc = location.GetCallLocation(3).Callee()
+			loc = location.GetLocation(3)
 		}
 		vp.SetLocation(loc)
 	}
@@ -210,6 +223,16 @@ func Value(val interface{}, opts ...ValueProviderOption) ValueProviderBuilder {
 	return vp
 }
 
+// Supply provides each of the values as a component of its own type
+func Supply(vals ...interface{}) ModuleOption {
+	loc := location.GetLocation(2)
+	return moduleOption(func(builder ModuleBuilder) {
+		for _, val := range vals {
+			builder.AddProvider(Value(val, Location(loc)))
+		}
+	})
+}
+
 type ValueProviderOption interface {
 	ApplyValueProvider(b ValueProviderBuilder)
 }
@@ -517,3 +517,12 @@ func Test_valuerProvider_Equal(t *testing.T) {
 		})
 	})
 }
+
+func TestValueOfProvider(t *testing.T) {
+	val, ok := ValueOfProvider(Value(1).Provider())
+	assert.True(t, ok)
+	assert.Equal(t, 1, val.Interface())
+
+	_, ok = ValueOfProvider(Func(func() int { return 1 }).Provider())
+	assert.False(t, ok)
+}
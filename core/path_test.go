@@ -402,7 +402,7 @@ func Test_pathNode_Format(t *testing.T) {
 		t.Run("verbose", func(t *testing.T) {
 			expected := strings.Builder{}
 			expected.WriteString("path:")
-			expected.WriteString(fmt.Sprintf("\n\t%v", valuer.Error(&missingError{comDep})))
+			expected.WriteString(fmt.Sprintf("\n\t%v", valuer.Error(&missingError{dependency: comDep})))
 			expected.WriteString(fmt.Sprintf("\n\t%v", comDep))
 			expected.WriteString(fmt.Sprintf("\n\t%+v", com.Provider()))
 			expected.WriteString(fmt.Sprintf("\n\t%v", com))
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/core/valuer"
+	"github.com/jison/uni/internal/errors"
+)
+
+// Resolver resolves the value of a consumer prepared by a container, without validating
+// and deriving the graph again
+type Resolver interface {
+	// Resolve resolves the value in the container which prepares the resolver
+	Resolve() (interface{}, error)
+	// ResolveIn resolves the value in the container, which must be the container preparing the
+	// resolver, or a container entering scopes from it
+	ResolveIn(c Container) (interface{}, error)
+}
+
+// resolveStep is a node with its inputs and scope looked up from the graph in advance
+type resolveStep struct {
+	node       Node
+	scope      model.Scope
+	provider   model.Provider
+	isProvider bool
+	inputs     []*resolveStep
+}
+
+func (s *resolveStep) valueIn(storage ScopeBaseStorage) valuer.Value {
+	return storage.GetOrElse(s.node, s.scope, s.supply)
+}
+
+func (s *resolveStep) supply(storage ScopeBaseStorage) valuer.Value {
+	params := make([]valuer.Value, len(s.inputs))
+	for i, input := range s.inputs {
+		params[i] = input.valueIn(storage)
+	}
+	return valueOfProvider(s.node.Value(params), s.provider, s.isProvider)
+}
+
+// newResolveStep builds steps of the node and its upstream nodes, the graph must have no cycles
+// in the upstream of the node
+func newResolveStep(g DependenceGraph, node Node, steps map[Node]*resolveStep) *resolveStep {
+	if step, ok := steps[node]; ok {
+		return step
+	}
+
+	e := &executor{graph: g}
+	step := &resolveStep{node: node, scope: e.scopeOfNode(node)}
+	step.provider, step.isProvider = g.ProviderOfNode(node)
+	steps[node] = step
+
+	g.InputNodesTo(node).Each(func(inputNode Node) {
+		step.inputs = append(step.inputs, newResolveStep(g, inputNode, steps))
+	})
+	return step
+}
+
+type resolver struct {
+	base    DependenceGraph
+	storage ScopeBaseStorage
+	root    *resolveStep
+}
+
+func (r *resolver) Resolve() (interface{}, error) {
+	return r.root.valueIn(r.storage).Interface()
+}
+
+func (r *resolver) ResolveIn(c Container) (interface{}, error) {
+	cc, ok := c.(*container)
+	if !ok || cc == nil {
+		return nil, errors.Newf("container is nil")
+	}
+	if cc.graph != r.base {
+		return nil, errors.Newf("the resolver is not prepared by the container")
+	}
+	return r.root.valueIn(cc.storage).Interface()
+}
+
+func newResolver(base DependenceGraph, storage ScopeBaseStorage, consumer model.Consumer) (Resolver, error) {
+	if err := consumer.Validate(); err != nil {
+		return nil, err
+	}
+
+	g, consumerNode := base.Derive(consumer)
+	if err := g.ErrorOfNode(consumerNode); err != nil {
+		return nil, errors.Empty().AddErrors(err).WithMainf("can not prepare %+v", consumer)
+	}
+
+	return &resolver{
+		base:    base,
+		storage: storage,
+		root:    newResolveStep(g, consumerNode, map[Node]*resolveStep{}),
+	}, nil
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jison/uni/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_container_Prepare(t *testing.T) {
+	type db struct{}
+	type handler struct {
+		d    *db
+		path string
+	}
+	type missing struct{}
+
+	requestScope := model.NewScopeWith("request", model.ScopeInput("", model.Name("path")))
+	tag := model.NewSymbol("uncertain")
+	m := model.NewModule(
+		model.Func(func() *db { return &db{} }),
+		model.Func(func(d *db, path string) *handler {
+			return &handler{d, path}
+		}, model.InScope(requestScope), model.Param(1, model.ByName("path"))),
+		model.Func(func(m missing) int { return 1 }),
+		model.Value("a", model.Tags(tag)),
+		model.Value("b", model.Tags(tag)),
+	)
+	c, err := newContainer(m, &ContainerOptions{ignoreMissing: true, ignoreUncertain: true})
+	assert.Nil(t, err)
+
+	t.Run("resolve", func(t *testing.T) {
+		r, err := c.Prepare(model.ValueConsumer(&db{}))
+		assert.Nil(t, err)
+		d1, err := r.Resolve()
+		assert.Nil(t, err)
+		d2, err := c.ValueOf(&db{}).Execute()
+		assert.Nil(t, err)
+		assert.Same(t, d1, d2)
+	})
+
+	t.Run("resolve in scopes", func(t *testing.T) {
+		r, err := c.Prepare(model.ValueConsumer(&handler{}).SetScope(requestScope))
+		assert.Nil(t, err)
+
+		_, err = r.Resolve()
+		assert.NotNil(t, err)
+
+		for _, path := range []string{"/a", "/b"} {
+			c1, err := c.EnterScope(requestScope, NamedSeed("path", path))
+			assert.Nil(t, err)
+			h, err := r.ResolveIn(c1)
+			assert.Nil(t, err)
+			assert.Equal(t, path, h.(*handler).path)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := c.Prepare(model.ValueConsumer(0))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "can not find component match these dependencies")
+
+		_, err = c.Prepare(model.ValueConsumer("", model.ByTags(tag)))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "these dependencies are more than one component match")
+
+		_, err = c.Prepare(model.ValueConsumer(int8(0)))
+		assert.NotNil(t, err)
+
+		_, err = c.Prepare(nil)
+		assert.NotNil(t, err)
+		var nilContainer *container
+		_, err = nilContainer.Prepare(model.ValueConsumer(0))
+		assert.NotNil(t, err)
+
+		r, err := c.Prepare(model.ValueConsumer(&db{}))
+		assert.Nil(t, err)
+		c2, _ := newContainer(m, &ContainerOptions{ignoreMissing: true, ignoreUncertain: true})
+		_, err = r.ResolveIn(c2)
+		assert.NotNil(t, err)
+		_, err = r.ResolveIn(nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		type a struct{}
+		type b struct{}
+		c3, err := newContainer(model.NewModule(
+			model.Func(func(b) a { return a{} }),
+			model.Func(func(a) b { return b{} }),
+			model.Value(1),
+		), &ContainerOptions{ignoreCycle: true})
+		assert.Nil(t, err)
+		_, err = c3.Prepare(model.ValueConsumer(a{}))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "there are cycles")
+		_, err = c3.Prepare(model.ValueConsumer(0))
+		assert.Nil(t, err)
+	})
+}
+
+func Test_resolver_errorOfProvider(t *testing.T) {
+	type a struct{}
+	type b struct{}
+	boom := errors.New("boom")
+	c, err := newContainer(model.NewModule(
+		model.Func(func() (a, error) { return a{}, boom }),
+		model.Func(func(a) b { return b{} }),
+	), nil)
+	assert.Nil(t, err)
+
+	r, err := c.Prepare(model.ValueConsumer(b{}))
+	assert.Nil(t, err)
+	_, resolveErr := r.Resolve()
+	_, valueOfErr := c.ValueOf(b{}).Execute()
+	assert.NotNil(t, resolveErr)
+	assert.Equal(t, valueOfErr.Error(), resolveErr.Error())
+	assert.NotContains(t, resolveErr.Error(), "<nil>")
+	assert.True(t, errors.Is(resolveErr, boom))
+}
+
+type benchmarkDB struct{}
+
+type benchmarkService struct {
+	db   *benchmarkDB
+	path string
+}
+
+func newBenchmarkContainer(b *testing.B) (Container, model.Scope) {
+	requestScope := model.NewScopeWith("request", model.ScopeInput("", model.Name("path")))
+	c, err := newContainer(model.NewModule(
+		model.Func(func() *benchmarkDB { return &benchmarkDB{} }),
+		model.Func(func(db *benchmarkDB, path string) *benchmarkService {
+			return &benchmarkService{db, path}
+		}, model.InScope(requestScope), model.Param(1, model.ByName("path"))),
+	), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c, requestScope
+}
+
+func BenchmarkValueOf(b *testing.B) {
+	c, _ := newBenchmarkContainer(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.ValueOf(&benchmarkDB{}).Execute(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResolver_Resolve(b *testing.B) {
+	c, _ := newBenchmarkContainer(b)
+	r, err := c.Prepare(model.ValueConsumer(&benchmarkDB{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Resolve(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValueOf_scope(b *testing.B) {
+	c, requestScope := newBenchmarkContainer(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scoped, err := c.EnterScope(requestScope, NamedSeed("path", "/"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := scoped.ValueOf(&benchmarkService{}).Execute(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResolver_ResolveIn(b *testing.B) {
+	c, requestScope := newBenchmarkContainer(b)
+	r, err := c.Prepare(model.ValueConsumer(&benchmarkService{}).SetScope(requestScope))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scoped, err := c.EnterScope(requestScope, NamedSeed("path", "/"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := r.ResolveIn(scoped); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/graph"
+)
+
+// ScopeGraph builds a graph of the scopes and their ancestors, there is an edge from each parent
+// to its child, the location and inputs of a scope are attributes of its node
+func ScopeGraph(scopes ...model.Scope) graph.DirectedGraph {
+	g := graph.NewDirectedGraph()
+	all := model.ScopesWithAncestors(scopes...)
+	for _, s := range all {
+		attrs := graph.Attrs{}
+		if loc := s.Location(); loc != nil {
+			attrs.Set("location", loc)
+		}
+		if inputs := s.Inputs(); len(inputs) > 0 {
+			var coms model.ComponentSlice
+			for _, input := range inputs {
+				coms = append(coms, input.Components().ToArray()...)
+			}
+			attrs.Set("inputs", coms)
+		}
+		g.AddNodeWithAttrs(s, attrs)
+	}
+	for _, s := range all {
+		for _, p := range s.Parents() {
+			graph.AddEdge(g, p, s)
+		}
+	}
+	return g
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeGraph(t *testing.T) {
+	s1 := model.NewScope("scope1")
+	s2 := model.NewScopeWith("scope2", s1, model.ScopeInput(""))
+	s3 := model.NewScope("scope3", s1, s2)
+
+	g := ScopeGraph(s3)
+
+	count := 0
+	g.Nodes().Iterate(func(_ graph.Node, _ graph.AttrsView) bool {
+		count += 1
+		return true
+	})
+	assert.Equal(t, 4, count)
+
+	for _, edge := range [][2]model.Scope{{model.GlobalScope, s1}, {s1, s2}, {s1, s3}, {s2, s3}} {
+		_, ok := g.EdgeAttrs(edge[0], edge[1])
+		assert.True(t, ok)
+	}
+	_, ok := g.EdgeAttrs(model.GlobalScope, s3)
+	assert.False(t, ok)
+
+	attrs, _ := g.NodeAttrs(s2)
+	loc, ok := attrs.Get("location")
+	assert.True(t, ok)
+	assert.Equal(t, s2.Location(), loc)
+	inputs, ok := attrs.Get("inputs")
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(inputs.(model.ComponentSlice)))
+
+	attrs, _ = g.NodeAttrs(model.GlobalScope)
+	assert.False(t, attrs.Has("location"))
+}
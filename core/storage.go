@@ -2,6 +2,8 @@ package core
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/jison/uni/core/valuer"
 
@@ -13,10 +15,76 @@ type ScopeBaseStorage interface {
 	GetOrElse(node Node, scope model.Scope, getter func(ScopeBaseStorage) valuer.Value) valuer.Value
 }
 
+// storageHooks connects storages with the graph of the container
+type storageHooks struct {
+	// policyOf returns the cache policy of the node, and whether the node is a provider
+	policyOf func(node Node) (policy model.CachePolicy, hasPolicy bool, isProvider bool)
+	// onDrop is called after the value of the node is dropped from a storage
+	onDrop func(node Node, val valuer.Value) error
+	// inherited returns true if the node is inherited from the container of the base storage
+	inherited func(node Node) bool
+	now       func() time.Time
+}
+
+// storageShared is shared by a storage and all storages entered from it
+type storageShared struct {
+	// drops is increased every time a value is dropped, entries are checked again if it is changed,
+	// it is shared with the base storage, since entries may depend on entries of the base storage
+	drops *uint64
+	hooks storageHooks
+	// base is the storage of the parent container, values of inherited nodes are cached in it
+	base *scopeStorage
+}
+
+func (sh *storageShared) now() time.Time {
+	if sh.hooks.now != nil {
+		return sh.hooks.now()
+	}
+	return time.Now()
+}
+
+type cacheEntry struct {
+	// checkedAt is the drops count when the entry and its dependencies were checked valid last time
+	checkedAt uint64
+	dropped   int32
+
+	value valuer.Value
+	// expiresAt is the earliest expiration of the entry and its dependencies, zero means never
+	expiresAt time.Time
+	deps      []*cacheEntry
+}
+
+// valid checks the entry is not expired, and it and its dependencies are not dropped. The time is
+// only got if the entry expires, which is the earliest expiration of its dependencies too
+func (e *cacheEntry) valid(sh *storageShared, drops uint64) bool {
+	if !e.expiresAt.IsZero() && !sh.now().Before(e.expiresAt) {
+		return false
+	}
+	return e.notDropped(drops)
+}
+
+func (e *cacheEntry) notDropped(drops uint64) bool {
+	if atomic.LoadInt32(&e.dropped) == 1 {
+		return false
+	}
+	if len(e.deps) == 0 || atomic.LoadUint64(&e.checkedAt) == drops {
+		return true
+	}
+
+	for _, dep := range e.deps {
+		if !dep.notDropped(drops) {
+			return false
+		}
+	}
+	atomic.StoreUint64(&e.checkedAt, drops)
+	return true
+}
+
 func newScopeStorage() *scopeStorage {
 	return &scopeStorage{
 		parent:      nil,
 		scope:       model.GlobalScope,
+		shared:      &storageShared{drops: new(uint64)},
 		valueByNode: &sync.Map{},
 		mutexByNode: &sync.Map{},
 	}
@@ -25,27 +93,61 @@ func newScopeStorage() *scopeStorage {
 type scopeStorage struct {
 	parent      *scopeStorage
 	scope       model.Scope
-	valueByNode *sync.Map // map[Node]valuer.Value
+	shared      *storageShared
+	valueByNode *sync.Map // map[Node]*cacheEntry
 	mutexByNode *sync.Map // map[Node]*sync.Mutex
+
+	// entriesMutex guards changes of valueByNode and the nodes below
+	entriesMutex sync.Mutex
+	createdNodes []Node // nodes in the order of creation, seeded nodes are excluded
+	seededNodes  []Node
 }
 
 func (s *scopeStorage) Scope() model.Scope {
 	return s.scope
 }
 
+// storageOf returns the storage of the scope in the storage and its ancestors
+func (s *scopeStorage) storageOf(scope model.Scope) *scopeStorage {
+	for storage := s; storage != nil; storage = storage.parent {
+		if storage.scope == scope {
+			return storage
+		}
+	}
+	return nil
+}
+
+// storageOfNode returns the storage caching the value of the node in the scope, values of
+// inherited nodes are cached in the base storage if the scope is entered in it
+func (s *scopeStorage) storageOfNode(node Node, scope model.Scope) *scopeStorage {
+	base := s.shared.base
+	if base != nil && s.shared.hooks.inherited != nil && s.shared.hooks.inherited(node) {
+		if storage := base.storageOfNode(node, scope); storage != nil {
+			return storage
+		}
+	}
+	return s.storageOf(scope)
+}
+
+// load returns the entry of the node, and whether it is valid
+func (s *scopeStorage) load(node Node) (*cacheEntry, bool) {
+	e, ok := s.valueByNode.Load(node)
+	if !ok {
+		return nil, false
+	}
+	entry := e.(*cacheEntry)
+	return entry, entry.valid(s.shared, atomic.LoadUint64(s.shared.drops))
+}
+
 func (s *scopeStorage) Get(node Node, scope model.Scope) (valuer.Value, bool) {
-	if s.scope == scope {
-		value, ok := s.valueByNode.Load(node)
-		if ok {
-			return value.(valuer.Value), true
-		} else {
-			return nil, false
-		}
-	} else if s.parent != nil {
-		return s.parent.Get(node, scope)
-	} else {
+	storage := s.storageOfNode(node, scope)
+	if storage == nil {
 		return nil, false
 	}
+	if entry, ok := storage.load(node); ok {
+		return entry.value, true
+	}
+	return nil, false
 }
 
 func (s *scopeStorage) GetOrElse(node Node, scope model.Scope,
@@ -61,23 +163,23 @@ func (s *scopeStorage) GetOrElse(node Node, scope model.Scope,
 		return valSupplier(s)
 	}
 
-	if s.scope == scope {
-		value, ok := s.valueByNode.Load(node)
-		if ok {
-			return value.(valuer.Value)
-		} else if valSupplier == nil {
-			return valuer.ErrorValue(errors.Newf("valSupplier is nil"))
-		} else {
-			return valuer.LazyValue(func() valuer.Value {
-				nodeMutex := s.getMutexByNode(node)
-				return s.update(nodeMutex, node, valSupplier)
-			})
-		}
-	} else if s.parent != nil {
-		return s.parent.GetOrElse(node, scope, valSupplier)
-	} else {
+	storage := s.storageOfNode(node, scope)
+	if storage == nil {
 		return valuer.ErrorValue(errors.Newf("this scope `%v` is not entered in the context", scope))
 	}
+
+	if entry, ok := storage.load(node); ok {
+		return entry.value
+	} else if valSupplier == nil {
+		return valuer.ErrorValue(errors.Newf("valSupplier is nil"))
+	}
+
+	return valuer.LazyValue(func() valuer.Value {
+		nodeMutex := storage.getMutexByNode(node)
+		val := storage.update(nodeMutex, node, valSupplier)
+		storage.limitEntries()
+		return val
+	})
 }
 
 func (s *scopeStorage) update(mutex *sync.Mutex, node Node,
@@ -85,19 +187,222 @@ func (s *scopeStorage) update(mutex *sync.Mutex, node Node,
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	nodeVal, ok := s.valueByNode.Load(node)
+	old, ok := s.load(node)
 	if ok {
-		return nodeVal.(valuer.Value)
+		return old.value
+	}
+
+	drops := atomic.LoadUint64(s.shared.drops)
+	recorder := &depsRecorder{storage: s}
+	val := valSupplier(recorder)
+	if _, isErr := val.AsError(); isErr {
+		return val
+	}
+
+	entry := &cacheEntry{
+		checkedAt: drops,
+		value:     val,
+		deps:      recorder.entries(),
+	}
+	if policy, hasPolicy, _ := s.policyOf(node); hasPolicy && policy.TTL > 0 {
+		entry.expiresAt = s.shared.now().Add(policy.TTL)
+	}
+	for _, dep := range entry.deps {
+		if !dep.expiresAt.IsZero() && (entry.expiresAt.IsZero() || dep.expiresAt.Before(entry.expiresAt)) {
+			entry.expiresAt = dep.expiresAt
+		}
 	}
 
-	val := valSupplier(s)
-	if _, isErr := val.AsError(); !isErr {
-		s.valueByNode.Store(node, val)
+	if old != nil {
+		// the old value is expired or its dependencies are changed
+		_ = s.drop(node, old)
 	}
 
+	s.entriesMutex.Lock()
+	s.valueByNode.Store(node, entry)
+	s.createdNodes = append(s.createdNodes, node)
+	s.entriesMutex.Unlock()
+
 	return val
 }
 
+func (s *scopeStorage) policyOf(node Node) (model.CachePolicy, bool, bool) {
+	if s.shared.hooks.policyOf == nil {
+		return model.CachePolicy{}, false, false
+	}
+	return s.shared.hooks.policyOf(node)
+}
+
+// drop removes the entry of the node, and entries depending on it become invalid
+func (s *scopeStorage) drop(node Node, entry *cacheEntry) error {
+	if !s.remove(node, entry) {
+		return nil
+	}
+
+	if s.shared.hooks.onDrop != nil {
+		return s.shared.hooks.onDrop(node, entry.value)
+	}
+	return nil
+}
+
+// remove is drop without calling the onDrop hook, it returns false if the entry has been dropped
+func (s *scopeStorage) remove(node Node, entry *cacheEntry) bool {
+	s.entriesMutex.Lock()
+	if e, ok := s.valueByNode.Load(node); ok && e.(*cacheEntry) == entry {
+		s.valueByNode.Delete(node)
+		for i, n := range s.createdNodes {
+			if n == node {
+				s.createdNodes = append(s.createdNodes[:i:i], s.createdNodes[i+1:]...)
+				break
+			}
+		}
+	}
+	s.entriesMutex.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&entry.dropped, 0, 1) {
+		return false
+	}
+	atomic.AddUint64(s.shared.drops, 1)
+	return true
+}
+
+// removeCreated removes values created in the storage without calling the onDrop hook,
+// such as after they are closed
+func (s *scopeStorage) removeCreated() {
+	for _, node := range s.createdNodesSnapshot() {
+		if e, ok := s.valueByNode.Load(node); ok {
+			s.remove(node, e.(*cacheEntry))
+		}
+	}
+}
+
+// dropNode drops the value of the node if it is stored in the storage
+func (s *scopeStorage) dropNode(node Node) error {
+	e, ok := s.valueByNode.Load(node)
+	if !ok {
+		return nil
+	}
+	return s.drop(node, e.(*cacheEntry))
+}
+
+// replace stores the value of node in the storage instead of building it, the old value is dropped
+func (s *scopeStorage) replace(node Node, val valuer.Value) {
+	mutex := s.getMutexByNode(node)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if e, ok := s.valueByNode.Load(node); ok {
+		_ = s.drop(node, e.(*cacheEntry))
+	}
+
+	s.entriesMutex.Lock()
+	s.valueByNode.Store(node, &cacheEntry{
+		checkedAt: atomic.LoadUint64(s.shared.drops),
+		value:     val,
+	})
+	s.createdNodes = append(s.createdNodes, node)
+	s.entriesMutex.Unlock()
+}
+
+// evict drops the value of the node and values depending on it in the storage
+func (s *scopeStorage) evict(node Node) error {
+	errs := errors.Empty()
+	if err := s.dropNode(node); err != nil {
+		errs = errs.AddErrors(err)
+	}
+	if err := s.sweep(); err != nil {
+		errs = errs.AddErrors(err)
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+// sweep drops invalid values in the storage, the latest created first
+func (s *scopeStorage) sweep() error {
+	errs := errors.Empty()
+	nodes := s.createdNodesSnapshot()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		if entry, ok := s.load(node); !ok && entry != nil {
+			if err := s.drop(node, entry); err != nil {
+				errs = errs.AddErrors(err)
+			}
+		}
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+// sweepAll sweeps the storage, its ancestors and their base storages
+func (s *scopeStorage) sweepAll() error {
+	errs := errors.Empty()
+	for storage := s; storage != nil; storage = storage.parent {
+		if err := storage.sweep(); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+	if base := s.shared.base; base != nil {
+		if err := base.sweepAll(); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+// limitEntries evicts the earliest created values of providers if there are more than
+// MaxEntries of the cache policy of the scope
+func (s *scopeStorage) limitEntries() {
+	policy, ok := s.scope.CachePolicy()
+	if !ok || policy.MaxEntries <= 0 {
+		return
+	}
+
+	var providerNodes []Node
+	for _, node := range s.createdNodesSnapshot() {
+		if _, _, isProvider := s.policyOf(node); isProvider {
+			providerNodes = append(providerNodes, node)
+		}
+	}
+
+	for i := 0; i < len(providerNodes)-policy.MaxEntries; i++ {
+		_ = s.evict(providerNodes[i])
+	}
+}
+
+func (s *scopeStorage) createdNodesSnapshot() []Node {
+	s.entriesMutex.Lock()
+	defer s.entriesMutex.Unlock()
+
+	nodes := make([]Node, len(s.createdNodes))
+	copy(nodes, s.createdNodes)
+	return nodes
+}
+
+// createdValues returns values created in the scope of the storage, the latest first
+func (s *scopeStorage) createdValues() ([]Node, []valuer.Value) {
+	nodes := s.createdNodesSnapshot()
+
+	var resNodes []Node
+	var values []valuer.Value
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if e, ok := s.valueByNode.Load(nodes[i]); ok {
+			resNodes = append(resNodes, nodes[i])
+			values = append(values, e.(*cacheEntry).value)
+		}
+	}
+	return resNodes, values
+}
+
 func (s *scopeStorage) getMutexByNode(node Node) *sync.Mutex {
 	if mutex, ok := s.mutexByNode.Load(node); ok {
 		return mutex.(*sync.Mutex)
@@ -115,12 +420,87 @@ func (s *scopeStorage) Enter(scope model.Scope) (*scopeStorage, error) {
 	newS := &scopeStorage{
 		parent:      s,
 		scope:       scope,
+		shared:      s.shared,
 		valueByNode: &sync.Map{},
 		mutexByNode: &sync.Map{},
 	}
 	return newS, nil
 }
 
+// seed stores the value of node in the scope of the storage directly
+func (s *scopeStorage) seed(node Node, val valuer.Value) {
+	s.entriesMutex.Lock()
+	s.valueByNode.Store(node, &cacheEntry{
+		checkedAt: atomic.LoadUint64(s.shared.drops),
+		value:     val,
+	})
+	s.seededNodes = append(s.seededNodes, node)
+	s.entriesMutex.Unlock()
+}
+
+// storedNodes returns nodes whose values are seeded and created in the scope of the storage,
+// created nodes are in the order of creation
+func (s *scopeStorage) storedNodes() (seeded []Node, created []Node) {
+	s.entriesMutex.Lock()
+	seeded = append(seeded, s.seededNodes...)
+	s.entriesMutex.Unlock()
+
+	for _, node := range s.createdNodesSnapshot() {
+		if _, ok := s.load(node); ok {
+			created = append(created, node)
+		}
+	}
+	return seeded, created
+}
+
 func (s *scopeStorage) Leave() *scopeStorage {
 	return s.parent
 }
+
+// depsRecorder records scoped nodes got when building the value of a node,
+// the value is invalid if any of their values is dropped or expired
+type depsRecorder struct {
+	storage *scopeStorage
+
+	mutex  sync.Mutex
+	nodes  []Node
+	scopes []model.Scope
+}
+
+func (r *depsRecorder) GetOrElse(node Node, scope model.Scope,
+	valSupplier func(ScopeBaseStorage) valuer.Value) valuer.Value {
+	if node == nil {
+		return valuer.ErrorValue(errors.Newf("node is nil"))
+	}
+
+	if scope == nil {
+		if valSupplier == nil {
+			return valuer.ErrorValue(errors.Newf("valSupplier is nil"))
+		}
+		return valSupplier(r)
+	}
+
+	r.mutex.Lock()
+	r.nodes = append(r.nodes, node)
+	r.scopes = append(r.scopes, scope)
+	r.mutex.Unlock()
+
+	return r.storage.GetOrElse(node, scope, valSupplier)
+}
+
+func (r *depsRecorder) entries() []*cacheEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var entries []*cacheEntry
+	for i, node := range r.nodes {
+		storage := r.storage.storageOfNode(node, r.scopes[i])
+		if storage == nil {
+			continue
+		}
+		if e, ok := storage.valueByNode.Load(node); ok {
+			entries = append(entries, e.(*cacheEntry))
+		}
+	}
+	return entries
+}
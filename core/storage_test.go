@@ -6,6 +6,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jison/uni/core/model"
 	"github.com/jison/uni/core/valuer"
@@ -451,3 +452,170 @@ func Test_scopeStorage_Scope(t *testing.T) {
 		assert.Equal(t, scope1, ss1.Scope())
 	})
 }
+
+func Test_scopeStorage_cache(t *testing.T) {
+	scope1 := model.NewScopeWith("scope1", model.Cache(model.CachePolicy{MaxEntries: 2}))
+
+	type counterNode struct {
+		node    Node
+		counter int
+	}
+	newNode := func() *counterNode {
+		return &counterNode{node: valuer.Identity()}
+	}
+	supplier := func(n *counterNode, deps ...*counterNode) func(ScopeBaseStorage) valuer.Value {
+		return func(s ScopeBaseStorage) valuer.Value {
+			for _, dep := range deps {
+				_, _ = s.GetOrElse(dep.node, model.GlobalScope, nil).AsSingle()
+			}
+			n.counter += 1
+			return valuer.SingleValue(reflect.ValueOf(n.counter))
+		}
+	}
+
+	newStorage := func(policies map[Node]model.CachePolicy) (*scopeStorage, *time.Time, *[]Node) {
+		now := time.Now()
+		var dropped []Node
+		ss := newScopeStorage()
+		ss.shared.hooks = storageHooks{
+			policyOf: func(node Node) (model.CachePolicy, bool, bool) {
+				policy, ok := policies[node]
+				return policy, ok, true
+			},
+			onDrop: func(node Node, _ valuer.Value) error {
+				dropped = append(dropped, node)
+				return nil
+			},
+			now: func() time.Time { return now },
+		}
+		return ss, &now, &dropped
+	}
+
+	getInt := func(ss *scopeStorage, n *counterNode, scope model.Scope, deps ...*counterNode) int {
+		rv, ok := ss.GetOrElse(n.node, scope, supplier(n, deps...)).AsSingle()
+		assert.True(t, ok)
+		return rv.Interface().(int)
+	}
+
+	t.Run("ttl", func(t *testing.T) {
+		n1 := newNode()
+		n2 := newNode()
+		ss, now, dropped := newStorage(map[Node]model.CachePolicy{n1.node: {TTL: time.Minute}})
+
+		assert.Equal(t, 1, getInt(ss, n1, model.GlobalScope))
+		assert.Equal(t, 1, getInt(ss, n2, model.GlobalScope, n1))
+
+		*now = now.Add(30 * time.Second)
+		assert.Equal(t, 1, getInt(ss, n1, model.GlobalScope))
+		assert.Equal(t, 1, getInt(ss, n2, model.GlobalScope, n1))
+
+		// n2 expires with n1
+		*now = now.Add(30 * time.Second)
+		_, ok := ss.Get(n2.node, model.GlobalScope)
+		assert.False(t, ok)
+		assert.Equal(t, 2, getInt(ss, n2, model.GlobalScope, n1))
+		assert.Equal(t, 2, getInt(ss, n1, model.GlobalScope))
+		assert.Equal(t, []Node{n1.node, n2.node}, *dropped)
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		n1 := newNode()
+		n2 := newNode()
+		n3 := newNode()
+		ss, _, dropped := newStorage(nil)
+		ss1, _ := ss.Enter(scope1)
+
+		assert.Equal(t, 1, getInt(ss1, n1, model.GlobalScope))
+		assert.Equal(t, 1, getInt(ss1, n2, scope1, n1))
+		assert.Equal(t, 1, getInt(ss1, n3, model.GlobalScope))
+
+		assert.Nil(t, ss.evict(n1.node))
+		assert.Equal(t, []Node{n1.node}, *dropped)
+		assert.Nil(t, ss1.sweep())
+		assert.Equal(t, []Node{n1.node, n2.node}, *dropped)
+
+		assert.Equal(t, 2, getInt(ss1, n2, scope1, n1))
+		assert.Equal(t, 2, getInt(ss1, n1, model.GlobalScope))
+		assert.Equal(t, 1, getInt(ss1, n3, model.GlobalScope))
+		assert.Nil(t, ss.evict(newNode().node))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		n1 := newNode()
+		n2 := newNode()
+		ss, _, dropped := newStorage(nil)
+
+		assert.Equal(t, 1, getInt(ss, n1, model.GlobalScope))
+		assert.Equal(t, 1, getInt(ss, n2, model.GlobalScope, n1))
+		ss.replace(n1.node, valuer.SingleValue(reflect.ValueOf(10)))
+		assert.Equal(t, []Node{n1.node}, *dropped)
+		assert.Nil(t, ss.sweep())
+		assert.Equal(t, []Node{n1.node, n2.node}, *dropped)
+
+		assert.Equal(t, 10, getInt(ss, n1, model.GlobalScope))
+		assert.Equal(t, 1, n1.counter)
+		assert.Equal(t, 2, getInt(ss, n2, model.GlobalScope, n1))
+
+		n3 := newNode()
+		ss.replace(n3.node, valuer.SingleValue(reflect.ValueOf(20)))
+		assert.Equal(t, 20, getInt(ss, n3, model.GlobalScope))
+		assert.Equal(t, 0, n3.counter)
+	})
+
+	t.Run("max entries", func(t *testing.T) {
+		n1 := newNode()
+		n2 := newNode()
+		n3 := newNode()
+		ss, _, dropped := newStorage(nil)
+		ss1, _ := ss.Enter(scope1)
+
+		assert.Equal(t, 1, getInt(ss1, n1, scope1))
+		assert.Equal(t, 1, getInt(ss1, n2, scope1))
+		assert.Equal(t, 0, len(*dropped))
+		assert.Equal(t, 1, getInt(ss1, n3, scope1))
+		assert.Equal(t, []Node{n1.node}, *dropped)
+
+		assert.Equal(t, 1, getInt(ss1, n2, scope1))
+		assert.Equal(t, 2, getInt(ss1, n1, scope1))
+		assert.Equal(t, []Node{n1.node, n2.node}, *dropped)
+	})
+}
+
+func Benchmark_scopeStorage_GetOrElse(b *testing.B) {
+	supplier := func(ScopeBaseStorage) valuer.Value { return valuer.SingleValue(reflect.ValueOf(1)) }
+	newStorage := func(policy model.CachePolicy, hasPolicy bool) (*scopeStorage, Node) {
+		ss := newScopeStorage()
+		ss.shared.hooks = storageHooks{
+			policyOf: func(Node) (model.CachePolicy, bool, bool) { return policy, hasPolicy, true },
+		}
+		dep := valuer.Identity()
+		node := valuer.Identity()
+		_, _ = ss.GetOrElse(node, model.GlobalScope, func(s ScopeBaseStorage) valuer.Value {
+			_, _ = s.GetOrElse(dep, model.GlobalScope, supplier).AsSingle()
+			return supplier(s)
+		}).AsSingle()
+		// values dropped by other nodes move the drop counter
+		other := valuer.Identity()
+		_, _ = ss.GetOrElse(other, model.GlobalScope, supplier).AsSingle()
+		_ = ss.dropNode(other)
+		return ss, node
+	}
+
+	b.Run("without policy", func(b *testing.B) {
+		ss, node := newStorage(model.CachePolicy{}, false)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = ss.GetOrElse(node, model.GlobalScope, supplier)
+		}
+	})
+
+	b.Run("with ttl", func(b *testing.B) {
+		ss, node := newStorage(model.CachePolicy{TTL: time.Hour}, true)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = ss.GetOrElse(node, model.GlobalScope, supplier)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+)
+
+// validateStrict validates providers of components in rep, dependencies are matched in lookup
+func validateStrict(rep model.ComponentRepository, lookup model.ComponentRepository) error {
+	errs := errors.Empty()
+
+	var providers []model.Provider
+	visited := map[model.Provider]struct{}{}
+	rep.AllComponents().Each(func(com model.Component) {
+		p := com.Provider()
+		if _, ok := visited[p]; ok {
+			return
+		}
+		visited[p] = struct{}{}
+		providers = append(providers, p)
+	})
+
+	checkedScopes := map[model.Scope]struct{}{}
+	for _, p := range providers {
+		s := p.Scope()
+		if _, ok := checkedScopes[s]; !ok && s != model.GlobalScope {
+			checkedScopes[s] = struct{}{}
+			if len(model.ScopePaths(model.GlobalScope, s)) == 0 {
+				errs = errs.AddErrorf("scope `%v` of %+v can not be entered from the global scope", s, p)
+			}
+		}
+
+		p.Dependencies().Iterate(func(dep model.Dependency) bool {
+			if err := validateDependencyStrict(dep, lookup); err != nil {
+				errs = errs.AddErrors(err)
+			}
+			return true
+		})
+	}
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+func validateDependencyStrict(dep model.Dependency, lookup model.ComponentRepository) error {
+	errs := errors.Empty()
+
+	if field, ok := model.FieldOfDependency(dep); ok {
+		if field.Name == "_" {
+			errs = errs.AddErrorf("blank field of %+v can not be set", dep)
+		} else if field.PkgPath != "" {
+			errs = errs.AddErrorf("%+v is unexported, it can not be set without unsafe", dep)
+		}
+	}
+
+	lookup.ComponentsMatchDependency(dep).Each(func(com model.Component) {
+		if !com.Type().AssignableTo(dep.Type()) {
+			errs = errs.AddErrorf("%+v can not be assigned to %+v", com, dep)
+		}
+	})
+
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+// buildEagerly builds components in the global scope of rep if the container is eager
+func (c *container) buildEagerly(rep model.ComponentRepository, opts *ContainerOptions) error {
+	if opts == nil || !opts.eager {
+		return nil
+	}
+
+	errs := errors.Empty()
+	rep.ComponentsWithScope(model.GlobalScope).Each(func(com model.Component) {
+		node, ok := c.graph.NodeOfComponent(com)
+		if !ok {
+			return
+		}
+		e := &executor{
+			graph:     c.graph,
+			cycleInfo: c.graph.CycleInfo(),
+			storage:   c.storage,
+			node:      node,
+		}
+		if _, err := e.Execute(); err != nil {
+			errs = errs.AddErrors(err)
+		}
+	})
+
+	if errs.HasError() {
+		if err := c.Close(); err != nil {
+			errs = errs.AddErrors(err)
+		}
+		return errs.WithMainf("can not build components eagerly")
+	}
+	return nil
+}
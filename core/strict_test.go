@@ -0,0 +1,156 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type orphanScope struct {
+	model.Scope
+}
+
+func (s *orphanScope) Parents() []model.Scope {
+	return nil
+}
+
+type fixedRepository struct {
+	model.ComponentRepository
+	coms model.ComponentCollection
+}
+
+func (r *fixedRepository) ComponentsMatchDependency(_ model.Dependency) model.ComponentCollection {
+	return r.coms
+}
+
+func TestStrict(t *testing.T) {
+	type missing struct{}
+	type exported struct {
+		I int
+	}
+	type unexported struct {
+		i int
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		m := model.NewModule(
+			model.Value(1),
+			model.Struct(exported{}),
+			model.Struct(unexported{}, model.IgnoreFields(func(field reflect.StructField) bool {
+				return true
+			})),
+		)
+		_, err := NewContainer(m, Strict())
+		assert.Nil(t, err)
+	})
+
+	t.Run("ignore options are invalid", func(t *testing.T) {
+		m := model.NewModule(model.Func(func(m missing) int { return 1 }))
+		_, err := NewContainer(m, IgnoreMissing())
+		assert.Nil(t, err)
+		_, err = NewContainer(m, IgnoreMissing(), Strict())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "container is invalid in strict mode")
+	})
+
+	t.Run("aggregated report", func(t *testing.T) {
+		orphan := &orphanScope{model.NewScope("orphan")}
+		m := model.NewModule(
+			model.Value(1),
+			model.Struct(unexported{}),
+			model.Func(func(m missing) string { return "" }),
+			model.Func(func() int8 { return 1 }, model.InScope(orphan)),
+		)
+		_, err := NewContainer(m, IgnoreMissing())
+		assert.Nil(t, err)
+
+		_, err = NewContainer(m, IgnoreMissing(), Strict())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "field `i`")
+		assert.Contains(t, err.Error(), "can not be entered from the global scope")
+		assert.Contains(t, err.Error(), "core.missing")
+	})
+
+	t.Run("child", func(t *testing.T) {
+		c, err := NewContainer(model.NewModule(model.Value(1), model.Struct(unexported{})))
+		assert.Nil(t, err)
+		_, err = c.Child(model.NewModule(model.Func(func(u unexported) int8 { return int8(u.i) })), Strict())
+		assert.Nil(t, err)
+		_, err = c.Child(model.NewModule(model.Struct(unexported{}, model.Name("u"))), Strict())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "field `i`")
+	})
+}
+
+func Test_validateDependencyStrict(t *testing.T) {
+	type blank struct {
+		_ int
+	}
+	m := model.NewModule(model.Value(1))
+	rep := model.NewRepositoryOfModule(m)
+	strs := model.NewRepositoryOfModule(model.NewModule(model.Value("abc"))).AllComponents()
+
+	var deps []model.Dependency
+	model.FuncConsumer(func(i int) {}).Consumer().Dependencies().Iterate(func(dep model.Dependency) bool {
+		deps = append(deps, dep)
+		return true
+	})
+	assert.Nil(t, validateDependencyStrict(deps[0], rep))
+
+	err := validateDependencyStrict(deps[0], &fixedRepository{rep, strs})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "can not be assigned to")
+
+	model.StructConsumer(blank{}).Consumer().Dependencies().Iterate(func(dep model.Dependency) bool {
+		err := validateDependencyStrict(dep, rep)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "blank field")
+		return true
+	})
+}
+
+func TestEager(t *testing.T) {
+	type db struct{ *closeRecorder }
+	var closed []string
+	counter := 0
+	m := model.NewModule(
+		model.Func(func() *db {
+			counter += 1
+			return &db{&closeRecorder{name: "db", closed: &closed}}
+		}),
+	)
+
+	c, err := NewContainer(m, Eager())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, counter)
+	_, err = c.ValueOf(&db{}).Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, counter)
+
+	t.Run("child", func(t *testing.T) {
+		built := false
+		_, err := c.Child(model.NewModule(model.Func(func(d *db) int {
+			built = true
+			return 1
+		})), Eager())
+		assert.Nil(t, err)
+		assert.True(t, built)
+		assert.Equal(t, 1, counter)
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		closed = nil
+		m2 := model.NewModule(
+			model.Func(func() *db { return &db{&closeRecorder{name: "db", closed: &closed}} }),
+			model.Func(func(d *db) (int, error) { return 0, errors.Newf("build error") }),
+		)
+		_, err := NewContainer(m2, Eager())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "build error")
+		assert.Contains(t, err.Error(), "can not build components eagerly")
+		assert.Equal(t, []string{"db"}, closed)
+	})
+}
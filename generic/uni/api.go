@@ -11,10 +11,19 @@ import (
 import "reflect"
 
 type Container = core.Container
+type CachePolicy = model.CachePolicy
+type Resolver = core.Resolver
+type Error = errors.StructError
 
 var IgnoreMissing = core.IgnoreMissing
 var IgnoreUncertain = core.IgnoreUncertain
 var IgnoreCycle = core.IgnoreCycle
+var ActiveProfiles = core.ActiveProfiles
+var Strict = core.Strict
+var Eager = core.Eager
+var CycleSearchLimits = core.CycleSearchLimits
+var NoCallLocations = core.NoCallLocations
+var CaptureLocations = model.CaptureLocations
 var NewContainer = core.NewContainer
 
 var NewModuleBuilder = model.NewModuleBuilder
@@ -22,8 +31,26 @@ var NewModule = model.NewModule
 
 var Module = model.SubModule
 var Provide = model.Provide
+var Private = model.Private
+var Export = model.Export
+var Namespace = model.Namespace
+var WithNamespace = model.WithNamespace
+var NamespacedTag = model.NamespacedSymbol
+var Require = model.Require
+var Default = model.Default
+
+var When = model.When
+var Profile = model.Profile
+var DeclareProfiles = model.DeclareProfiles
+var OnProfile = model.OnProfile
+var OnEnv = model.OnEnv
+var OnSwitch = model.OnSwitch
+var OnFunc = model.OnFunc
+var Not = model.Not
 
 var Value = model.Value
+var Supply = model.Supply
+var Bind = model.Bind
 
 var Struct = model.Struct
 var Field = model.Field
@@ -54,6 +81,15 @@ var ByTags = model.ByTags
 var NewTag = model.NewSymbol
 
 var NewScope = model.NewScope
+var NewScopeWith = model.NewScopeWith
+var ScopeInput = model.ScopeInput
+var ScopesWithAncestors = model.ScopesWithAncestors
+var FormatScopeHierarchy = model.FormatScopeHierarchy
+var ScopeGraph = core.ScopeGraph
+var Cache = model.Cache
+var Seed = core.Seed
+var NamedSeed = core.NamedSeed
+var OptionalSeed = core.OptionalSeed
 
 var BuildFunc = model.FuncConsumer
 var BuildStruct = model.StructConsumer
@@ -68,6 +104,8 @@ var StructOfCtx = core.StructOfCtx
 var ValueOfCtx = core.ValueOfCtx
 var EnterScopeCtx = core.EnterScopeCtx
 var LeaveScopeCtx = core.LeaveScopeCtx
+var EnterScopePathCtx = core.EnterScopePathCtx
+var LeaveScopeToCtx = core.LeaveScopeToCtx
 
 func TypeOfT[T any]() reflect.Type {
 	return reflect.TypeOf((*T)(nil)).Elem()
@@ -81,6 +119,12 @@ func AsT[T any]() model.ComponentOption {
 	return As(TypeOfT[T]())
 }
 
+// BindT provides a component of type I, which is the component of type Impl
+func BindT[I any, Impl any](opts ...model.BindProviderOption) model.BindProviderBuilder {
+	opts = append(opts, model.UpdateCallLocation())
+	return Bind(TypeOfT[I](), TypeOfT[Impl](), opts...)
+}
+
 func StructT[T any](opts ...model.StructProviderOption) model.StructProviderBuilder {
 	opts = append(opts, model.UpdateCallLocation())
 	return Struct(TypeOfT[T](), opts...)
@@ -141,13 +185,36 @@ func suppressUnusedWarningDslGeneric() {
 	var _ = IgnoreMissing
 	var _ = IgnoreUncertain
 	var _ = IgnoreCycle
+	var _ = ActiveProfiles
+	var _ = Strict
+	var _ = Eager
+	var _ = CycleSearchLimits
+	var _ = NoCallLocations
+	var _ = CaptureLocations
 	var _ = NewContainer
 	var _ = NewModuleBuilder
 	var _ = NewModule
 	var _ = Module
 	var _ = Provide
+	var _ = Private
+	var _ = Export
+	var _ = Namespace
+	var _ = WithNamespace
+	var _ = NamespacedTag
+	var _ = Require
+	var _ = Default
+	var _ = When
+	var _ = Profile
+	var _ = DeclareProfiles
+	var _ = OnProfile
+	var _ = OnEnv
+	var _ = OnSwitch
+	var _ = OnFunc
+	var _ = Not
 	var _ = Struct
 	var _ = Value
+	var _ = Supply
+	var _ = Bind
 	var _ = Field
 	var _ = IgnoreFields
 	var _ = Func
@@ -168,6 +235,15 @@ func suppressUnusedWarningDslGeneric() {
 	var _ = ByTags
 	var _ = NewTag
 	var _ = NewScope
+	var _ = NewScopeWith
+	var _ = ScopeInput
+	var _ = ScopesWithAncestors
+	var _ = FormatScopeHierarchy
+	var _ = ScopeGraph
+	var _ = Cache
+	var _ = Seed
+	var _ = NamedSeed
+	var _ = OptionalSeed
 	var _ = BuildFunc
 	var _ = BuildStruct
 	var _ = BuildValue
@@ -179,10 +255,13 @@ func suppressUnusedWarningDslGeneric() {
 	var _ = ValueOfCtx
 	var _ = EnterScopeCtx
 	var _ = LeaveScopeCtx
+	var _ = EnterScopePathCtx
+	var _ = LeaveScopeToCtx
 	var _ = TypeOfT[any]
 	var _ = TypeT[any]
 	var _ = AsT[any]
 	var _ = StructT[any]
+	var _ = BindT[any, any]
 	var _ = FuncOfT
 	var _ = StructOfT[any]
 	var _ = ValueOfT[any]
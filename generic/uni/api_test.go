@@ -1,7 +1,45 @@
 package uni
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func Test_unused(t *testing.T) {
 	suppressUnusedWarningDslGeneric()
 }
+
+type bindTestInterface interface {
+	Foo() int
+}
+
+type bindTestImpl struct{ n int }
+
+func (b *bindTestImpl) Foo() int { return b.n }
+
+func TestBindT(t *testing.T) {
+	c, err := NewContainer(NewModule(
+		Value(&bindTestImpl{n: 1}),
+		BindT[bindTestInterface, *bindTestImpl](),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	impl, err := ValueOfT[*bindTestImpl](c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iface, err := ValueOfT[bindTestInterface](c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iface != bindTestInterface(impl) {
+		t.Fatalf("%v is not the bound component %v", iface, impl)
+	}
+
+	loc := BindT[bindTestInterface, *bindTestImpl]().Provider().Location()
+	if !strings.HasSuffix(loc.FileName(), "api_test.go") {
+		t.Fatalf("unexpected location %v", loc)
+	}
+}
@@ -0,0 +1,367 @@
+package uni
+
+import (
+	"reflect"
+
+	"github.com/jison/uni/core/model"
+)
+
+// FuncOption is an option of the provider created by ProvideFunc functions as a whole, such as
+// Scope and Cache. Parameters and the returned component are set by methods of the provider,
+// which are typed by the function
+type FuncOption interface {
+	model.FuncProviderOption
+	model.StructProviderOption
+}
+
+// Implementation makes the component of type R matched by an interface it implements
+type Implementation[R any] struct {
+	iface reflect.Type
+}
+
+// Implements makes the component of type R matched by I, conv is never called but lets the
+// compiler check R implements I, such as Implements(func(c *Client) Doer { return c })
+func Implements[R, I any](conv func(R) I) Implementation[R] {
+	_ = conv
+	return Implementation[R]{iface: TypeOfT[I]()}
+}
+
+type funcProviderT[R any] struct {
+	b model.FuncProviderBuilder
+}
+
+func (p *funcProviderT[R]) ApplyModule(mb model.ModuleBuilder) {
+	p.b.ApplyModule(mb)
+}
+
+func (p *funcProviderT[R]) Provider() model.Provider {
+	return p.b.Provider()
+}
+
+func (p *funcProviderT[R]) param(index int, opts []model.DependencyOption) {
+	p.b.Param(index, opts...)
+}
+
+func (p *funcProviderT[R]) ret(opts []model.ComponentOption) {
+	p.b.Return(0, opts...)
+}
+
+func (p *funcProviderT[R]) as(impls []Implementation[R]) {
+	var ifs []model.TypeVal
+	for _, impl := range impls {
+		ifs = append(ifs, impl.iface)
+	}
+	p.b.Return(0, As(ifs...))
+}
+
+func newFuncProviderT[R any](f any, loc model.UpdateCallLocationOption, opts []FuncOption) funcProviderT[R] {
+	b := Func(f, loc)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt.ApplyFuncProvider(b)
+	}
+	return funcProviderT[R]{b: b}
+}
+
+// FuncProvider0 provides the component of type R returned by a function without parameters
+type FuncProvider0[R any] struct {
+	funcProviderT[R]
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider0[R]) Return(opts ...model.ComponentOption) *FuncProvider0[R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider0[R]) Name(name string) *FuncProvider0[R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider0[R]) As(impls ...Implementation[R]) *FuncProvider0[R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc0 provides the component returned by f, returning R and an error
+func ProvideFunc0[R any](f func() (R, error), opts ...FuncOption) *FuncProvider0[R] {
+	return &FuncProvider0[R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError0 provides the component returned by f, returning R
+func ProvideFuncNoError0[R any](f func() R, opts ...FuncOption) *FuncProvider0[R] {
+	return &FuncProvider0[R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// FuncProvider1 provides the component of type R returned by a function with a parameter of type A
+type FuncProvider1[A, R any] struct {
+	funcProviderT[R]
+}
+
+// ParamA applies options to the parameter of type A
+func (p *FuncProvider1[A, R]) ParamA(opts ...model.DependencyOption) *FuncProvider1[A, R] {
+	p.param(0, opts)
+	return p
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider1[A, R]) Return(opts ...model.ComponentOption) *FuncProvider1[A, R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider1[A, R]) Name(name string) *FuncProvider1[A, R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider1[A, R]) As(impls ...Implementation[R]) *FuncProvider1[A, R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc1 provides the component returned by f, returning R and an error
+func ProvideFunc1[A, R any](f func(A) (R, error), opts ...FuncOption) *FuncProvider1[A, R] {
+	return &FuncProvider1[A, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError1 provides the component returned by f, returning R
+func ProvideFuncNoError1[A, R any](f func(A) R, opts ...FuncOption) *FuncProvider1[A, R] {
+	return &FuncProvider1[A, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// FuncProvider2 provides the component of type R returned by a function with parameters of types A and B
+type FuncProvider2[A, B, R any] struct {
+	funcProviderT[R]
+}
+
+// ParamA applies options to the parameter of type A
+func (p *FuncProvider2[A, B, R]) ParamA(opts ...model.DependencyOption) *FuncProvider2[A, B, R] {
+	p.param(0, opts)
+	return p
+}
+
+// ParamB applies options to the parameter of type B
+func (p *FuncProvider2[A, B, R]) ParamB(opts ...model.DependencyOption) *FuncProvider2[A, B, R] {
+	p.param(1, opts)
+	return p
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider2[A, B, R]) Return(opts ...model.ComponentOption) *FuncProvider2[A, B, R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider2[A, B, R]) Name(name string) *FuncProvider2[A, B, R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider2[A, B, R]) As(impls ...Implementation[R]) *FuncProvider2[A, B, R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc2 provides the component returned by f, returning R and an error
+func ProvideFunc2[A, B, R any](f func(A, B) (R, error), opts ...FuncOption) *FuncProvider2[A, B, R] {
+	return &FuncProvider2[A, B, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError2 provides the component returned by f, returning R
+func ProvideFuncNoError2[A, B, R any](f func(A, B) R, opts ...FuncOption) *FuncProvider2[A, B, R] {
+	return &FuncProvider2[A, B, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// FuncProvider3 provides the component of type R returned by a function with parameters of types A, B and C
+type FuncProvider3[A, B, C, R any] struct {
+	funcProviderT[R]
+}
+
+// ParamA applies options to the parameter of type A
+func (p *FuncProvider3[A, B, C, R]) ParamA(opts ...model.DependencyOption) *FuncProvider3[A, B, C, R] {
+	p.param(0, opts)
+	return p
+}
+
+// ParamB applies options to the parameter of type B
+func (p *FuncProvider3[A, B, C, R]) ParamB(opts ...model.DependencyOption) *FuncProvider3[A, B, C, R] {
+	p.param(1, opts)
+	return p
+}
+
+// ParamC applies options to the parameter of type C
+func (p *FuncProvider3[A, B, C, R]) ParamC(opts ...model.DependencyOption) *FuncProvider3[A, B, C, R] {
+	p.param(2, opts)
+	return p
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider3[A, B, C, R]) Return(opts ...model.ComponentOption) *FuncProvider3[A, B, C, R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider3[A, B, C, R]) Name(name string) *FuncProvider3[A, B, C, R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider3[A, B, C, R]) As(impls ...Implementation[R]) *FuncProvider3[A, B, C, R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc3 provides the component returned by f, returning R and an error
+func ProvideFunc3[A, B, C, R any](f func(A, B, C) (R, error), opts ...FuncOption) *FuncProvider3[A, B, C, R] {
+	return &FuncProvider3[A, B, C, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError3 provides the component returned by f, returning R
+func ProvideFuncNoError3[A, B, C, R any](f func(A, B, C) R, opts ...FuncOption) *FuncProvider3[A, B, C, R] {
+	return &FuncProvider3[A, B, C, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// FuncProvider4 provides the component of type R returned by a function with parameters of types A, B, C and D
+type FuncProvider4[A, B, C, D, R any] struct {
+	funcProviderT[R]
+}
+
+// ParamA applies options to the parameter of type A
+func (p *FuncProvider4[A, B, C, D, R]) ParamA(opts ...model.DependencyOption) *FuncProvider4[A, B, C, D, R] {
+	p.param(0, opts)
+	return p
+}
+
+// ParamB applies options to the parameter of type B
+func (p *FuncProvider4[A, B, C, D, R]) ParamB(opts ...model.DependencyOption) *FuncProvider4[A, B, C, D, R] {
+	p.param(1, opts)
+	return p
+}
+
+// ParamC applies options to the parameter of type C
+func (p *FuncProvider4[A, B, C, D, R]) ParamC(opts ...model.DependencyOption) *FuncProvider4[A, B, C, D, R] {
+	p.param(2, opts)
+	return p
+}
+
+// ParamD applies options to the parameter of type D
+func (p *FuncProvider4[A, B, C, D, R]) ParamD(opts ...model.DependencyOption) *FuncProvider4[A, B, C, D, R] {
+	p.param(3, opts)
+	return p
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider4[A, B, C, D, R]) Return(opts ...model.ComponentOption) *FuncProvider4[A, B, C, D, R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider4[A, B, C, D, R]) Name(name string) *FuncProvider4[A, B, C, D, R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider4[A, B, C, D, R]) As(impls ...Implementation[R]) *FuncProvider4[A, B, C, D, R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc4 provides the component returned by f, returning R and an error
+func ProvideFunc4[A, B, C, D, R any](f func(A, B, C, D) (R, error),
+	opts ...FuncOption) *FuncProvider4[A, B, C, D, R] {
+	return &FuncProvider4[A, B, C, D, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError4 provides the component returned by f, returning R
+func ProvideFuncNoError4[A, B, C, D, R any](f func(A, B, C, D) R,
+	opts ...FuncOption) *FuncProvider4[A, B, C, D, R] {
+	return &FuncProvider4[A, B, C, D, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// FuncProvider5 provides the component of type R returned by a function with parameters of types A, B, C, D and E
+type FuncProvider5[A, B, C, D, E, R any] struct {
+	funcProviderT[R]
+}
+
+// ParamA applies options to the parameter of type A
+func (p *FuncProvider5[A, B, C, D, E, R]) ParamA(opts ...model.DependencyOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.param(0, opts)
+	return p
+}
+
+// ParamB applies options to the parameter of type B
+func (p *FuncProvider5[A, B, C, D, E, R]) ParamB(opts ...model.DependencyOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.param(1, opts)
+	return p
+}
+
+// ParamC applies options to the parameter of type C
+func (p *FuncProvider5[A, B, C, D, E, R]) ParamC(opts ...model.DependencyOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.param(2, opts)
+	return p
+}
+
+// ParamD applies options to the parameter of type D
+func (p *FuncProvider5[A, B, C, D, E, R]) ParamD(opts ...model.DependencyOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.param(3, opts)
+	return p
+}
+
+// ParamE applies options to the parameter of type E
+func (p *FuncProvider5[A, B, C, D, E, R]) ParamE(opts ...model.DependencyOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.param(4, opts)
+	return p
+}
+
+// Return applies options to the returned component of type R
+func (p *FuncProvider5[A, B, C, D, E, R]) Return(opts ...model.ComponentOption) *FuncProvider5[A, B, C, D, E, R] {
+	p.ret(opts)
+	return p
+}
+
+// Name names the returned component of type R
+func (p *FuncProvider5[A, B, C, D, E, R]) Name(name string) *FuncProvider5[A, B, C, D, E, R] {
+	p.ret([]model.ComponentOption{Name(name)})
+	return p
+}
+
+// As makes the returned component of type R matched by interfaces it implements
+func (p *FuncProvider5[A, B, C, D, E, R]) As(impls ...Implementation[R]) *FuncProvider5[A, B, C, D, E, R] {
+	p.as(impls)
+	return p
+}
+
+// ProvideFunc5 provides the component returned by f, returning R and an error
+func ProvideFunc5[A, B, C, D, E, R any](f func(A, B, C, D, E) (R, error),
+	opts ...FuncOption) *FuncProvider5[A, B, C, D, E, R] {
+	return &FuncProvider5[A, B, C, D, E, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ProvideFuncNoError5 provides the component returned by f, returning R
+func ProvideFuncNoError5[A, B, C, D, E, R any](f func(A, B, C, D, E) R,
+	opts ...FuncOption) *FuncProvider5[A, B, C, D, E, R] {
+	return &FuncProvider5[A, B, C, D, E, R]{newFuncProviderT[R](f, model.UpdateCallLocation(), opts)}
+}
+
+// ValueT provides v as a component of type T, it can be matched by T even if T is an interface
+func ValueT[T any](v T, opts ...model.ValueProviderOption) model.ValueProviderBuilder {
+	if t := TypeOfT[T](); t.Kind() == reflect.Interface {
+		opts = append(opts, As(t))
+	}
+	opts = append(opts, model.UpdateCallLocation())
+	return Value(v, opts...)
+}
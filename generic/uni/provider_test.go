@@ -0,0 +1,152 @@
+package uni
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jison/uni/core/model"
+)
+
+type providerTestConfig struct{ addr string }
+
+type providerTestClient struct {
+	conf *providerTestConfig
+	n    int
+}
+
+func TestProvideFunc(t *testing.T) {
+	newClient := func(conf *providerTestConfig, n int) (*providerTestClient, error) {
+		return &providerTestClient{conf, n}, nil
+	}
+
+	tag := NewTypedTag[string]("tag")
+	c, err := NewContainer(NewModule(
+		ProvideFunc0(func() (*providerTestConfig, error) { return &providerTestConfig{"addr"}, nil }),
+		ProvideFunc0(func() (int, error) { return 1, nil }),
+		ProvideFunc2(newClient).Name("client"),
+		ProvideFunc1(func(c *providerTestClient) (string, error) { return c.conf.addr, nil }).
+			ParamA(ByName("client")).
+			Return(Name("addr"), Tags(tag)),
+		ProvideFunc3(func(a int, s string, conf *providerTestConfig) (int8, error) {
+			return 0, errors.New("can not build int8")
+		}).ParamB(ByName("addr")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := ValueOfT[*providerTestClient](c, ByName("client"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.conf.addr != "addr" || client.n != 1 {
+		t.Fatalf("unexpected client %v", client)
+	}
+
+	addr, err := ValueOfT[string](c, ByTags(tag))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "addr" {
+		t.Fatalf("unexpected addr %v", addr)
+	}
+
+	if _, err = ValueOfT[int8](c); err == nil || !strings.Contains(err.Error(), "can not build int8") {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loc := ProvideFunc0(func() (int, error) { return 1, nil }).Provider().Location()
+	if !strings.HasSuffix(loc.FileName(), "provider_test.go") {
+		t.Fatalf("unexpected location %v", loc)
+	}
+}
+
+func TestProvideFunc_arities(t *testing.T) {
+	m := NewModule(
+		ProvideFunc0(func() (int, error) { return 1, nil }),
+		ProvideFunc4(func(a, b, c, d int) (int8, error) { return int8(a + b + c + d), nil }),
+		ProvideFunc5(func(a, b, c, d int, e int8) (string, error) {
+			return fmt.Sprint(a, b, c, d, e), nil
+		}),
+	)
+	c, err := NewContainer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ValueOfT[string](c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "1 1 1 1 4" {
+		t.Fatalf("unexpected value %v", s)
+	}
+}
+
+func TestProvideFuncNoError(t *testing.T) {
+	c, err := NewContainer(NewModule(
+		ProvideFuncNoError0(func() int { return 1 }),
+		ProvideFuncNoError1(func(n int) *bindTestImpl { return &bindTestImpl{n: n} }).
+			As(Implements(func(b *bindTestImpl) bindTestInterface { return b })),
+		ProvideFuncNoError2(func(n int, b bindTestInterface) string { return fmt.Sprint(n, b.Foo()) }),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ValueOfT[string](c)
+	if err != nil || s != "1 1" {
+		t.Fatalf("unexpected value %v %v", s, err)
+	}
+
+	loc := ProvideFuncNoError0(func() int { return 1 }).Provider().Location()
+	if !strings.HasSuffix(loc.FileName(), "provider_test.go") {
+		t.Fatalf("unexpected location %v", loc)
+	}
+}
+
+func TestProvideFunc_options(t *testing.T) {
+	scope1 := NewScope("scope1")
+	policy := CachePolicy{TTL: time.Minute}
+	p := ProvideFunc0(func() (int, error) { return 1, nil }, Scope(scope1), Cache(policy)).Provider()
+	if p.Scope() != scope1 {
+		t.Fatalf("unexpected scope %v", p.Scope())
+	}
+	if cp, ok := model.CachePolicyOf(p); !ok || cp != policy {
+		t.Fatalf("unexpected cache policy %v", cp)
+	}
+}
+
+func TestValueT(t *testing.T) {
+	c, err := NewContainer(NewModule(
+		ValueT[bindTestInterface](&bindTestImpl{n: 2}),
+		ValueT(3, Name("three")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iface, err := ValueOfT[bindTestInterface](c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iface.Foo() != 2 {
+		t.Fatalf("unexpected value %v", iface)
+	}
+
+	n, err := ValueOfT[int](c, ByName("three"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("unexpected value %v", n)
+	}
+
+	loc := ValueT(1).Provider().Location()
+	if !strings.HasSuffix(loc.FileName(), "provider_test.go") {
+		t.Fatalf("unexpected location %v", loc)
+	}
+}
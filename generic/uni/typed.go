@@ -0,0 +1,78 @@
+package uni
+
+import (
+	"fmt"
+
+	"github.com/jison/uni/core/model"
+	"github.com/jison/uni/internal/errors"
+	"github.com/jison/uni/internal/location"
+)
+
+// Tag can only tag components and dependencies of type T
+type Tag[T any] struct {
+	model.TypedSymbol
+}
+
+func (t Tag[T]) Format(f fmt.State, verb rune) {
+	if f.Flag('+') && verb == 'v' {
+		_, _ = fmt.Fprintf(f, "%+v", t.TypedSymbol)
+	} else {
+		_, _ = fmt.Fprintf(f, "%v", t.TypedSymbol)
+	}
+}
+
+// NewTypedTag creates a tag which can only tag components and dependencies of type T
+func NewTypedTag[T any](name string) Tag[T] {
+	return Tag[T]{model.NewTypedSymbol(name, TypeOfT[T](), location.GetCallLocation(1))}
+}
+
+// Named names components and dependencies, which must be of type T
+func Named[T any](name string) model.TypedNameOption {
+	return model.TypedName(name, TypeOfT[T]())
+}
+
+// SliceOfT collects values of components of type T
+func SliceOfT[T any](c Container, opts ...model.ValueConsumerOption) ([]T, error) {
+	opts = append(opts, AsCollector(true), model.UpdateCallLocation())
+	val, err := ValueOf(c, TypeOfT[[]T](), opts...)
+	return convertTo[[]T](val, err)
+}
+
+// MapOfT returns values of components of type T by their names, components without names are excluded
+func MapOfT[T any](c Container, opts ...model.ValueConsumerOption) (map[string]T, error) {
+	if c == nil {
+		return nil, errors.Newf("container is nil")
+	}
+	loc := model.UpdateCallLocation()
+
+	cri := Type(TypeOfT[T]())
+	for _, opt := range opts {
+		if o, ok := opt.(model.CriteriaOption); ok {
+			o.ApplyCriteria(cri)
+		}
+	}
+
+	res := map[string]T{}
+	errs := errors.Empty()
+	c.Components(cri).Each(func(com model.Component) {
+		if com.Name() == "" {
+			return
+		}
+		if _, ok := res[com.Name()]; ok {
+			errs = errs.AddErrorf("there are more than one component named %q", com.Name())
+			return
+		}
+		valOpts := append(append([]model.ValueConsumerOption(nil), opts...), ByName(com.Name()), loc)
+		val, err := convertTo[T](ValueOf(c, TypeOfT[T](), valOpts...))
+		if err != nil {
+			errs = errs.AddErrors(err)
+			return
+		}
+		res[com.Name()] = val
+	})
+
+	if errs.HasError() {
+		return nil, errs
+	}
+	return res, nil
+}
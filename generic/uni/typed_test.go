@@ -0,0 +1,104 @@
+package uni
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type typedTestHandler interface {
+	Path() string
+}
+
+type typedTestHandlerImpl struct{ path string }
+
+func (h *typedTestHandlerImpl) Path() string { return h.path }
+
+func TestNewTypedTag(t *testing.T) {
+	routes := NewTypedTag[typedTestHandler]("routes")
+	if fmt.Sprint(routes) != "routes" {
+		t.Fatalf("unexpected format %v", routes)
+	}
+
+	c, err := NewContainer(NewModule(
+		Value(&typedTestHandlerImpl{"/a"}, As(TypeOfT[typedTestHandler]()), Tags(routes)),
+		ValueT[typedTestHandler](&typedTestHandlerImpl{"/b"}, Tags(routes)),
+		Value(&typedTestHandlerImpl{"/c"}, As(TypeOfT[typedTestHandler]())),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handlers, err := SliceOfT[typedTestHandler](c, ByTags(routes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, h := range handlers {
+		paths = append(paths, h.Path())
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "/a,/b" {
+		t.Fatalf("unexpected handlers %v", paths)
+	}
+
+	_, err = NewContainer(NewModule(Value(1, Tags(routes))))
+	if err == nil || !strings.Contains(err.Error(), "tag routes of [uni.typedTestHandler] can not be applied") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err = SliceOfT[int](c, ByTags(routes)); err == nil {
+		t.Fatal("expected error of the tag of another type")
+	}
+}
+
+func TestNamed(t *testing.T) {
+	c, err := NewContainer(NewModule(
+		Value(1, Named[int]("one")),
+		Value(2, Named[int]("two")),
+		Value(3),
+		ProvideFunc1(func(i int) (string, error) { return fmt.Sprint(i), nil }).ParamA(Named[int]("two")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ValueOfT[string](c)
+	if err != nil || s != "2" {
+		t.Fatalf("unexpected value %v %v", s, err)
+	}
+
+	m, err := MapOfT[int](c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || m["one"] != 1 || m["two"] != 2 {
+		t.Fatalf("unexpected map %v", m)
+	}
+
+	m, err = MapOfT[int](c, ByName("two"))
+	if err != nil || len(m) != 1 || m["two"] != 2 {
+		t.Fatalf("unexpected map %v %v", m, err)
+	}
+
+	ints, err := SliceOfT[int](c)
+	if err != nil || len(ints) != 3 {
+		t.Fatalf("unexpected slice %v %v", ints, err)
+	}
+
+	_, err = NewContainer(NewModule(Value("abc", Named[int]("one"))))
+	if err == nil || !strings.Contains(err.Error(), `name "one" of [int] can not be applied`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err = MapOfT[int](nil); err == nil {
+		t.Fatal("expected error of nil container")
+	}
+	c2, err := c.Child(NewModule(Value(4, Name("one"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = MapOfT[int](c2); err == nil {
+		t.Fatal("expected error of duplicated names")
+	}
+}
@@ -1,5 +1,7 @@
 package graph
 
+import "time"
+
 // stronglyConnectedComponents
 // find strongly connected components of graph
 // see https://github.com/networkx/networkx/blob/main/networkx/algorithms/components/strongly_connected.py#L16
@@ -127,7 +129,7 @@ type stackItem struct {
 	neighbors []Node
 }
 
-func _pushStack(stack []*stackItem, g DirectedGraph, node Node) []*stackItem {
+func _pushStack(stack []*stackItem, g DirectedGraphView, node Node) []*stackItem {
 	var neighbors []Node
 	SuccessorsOf(g, node).Iterate(func(n Node, _ AttrsView) bool {
 		neighbors = append(neighbors, n)
@@ -139,9 +141,110 @@ func _pushStack(stack []*stackItem, g DirectedGraph, node Node) []*stackItem {
 	})
 }
 
-// FindCycles
-// see https://github.com/networkx/networkx/blob/main/networkx/algorithms/cycles.py#L98
+// CyclicComponents returns strongly connected components of the graph which have cycles,
+// that is components with more than one node or with a self loop
+func CyclicComponents(g DirectedGraphView) [][]Node {
+	subG := NewDirectedGraph()
+	g.Edges().Iterate(func(from Node, to Node, attrs AttrsView) bool {
+		AddEdge(subG, from, to)
+		return true
+	})
+
+	var res [][]Node
+	for _, scc := range stronglyConnectedComponents(subG) {
+		if len(scc) > 1 || HasEdge(subG, scc[0], scc[0]) {
+			res = append(res, scc)
+		}
+	}
+	return res
+}
+
+// ShortestCycleThrough returns the cycle with the fewest edges which passes through the node,
+// the node is the first one of the cycle
+func ShortestCycleThrough(g DirectedGraphView, node Node) (Cycle, bool) {
+	return ShortestCycleThroughWithin(g, node, 0)
+}
+
+// ShortestCycleThroughWithin is like ShortestCycleThrough, but only finds cycles with less than
+// maxLen nodes, a zero maxLen means no limit. The search stops at the depth of maxLen, so it is
+// cheap to look for a shorter cycle than a known one
+func ShortestCycleThroughWithin(g DirectedGraphView, node Node, maxLen int) (Cycle, bool) {
+	if !HasNode(g, node) {
+		return nil, false
+	}
+
+	parents := map[Node]Node{}
+	depths := map[Node]int{node: 1}
+	queue := []Node{node}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if maxLen > 0 && depths[n] >= maxLen {
+			break
+		}
+
+		closed := !SuccessorsOf(g, n).Iterate(func(next Node, _ AttrsView) bool {
+			if next == node {
+				return false
+			}
+			if _, ok := parents[next]; !ok {
+				parents[next] = n
+				depths[next] = depths[n] + 1
+				queue = append(queue, next)
+			}
+			return true
+		})
+		if !closed {
+			continue
+		}
+
+		var cycle Cycle
+		for cur := n; cur != node; cur = parents[cur] {
+			cycle = append(cycle, cur)
+		}
+		cycle = append(cycle, node)
+		for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+			cycle[i], cycle[j] = cycle[j], cycle[i]
+		}
+		return cycle, true
+	}
+
+	return nil, false
+}
+
+// CycleLimits bounds the search of FindCyclesWithLimits, a zero value means no limit
+type CycleLimits struct {
+	// MaxCycles is the most cycles to find
+	MaxCycles int
+	// Timeout is the longest time to search
+	Timeout time.Duration
+}
+
+// FindCycles returns all elementary cycles of the graph, the count of them can be exponential
+// to the size of the graph, see FindCyclesWithLimits
 func FindCycles(g DirectedGraphView) []Cycle {
+	cycles, _ := FindCyclesWithLimits(g, CycleLimits{})
+	return cycles
+}
+
+// FindCyclesWithLimits is like FindCycles, but it stops when the limits are reached, and returns
+// false if there may be more cycles
+// see https://github.com/networkx/networkx/blob/main/networkx/algorithms/cycles.py#L98
+func FindCyclesWithLimits(g DirectedGraphView, limits CycleLimits) ([]Cycle, bool) {
+	var deadline time.Time
+	if limits.Timeout > 0 {
+		deadline = time.Now().Add(limits.Timeout)
+	}
+	full := func(cycles []Cycle) bool {
+		return limits.MaxCycles > 0 && len(cycles) >= limits.MaxCycles
+	}
+	steps := 0
+	timeout := func() bool {
+		steps += 1
+		// checking time at every step is expensive
+		return !deadline.IsZero() && steps%1024 == 0 && time.Now().After(deadline)
+	}
+
 	subG := NewDirectedGraph()
 	g.Edges().Iterate(func(from Node, to Node, attrs AttrsView) bool {
 		AddEdge(subG, from, to)
@@ -157,13 +260,21 @@ func FindCycles(g DirectedGraphView) []Cycle {
 	}
 
 	var cycles []Cycle
+	complete := true
 	subG.Nodes().Iterate(func(node Node, _ AttrsView) bool {
 		if HasEdge(subG, node, node) {
+			if full(cycles) {
+				complete = false
+				return false
+			}
 			cycles = append(cycles, []Node{node})
 			subG.RemoveEdge(node, node)
 		}
 		return true
 	})
+	if !complete {
+		return cycles, false
+	}
 
 	for len(sccs) > 0 {
 		scc := sccs[len(sccs)-1]
@@ -181,12 +292,18 @@ func FindCycles(g DirectedGraphView) []Cycle {
 		B := make(map[Node]NodeSet)
 		stack := _pushStack([]*stackItem{}, sccG, startNode)
 		for len(stack) > 0 {
+			if timeout() {
+				return cycles, false
+			}
 			item := stack[len(stack)-1]
 
 			if len(item.neighbors) > 0 {
 				nextNode := item.neighbors[len(item.neighbors)-1]
 				item.neighbors = item.neighbors[0 : len(item.neighbors)-1]
 				if nextNode == startNode {
+					if full(cycles) {
+						return cycles, false
+					}
 					var cycle []Node
 					cycle = append(cycle, path...)
 					cycles = append(cycles, cycle)
@@ -230,5 +347,5 @@ func FindCycles(g DirectedGraphView) []Cycle {
 		}
 	}
 
-	return cycles
+	return cycles, true
 }
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -243,3 +244,107 @@ func TestFindCycles(t *testing.T) {
 		}
 	})
 }
+
+func TestCyclicComponents(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 1}, {2, 3}, {3, 4}, {4, 4}, {4, 5}, {5, 6}, {6, 7}, {7, 5}})
+
+	var got []map[Node]struct{}
+	for _, scc := range CyclicComponents(g) {
+		set := map[Node]struct{}{}
+		for _, n := range scc {
+			set[n] = struct{}{}
+		}
+		got = append(got, set)
+	}
+	assert.ElementsMatch(t, []map[Node]struct{}{
+		{1: {}, 2: {}}, {4: {}}, {5: {}, 6: {}, 7: {}},
+	}, got)
+
+	assert.Empty(t, CyclicComponents(newGraphWithEdges([][2]Node{{1, 2}, {2, 3}})))
+}
+
+func TestShortestCycleThrough(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 3}, {3, 1}, {1, 4}, {4, 1}, {5, 5}, {3, 6}})
+
+	cycle, ok := ShortestCycleThrough(g, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Cycle{1, 4}, cycle)
+
+	cycle, ok = ShortestCycleThrough(g, 2)
+	assert.True(t, ok)
+	assert.Equal(t, Cycle{2, 3, 1}, cycle)
+
+	cycle, ok = ShortestCycleThrough(g, 5)
+	assert.True(t, ok)
+	assert.Equal(t, Cycle{5}, cycle)
+
+	_, ok = ShortestCycleThrough(g, 6)
+	assert.False(t, ok)
+	_, ok = ShortestCycleThrough(g, 7)
+	assert.False(t, ok)
+}
+
+func TestShortestCycleThroughWithin(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 3}, {3, 1}, {1, 4}, {4, 1}})
+
+	cycle, ok := ShortestCycleThroughWithin(g, 2, 4)
+	assert.True(t, ok)
+	assert.Equal(t, Cycle{2, 3, 1}, cycle)
+
+	_, ok = ShortestCycleThroughWithin(g, 2, 3)
+	assert.False(t, ok)
+
+	cycle, ok = ShortestCycleThroughWithin(g, 1, 3)
+	assert.True(t, ok)
+	assert.Equal(t, Cycle{1, 4}, cycle)
+}
+
+// completeGraph returns a graph with edges between every two nodes, it has an exponential
+// number of elementary cycles
+func completeGraph(n int) DirectedGraph {
+	g := NewDirectedGraph()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				AddEdge(g, i, j)
+			}
+		}
+	}
+	return g
+}
+
+func TestFindCyclesWithLimits(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		cycles, complete := FindCyclesWithLimits(completeGraph(4), CycleLimits{})
+		assert.True(t, complete)
+		// 6 cycles of 2 nodes, 8 of 3 nodes and 6 of 4 nodes
+		assert.Len(t, cycles, 20)
+	})
+
+	t.Run("max cycles", func(t *testing.T) {
+		g := completeGraph(12)
+		AddEdge(g, 100, 100)
+		cycles, complete := FindCyclesWithLimits(g, CycleLimits{MaxCycles: 50})
+		assert.False(t, complete)
+		assert.Len(t, cycles, 50)
+
+		cycles, complete = FindCyclesWithLimits(completeGraph(4), CycleLimits{MaxCycles: 20})
+		assert.True(t, complete)
+		assert.Len(t, cycles, 20)
+	})
+
+	t.Run("self loops over max cycles", func(t *testing.T) {
+		g := newGraphWithEdges([][2]Node{{1, 1}, {2, 2}, {3, 3}})
+		cycles, complete := FindCyclesWithLimits(g, CycleLimits{MaxCycles: 2})
+		assert.False(t, complete)
+		assert.Len(t, cycles, 2)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		cycles, complete := FindCyclesWithLimits(completeGraph(30), CycleLimits{Timeout: 50 * time.Millisecond})
+		assert.False(t, complete)
+		assert.NotEmpty(t, cycles)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned by algorithms which require the graph to be acyclic
+type CycleError struct {
+	Cycle Cycle
+}
+
+func (e *CycleError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("graph has a cycle: ")
+	for _, node := range e.Cycle {
+		sb.WriteString(fmt.Sprintf("%v -> ", node))
+	}
+	if len(e.Cycle) > 0 {
+		sb.WriteString(fmt.Sprintf("%v", e.Cycle[0]))
+	}
+	return sb.String()
+}
+
+// TopologicalSort returns nodes of the graph in an order that for every edge, `from` is before `to`.
+// If the graph has cycles, a *CycleError with one of them is returned.
+func TopologicalSort(g DirectedGraphView) ([]Node, error) {
+	inDegrees := map[Node]int{}
+	var queue []Node
+	g.Nodes().Iterate(func(node Node, _ AttrsView) bool {
+		degree := 0
+		PredecessorsOf(g, node).Iterate(func(_ Node, _ AttrsView) bool {
+			degree += 1
+			return true
+		})
+		inDegrees[node] = degree
+		if degree == 0 {
+			queue = append(queue, node)
+		}
+		return true
+	})
+
+	sorted := make([]Node, 0, len(inDegrees))
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, node)
+
+		SuccessorsOf(g, node).Iterate(func(suc Node, _ AttrsView) bool {
+			inDegrees[suc] -= 1
+			if inDegrees[suc] == 0 {
+				queue = append(queue, suc)
+			}
+			return true
+		})
+	}
+
+	if len(sorted) < len(inDegrees) {
+		return nil, &CycleError{Cycle: cycleIn(g, inDegrees)}
+	}
+	return sorted, nil
+}
+
+// cycleIn finds a cycle in nodes whose remaining in-degrees are positive, every such node
+// has a predecessor in them, so walking back along predecessors must meet a cycle
+func cycleIn(g DirectedGraphView, inDegrees map[Node]int) Cycle {
+	var start Node
+	for node, degree := range inDegrees {
+		if degree > 0 {
+			start = node
+			break
+		}
+	}
+
+	visitedAt := map[Node]int{}
+	var walk []Node
+	for node := start; ; {
+		if i, ok := visitedAt[node]; ok {
+			cycle := walk[i:]
+			// the walk goes against edges
+			for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+				cycle[l], cycle[r] = cycle[r], cycle[l]
+			}
+			return cycle
+		}
+		visitedAt[node] = len(walk)
+		walk = append(walk, node)
+
+		PredecessorsOf(g, node).Iterate(func(pre Node, _ AttrsView) bool {
+			if inDegrees[pre] > 0 {
+				node = pre
+				return false
+			}
+			return true
+		})
+	}
+}
+
+// TransitiveReduction returns a new graph with all nodes of g and edges of g which are the only
+// path between their nodes. The graph must be acyclic, otherwise a *CycleError is returned.
+func TransitiveReduction(g DirectedGraphView) (DirectedGraph, error) {
+	sorted, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	reduced := NewDirectedGraph()
+	g.Nodes().Iterate(func(node Node, attrs AttrsView) bool {
+		reduced.AddNodeWithAttrs(node, attrs)
+		return true
+	})
+
+	// descendants of nodes later in the order are computed first
+	descendants := make(map[Node]NodeSet, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		node := sorted[i]
+		indirect := NodeSet{}
+		SuccessorsOf(g, node).Iterate(func(suc Node, _ AttrsView) bool {
+			for d := range descendants[suc] {
+				indirect.Add(d)
+			}
+			return true
+		})
+
+		all := NodeSet{}
+		g.OutEdgesOf(node).Iterate(func(from Node, to Node, attrs AttrsView) bool {
+			all.Add(to)
+			if !indirect.Has(to) {
+				reduced.AddEdgeWithAttrs(from, to, attrs)
+			}
+			return true
+		})
+		for d := range indirect {
+			all.Add(d)
+		}
+		descendants[node] = all
+	}
+
+	return reduced, nil
+}
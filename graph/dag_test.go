@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	t.Run("acyclic", func(t *testing.T) {
+		g := newGraphWithEdges([][2]Node{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}, {6, 5}})
+		AddNodes(g, 7)
+
+		sorted, err := TopologicalSort(g)
+		assert.Nil(t, err)
+		assert.Len(t, sorted, 7)
+
+		index := map[Node]int{}
+		for i, node := range sorted {
+			index[node] = i
+		}
+		g.Edges().Iterate(func(from Node, to Node, _ AttrsView) bool {
+			assert.Less(t, index[from], index[to], "%v -> %v", from, to)
+			return true
+		})
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		sorted, err := TopologicalSort(NewDirectedGraph())
+		assert.Nil(t, err)
+		assert.Empty(t, sorted)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		g := newGraphWithEdges([][2]Node{{0, 1}, {1, 2}, {2, 3}, {3, 1}, {3, 4}})
+
+		sorted, err := TopologicalSort(g)
+		assert.Nil(t, sorted)
+		var cycleErr *CycleError
+		assert.True(t, errors.As(err, &cycleErr))
+		assert.Len(t, cycleErr.Cycle, 3)
+		for i, node := range cycleErr.Cycle {
+			next := cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]
+			assert.True(t, HasEdge(g, node, next), "%v -> %v", node, next)
+		}
+	})
+
+	t.Run("self loop", func(t *testing.T) {
+		g := newGraphWithEdges([][2]Node{{1, 2}, {2, 2}})
+
+		_, err := TopologicalSort(g)
+		assert.Equal(t, &CycleError{Cycle: Cycle{2}}, err)
+		assert.Equal(t, "graph has a cycle: 2 -> 2", err.Error())
+	})
+}
+
+func TestCycleError_Error(t *testing.T) {
+	assert.Equal(t, "graph has a cycle: 1 -> 2 -> 3 -> 1", (&CycleError{Cycle: Cycle{1, 2, 3}}).Error())
+	assert.Equal(t, "graph has a cycle: ", (&CycleError{}).Error())
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	t.Run("acyclic", func(t *testing.T) {
+		g := NewDirectedGraph()
+		AddEdges(g, [][2]Node{{1, 2}, {1, 3}, {1, 4}, {2, 4}, {3, 4}, {4, 5}, {1, 5}, {2, 5}})
+		g.AddEdgeWithAttrs(4, 5, Attrs{"k": "v"})
+		g.AddNodeWithAttrs(6, Attrs{"n": 6})
+
+		reduced, err := TransitiveReduction(g)
+		assert.Nil(t, err)
+
+		expected := NewDirectedGraph()
+		AddEdges(expected, [][2]Node{{1, 2}, {1, 3}, {2, 4}, {3, 4}})
+		expected.AddEdgeWithAttrs(4, 5, Attrs{"k": "v"})
+		expected.AddNodeWithAttrs(6, Attrs{"n": 6})
+		assert.Equal(t, NodesWithAttrsFrom(expected.Nodes()), NodesWithAttrsFrom(reduced.Nodes()))
+		assert.Equal(t, EdgesWithAttrsFrom(expected.Edges()), EdgesWithAttrsFrom(reduced.Edges()))
+
+		// the source graph is not changed
+		assert.True(t, HasEdge(g, 1, 5))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		g := newGraphWithEdges([][2]Node{{1, 2}, {2, 1}})
+
+		reduced, err := TransitiveReduction(g)
+		assert.Nil(t, reduced)
+		assert.IsType(t, &CycleError{}, err)
+	})
+}
@@ -0,0 +1,80 @@
+package graph
+
+// Dominators returns the immediate dominator of each node reachable from start. A node d
+// dominates n if every path from start to n goes through d, and the immediate dominator of n
+// is its closest dominator other than itself. The immediate dominator of start is start.
+// see https://github.com/networkx/networkx/blob/main/networkx/algorithms/dominance.py#L14
+func Dominators(g DirectedGraphView, start Node) map[Node]Node {
+	if !HasNode(g, start) {
+		return map[Node]Node{}
+	}
+
+	order := postorderFrom(g, start)
+	index := make(map[Node]int, len(order))
+	for i, node := range order {
+		index[node] = i
+	}
+
+	idom := map[Node]Node{start: start}
+	intersect := func(a, b Node) Node {
+		for a != b {
+			for index[a] < index[b] {
+				a = idom[a]
+			}
+			for index[b] < index[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		// reverse postorder without start
+		for i := len(order) - 2; i >= 0; i-- {
+			node := order[i]
+			var newIdom Node
+			PredecessorsOf(g, node).Iterate(func(pre Node, _ AttrsView) bool {
+				if _, ok := idom[pre]; !ok {
+					return true
+				}
+				if newIdom == nil {
+					newIdom = pre
+				} else {
+					newIdom = intersect(pre, newIdom)
+				}
+				return true
+			})
+			if idom[node] != newIdom {
+				idom[node] = newIdom
+				changed = true
+			}
+		}
+	}
+
+	return idom
+}
+
+// postorderFrom returns nodes reachable from start in depth-first postorder, start is the last
+func postorderFrom(g DirectedGraphView, start Node) []Node {
+	var order []Node
+	visited := NodeSet{}
+	visited.Add(start)
+	stack := _pushStack(nil, g, start)
+	for len(stack) > 0 {
+		item := stack[len(stack)-1]
+		if len(item.neighbors) > 0 {
+			next := item.neighbors[len(item.neighbors)-1]
+			item.neighbors = item.neighbors[0 : len(item.neighbors)-1]
+			if !visited.Has(next) {
+				visited.Add(next)
+				stack = _pushStack(stack, g, next)
+			}
+			continue
+		}
+		order = append(order, item.node)
+		stack = stack[0 : len(stack)-1]
+	}
+	return order
+}
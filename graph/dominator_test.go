@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDominators(t *testing.T) {
+	tests := []struct {
+		name  string
+		edges [][2]Node
+		start Node
+		idom  map[Node]Node
+	}{
+		{
+			"diamond",
+			[][2]Node{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}},
+			1,
+			map[Node]Node{1: 1, 2: 1, 3: 1, 4: 1, 5: 4},
+		},
+		{
+			"loops",
+			// see Cooper, Harvey and Kennedy, A Simple, Fast Dominance Algorithm, figure 4
+			[][2]Node{{6, 5}, {6, 4}, {5, 1}, {4, 2}, {4, 3}, {1, 2}, {2, 1}, {2, 3}, {3, 2}},
+			6,
+			map[Node]Node{6: 6, 5: 6, 4: 6, 1: 6, 2: 6, 3: 6},
+		},
+		{
+			"chain with unreachable nodes",
+			[][2]Node{{0, 1}, {1, 2}, {2, 3}, {4, 3}, {3, 1}},
+			1,
+			map[Node]Node{1: 1, 2: 1, 3: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGraphWithEdges(tt.edges)
+			assert.Equal(t, tt.idom, Dominators(g, tt.start))
+		})
+	}
+
+	t.Run("start not in graph", func(t *testing.T) {
+		assert.Equal(t, map[Node]Node{}, Dominators(NewDirectedGraph(), 1))
+	})
+}
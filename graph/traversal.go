@@ -0,0 +1,84 @@
+package graph
+
+// Descendants returns nodes reachable from node, excluding node itself
+func Descendants(g DirectedGraphView, node Node) NodeSet {
+	return reachableFrom(node, func(n Node) NodeAndAttrsIterator { return SuccessorsOf(g, n) })
+}
+
+// Ancestors returns nodes which can reach node, excluding node itself
+func Ancestors(g DirectedGraphView, node Node) NodeSet {
+	return reachableFrom(node, func(n Node) NodeAndAttrsIterator { return PredecessorsOf(g, n) })
+}
+
+func reachableFrom(node Node, directionFunc func(Node) NodeAndAttrsIterator) NodeSet {
+	visited := NodeSet{}
+	if !canBeNode(node) {
+		return visited
+	}
+
+	stack := []Node{node}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[0 : len(stack)-1]
+		directionFunc(n).Iterate(func(next Node, _ AttrsView) bool {
+			if !visited.Has(next) {
+				visited.Add(next)
+				stack = append(stack, next)
+			}
+			return true
+		})
+	}
+
+	visited.Del(node)
+	return visited
+}
+
+// Reachable returns true if there is a path from `from` to `to`, a node is reachable from itself
+func Reachable(g DirectedGraphView, from Node, to Node) bool {
+	_, ok := ShortestPath(g, from, to)
+	return ok
+}
+
+// ShortestPath returns the path with the fewest edges from `from` to `to`, including both ends
+func ShortestPath(g DirectedGraphView, from Node, to Node) ([]Node, bool) {
+	if !HasNode(g, from) || !HasNode(g, to) {
+		return nil, false
+	}
+	if from == to {
+		return []Node{from}, true
+	}
+
+	parents := map[Node]Node{from: nil}
+	queue := []Node{from}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		found := !SuccessorsOf(g, n).Iterate(func(next Node, _ AttrsView) bool {
+			if _, ok := parents[next]; ok {
+				return true
+			}
+			parents[next] = n
+			if next == to {
+				return false
+			}
+			queue = append(queue, next)
+			return true
+		})
+		if !found {
+			continue
+		}
+
+		var path []Node
+		for cur := to; cur != from; cur = parents[cur] {
+			path = append(path, cur)
+		}
+		path = append(path, from)
+		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+		}
+		return path, true
+	}
+
+	return nil, false
+}
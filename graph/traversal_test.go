@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGraphWithEdges(edges [][2]Node) DirectedGraph {
+	g := NewDirectedGraph()
+	AddEdges(g, edges)
+	return g
+}
+
+func TestDescendants(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {5, 4}})
+	AddNodes(g, 6)
+
+	assert.Equal(t, NodeSet{2: {}, 3: {}, 4: {}}, Descendants(g, 1))
+	assert.Equal(t, NodeSet{4: {}}, Descendants(g, 5))
+	assert.Equal(t, NodeSet{}, Descendants(g, 4))
+	assert.Equal(t, NodeSet{}, Descendants(g, 6))
+	assert.Equal(t, NodeSet{}, Descendants(g, 7))
+	assert.Equal(t, NodeSet{}, Descendants(g, nil))
+}
+
+func TestAncestors(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {5, 4}})
+
+	assert.Equal(t, NodeSet{1: {}, 2: {}, 3: {}, 5: {}}, Ancestors(g, 4))
+	assert.Equal(t, NodeSet{1: {}, 2: {}}, Ancestors(g, 3))
+	assert.Equal(t, NodeSet{}, Ancestors(g, 5))
+}
+
+func TestShortestPath(t *testing.T) {
+	g := newGraphWithEdges([][2]Node{{1, 2}, {2, 3}, {3, 4}, {1, 5}, {5, 4}, {4, 1}, {6, 7}})
+
+	tests := []struct {
+		name string
+		from Node
+		to   Node
+		path []Node
+		ok   bool
+	}{
+		{"shorter branch", 1, 4, []Node{1, 5, 4}, true},
+		{"through cycle", 2, 5, []Node{2, 3, 4, 1, 5}, true},
+		{"self", 3, 3, []Node{3}, true},
+		{"adjacent", 6, 7, []Node{6, 7}, true},
+		{"unreachable", 1, 6, nil, false},
+		{"against edge", 7, 6, nil, false},
+		{"not in graph", 1, 8, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := ShortestPath(g, tt.from, tt.to)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.ok, Reachable(g, tt.from, tt.to))
+		})
+	}
+}
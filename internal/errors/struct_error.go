@@ -16,6 +16,10 @@ type StructError interface {
 	WithMainf(format string, a ...interface{}) StructError
 	AddErrors(errs ...error) StructError
 	AddErrorf(format string, a ...interface{}) StructError
+	// Main returns the main error, which may be nil
+	Main() error
+	// Errors returns sub errors under the main error
+	Errors() []error
 }
 
 type structError struct {
@@ -207,7 +211,27 @@ func (e *structError) AddErrorf(format string, a ...interface{}) StructError {
 	return e.AddErrors(err)
 }
 
+func (e *structError) Main() error {
+	if e == nil {
+		return nil
+	}
+	return e.mainError
+}
+
+func (e *structError) Errors() []error {
+	if e == nil {
+		return nil
+	}
+	return append([]error{}, e.subErrors...)
+}
+
+// Is and As walk the tree themselves, so they work on direct calls and before Go 1.20, when
+// errors.Is and errors.As do not follow Unwrap() []error. Sub trees are not checked by errors.Is
+// again, otherwise a miss would cost exponential time in deep trees
 func (e *structError) Is(err error) bool {
+	if e == nil {
+		return false
+	}
 	if e2, ok := err.(*structError); ok {
 		if errors.Is(e, e2.mainError) {
 			return true
@@ -222,20 +246,15 @@ func (e *structError) Is(err error) bool {
 		return false
 	}
 
-	if e.mainError != nil && errors.Is(e.mainError, err) {
-		return true
-	}
-
-	for _, subErr := range e.subErrors {
-		if errors.Is(subErr, err) {
-			return true
-		}
-	}
-
-	return false
+	return !e.walkLeaves(func(leaf error) bool {
+		return !errors.Is(leaf, err)
+	})
 }
 
 func (e *structError) As(target interface{}) bool {
+	if e == nil {
+		return false
+	}
 	if t, ok := target.(**structError); ok {
 		*t = e
 		return true
@@ -245,35 +264,28 @@ func (e *structError) As(target interface{}) bool {
 		return true
 	}
 
-	//goland:noinspection GoErrorsAs
-	if errors.As(e.mainError, target) {
-		return true
-	}
-
-	for _, err := range e.subErrors {
+	return !e.walkLeaves(func(leaf error) bool {
 		//goland:noinspection GoErrorsAs
-		if errors.As(err, target) {
-			return true
-		}
-	}
-
-	return false
+		return !errors.As(leaf, target)
+	})
 }
 
-func (e *structError) Unwrap() error {
-	if e == nil {
-		return nil
-	}
-
+// walkLeaves calls f with errors in the tree which are not struct errors, until f returns false
+func (e *structError) walkLeaves(f func(error) bool) bool {
+	var errs []error
 	if e.mainError != nil {
-		return e.mainError
+		errs = append(errs, e.mainError)
 	}
-
-	for _, err := range e.subErrors {
-		return err
+	for _, err := range append(errs, e.subErrors...) {
+		if sub, ok := err.(*structError); ok {
+			if sub != nil && !sub.walkLeaves(f) {
+				return false
+			}
+		} else if !f(err) {
+			return false
+		}
 	}
-
-	return nil
+	return true
 }
 
 func Empty() StructError {
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -305,30 +307,71 @@ func (t *testError) Error() string {
 	return t.msg
 }
 
-func TestStructError_Unwrap(t *testing.T) {
-	t.Run("have main", func(t *testing.T) {
-		e := Newf("abc")
-		e2 := &structError{
-			mainError: e,
-		}
-		assert.Same(t, e, e2.Unwrap())
-	})
+func TestStructError_MainAndErrors(t *testing.T) {
+	e1 := Newf("abc")
+	e2 := Newf("def")
+	e := New(e1).AddErrors(e2)
+	assert.Same(t, e1, e.Main())
+	assert.Equal(t, []error{e2}, e.Errors())
 
-	t.Run("have sub", func(t *testing.T) {
-		e := Newf("abc")
-		e2 := &structError{
-			subErrors: []error{e},
-		}
-		assert.Same(t, e, e2.Unwrap())
-	})
+	assert.Nil(t, Empty().Main())
+	assert.Nil(t, Empty().Errors())
+}
 
-	t.Run("empty", func(t *testing.T) {
-		e := &structError{}
-		assert.Nil(t, e.Unwrap())
-	})
+type customError struct {
+	msg string
+}
 
-	t.Run("nil", func(t *testing.T) {
-		var e *structError
-		assert.Nil(t, e.Unwrap())
-	})
+func (e *customError) Error() string {
+	return e.msg
+}
+
+func TestStructError_stdWrapping(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	custom := &customError{msg: "custom"}
+	e := Newf("a").AddErrors(
+		Newf("b"),
+		Newf("c").AddErrors(fmt.Errorf("d: %w", sentinel), custom),
+	)
+
+	assert.True(t, errors.Is(e, sentinel))
+	assert.False(t, errors.Is(e, errors.New("sentinel")))
+
+	var target *customError
+	assert.True(t, errors.As(e, &target))
+	assert.Same(t, custom, target)
+}
+
+func TestStructError_directIsAs(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	custom := &customError{msg: "custom"}
+	e := Newf("a").AddErrors(
+		Newf("b"),
+		Newf("c").AddErrors(Newf("d").AddErrors(fmt.Errorf("e: %w", sentinel)), custom),
+	).(*structError)
+
+	// descendants are checked by the methods, not only direct children
+	assert.True(t, e.Is(sentinel))
+	assert.False(t, e.Is(errors.New("sentinel")))
+
+	var target *customError
+	assert.True(t, e.As(&target))
+	assert.Same(t, custom, target)
+
+	var notFound *testError
+	assert.False(t, e.As(&notFound))
+}
+
+func TestStructError_deepTree(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	var e error = sentinel
+	for i := 0; i < 64; i++ {
+		e = Newf("level %v", i).AddErrors(Newf("sibling %v", i), e)
+	}
+
+	assert.True(t, errors.Is(e, sentinel))
+	assert.False(t, errors.Is(e, errors.New("other")))
+
+	var target *customError
+	assert.False(t, errors.As(e, &target))
 }
@@ -0,0 +1,17 @@
+//go:build go1.20 && !unilegacyerrors
+
+package errors
+
+// Unwrap returns the main error and sub errors, so the tree participates in the multi-error wrapping
+// of Go 1.20
+func (e *structError) Unwrap() []error {
+	if e == nil {
+		return nil
+	}
+
+	var errs []error
+	if e.mainError != nil {
+		errs = append(errs, e.mainError)
+	}
+	return append(errs, e.subErrors...)
+}
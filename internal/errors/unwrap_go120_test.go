@@ -0,0 +1,65 @@
+//go:build go1.20 && !unilegacyerrors
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructError_Unwrap(t *testing.T) {
+	t.Run("have main", func(t *testing.T) {
+		e := Newf("abc")
+		e2 := &structError{
+			mainError: e,
+		}
+		assert.Equal(t, []error{e}, e2.Unwrap())
+	})
+
+	t.Run("have main and sub", func(t *testing.T) {
+		e := Newf("abc")
+		e2 := Newf("def")
+		e3 := &structError{
+			mainError: e,
+			subErrors: []error{e2},
+		}
+		assert.Equal(t, []error{e, e2}, e3.Unwrap())
+	})
+
+	t.Run("have sub", func(t *testing.T) {
+		e := Newf("abc")
+		e2 := &structError{
+			subErrors: []error{e},
+		}
+		assert.Equal(t, []error{e}, e2.Unwrap())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		e := &structError{}
+		assert.Nil(t, e.Unwrap())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var e *structError
+		assert.Nil(t, e.Unwrap())
+	})
+}
+
+func TestStructError_Unwrap_tree(t *testing.T) {
+	custom := &customError{msg: "custom"}
+	e := Newf("a").AddErrors(Newf("b"), Newf("c").AddErrors(custom))
+
+	var joined []error
+	var walk func(err error)
+	walk = func(err error) {
+		joined = append(joined, err)
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, sub := range u.Unwrap() {
+				walk(sub)
+			}
+		}
+	}
+	walk(e)
+	assert.Contains(t, joined, error(custom))
+}
@@ -0,0 +1,20 @@
+//go:build !go1.20 || unilegacyerrors
+
+package errors
+
+// Unwrap returns the main error, or the first sub error if there is no main error, since
+// errors.Unwrap before Go 1.20 only follows Unwrap() error. Is and As walk the whole tree.
+// The build tag unilegacyerrors selects it in later versions to test it
+func (e *structError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.mainError != nil {
+		return e.mainError
+	}
+	if len(e.subErrors) > 0 {
+		return e.subErrors[0]
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build !go1.20 || unilegacyerrors
+
+package errors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructError_Unwrap(t *testing.T) {
+	t.Run("have main", func(t *testing.T) {
+		e := Newf("abc")
+		e2 := Newf("def")
+		assert.Same(t, e, errors.Unwrap(New(e).AddErrors(e2)))
+	})
+
+	t.Run("have sub", func(t *testing.T) {
+		e := Newf("abc")
+		e2 := Newf("def")
+		assert.Same(t, e, errors.Unwrap(Empty().AddErrors(e, e2)))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, (&structError{}).Unwrap())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var e *structError
+		assert.Nil(t, e.Unwrap())
+	})
+}
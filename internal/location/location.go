@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type Location interface {
@@ -20,18 +22,78 @@ type CallLocation interface {
 	Callee() Location
 }
 
+// disabled is not 0 if locations of calls are not captured
+var disabled int32
+
+// SetEnabled enables or disables capturing locations of calls, GetCallLocation and GetLocation
+// return nil if it is disabled
+func SetEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&disabled, 0)
+	} else {
+		atomic.StoreInt32(&disabled, 1)
+	}
+}
+
+func Enabled() bool {
+	return atomic.LoadInt32(&disabled) == 0
+}
+
+// location is symbolized lazily, only the pc is kept until the file or the function is needed
 type location struct {
+	pc uintptr
+	// entry is true if pc is the entry of a function, otherwise it is a return address of a call
+	entry bool
+
+	once     sync.Once
 	pkgName  string
 	funcName string
 	fileName string
 	fileLine int
 }
 
+func (l *location) resolve() {
+	l.once.Do(func() {
+		if l.pc == 0 {
+			// symbolized already
+			return
+		}
+		var fullName string
+		if l.entry {
+			fn := runtime.FuncForPC(l.pc)
+			if fn == nil {
+				return
+			}
+			fullName = fn.Name()
+			l.fileName, l.fileLine = fn.FileLine(l.pc)
+		} else {
+			frame, _ := runtime.CallersFrames([]uintptr{l.pc}).Next()
+			fullName, l.fileName, l.fileLine = frame.Function, frame.File, frame.Line
+		}
+		l.pkgName, l.funcName = splitFullName(fullName)
+	})
+}
+
+func splitFullName(fullName string) (string, string) {
+	lastSlash := strings.LastIndexByte(fullName, '/')
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	dot := strings.IndexByte(fullName[lastSlash:], '.')
+	if dot < 0 {
+		return "", fullName
+	}
+	firstDotAfterLastSlash := dot + lastSlash
+	return fullName[:firstDotAfterLastSlash], fullName[firstDotAfterLastSlash+1:]
+}
+
 func (l *location) PkgName() string {
+	l.resolve()
 	return l.pkgName
 }
 
 func (l *location) FuncName() string {
+	l.resolve()
 	return l.funcName
 }
 
@@ -40,18 +102,20 @@ func (l *location) FullName() string {
 }
 
 func (l *location) FileName() string {
+	l.resolve()
 	return l.fileName
 }
 
 func (l *location) FileLine() int {
+	l.resolve()
 	return l.fileLine
 }
 
 func (l *location) Format(f fmt.State, verb rune) {
 	if f.Flag('+') && verb == 'v' {
-		_, _ = fmt.Fprintf(f, "%v.%v (%v:%d)", l.pkgName, l.funcName, l.fileName, l.fileLine)
+		_, _ = fmt.Fprintf(f, "%v.%v (%v:%d)", l.PkgName(), l.FuncName(), l.FileName(), l.FileLine())
 	} else {
-		_, _ = fmt.Fprintf(f, "%v:%d", l.fileName, l.fileLine)
+		_, _ = fmt.Fprintf(f, "%v:%d", l.FileName(), l.FileLine())
 	}
 }
 
@@ -60,15 +124,15 @@ func (l *location) String() string {
 }
 
 type callLocation struct {
-	location
-	callee Location
+	*location
+	callee *location
 }
 
 func (cl *callLocation) Format(f fmt.State, verb rune) {
 	if f.Flag('+') && verb == 'v' {
-		_, _ = fmt.Fprintf(f, "call %v at %+v", cl.Callee().FullName(), &cl.location)
+		_, _ = fmt.Fprintf(f, "call %v at %+v", cl.Callee().FullName(), cl.location)
 	} else {
-		_, _ = fmt.Fprintf(f, "call %v at %v", cl.Callee().FullName(), &cl.location)
+		_, _ = fmt.Fprintf(f, "call %v at %v", cl.Callee().FullName(), cl.location)
 	}
 }
 
@@ -80,48 +144,68 @@ func (cl *callLocation) Callee() Location {
 	return cl.callee
 }
 
+type pcKey struct {
+	pc    uintptr
+	entry bool
+}
+
+// locations and callLocations cache locations by pcs, so repeated calls at the same site share one
+// location, and it is symbolized at most once
+var (
+	locations     sync.Map // map[pcKey]*location
+	callLocations sync.Map // map[[2]uintptr]*callLocation
+)
+
+func locationOfPC(pc uintptr, entry bool) *location {
+	key := pcKey{pc: pc, entry: entry}
+	if l, ok := locations.Load(key); ok {
+		return l.(*location)
+	}
+	l, _ := locations.LoadOrStore(key, &location{pc: pc, entry: entry})
+	return l.(*location)
+}
+
+// GetCallLocation returns the location of the call of the function skip frames up, 0 means the
+// caller of GetCallLocation, the callee is the function
 func GetCallLocation(skip int) CallLocation {
-	pc, _, _, ok := runtime.Caller(skip + 1)
-	if !ok {
+	if !Enabled() {
 		return nil
 	}
 
-	callLoc := getFuncPtrLocation(pc)
-
-	var calleeLoc Location
-	var calleePc uintptr
-	calleePc, _, _, ok = runtime.Caller(skip)
-	if ok {
-		calleeLoc = getFuncPtrLocation(calleePc)
+	// pcs[0] is the callee, and pcs[1] is its caller
+	var pcs [2]uintptr
+	if runtime.Callers(skip+1, pcs[:]) < 2 {
+		return nil
 	}
 
-	return &callLocation{
-		location: *callLoc,
-		callee:   calleeLoc,
+	if cl, ok := callLocations.Load(pcs); ok {
+		return cl.(*callLocation)
 	}
+	cl, _ := callLocations.LoadOrStore(pcs, &callLocation{
+		location: locationOfPC(pcs[1], false),
+		callee:   locationOfPC(pcs[0], false),
+	})
+	return cl.(*callLocation)
 }
 
-func GetFuncLocation(f interface{}) Location {
-	val := reflect.ValueOf(f)
-	if val.Kind() != reflect.Func {
+// GetLocation is like GetCallLocation(skip).Callee(), but only one frame is captured
+func GetLocation(skip int) Location {
+	if !Enabled() {
 		return nil
 	}
 
-	return getFuncPtrLocation(val.Pointer())
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) < 1 {
+		return nil
+	}
+	return locationOfPC(pcs[0], false)
 }
 
-func getFuncPtrLocation(funcPtr uintptr) *location {
-	outside := runtime.FuncForPC(funcPtr)
-	fileName, fileLine := outside.FileLine(funcPtr)
-	fullName := outside.Name()
-	lastSlash := strings.LastIndexByte(fullName, '/')
-	if lastSlash < 0 {
-		lastSlash = 0
+func GetFuncLocation(f interface{}) Location {
+	val := reflect.ValueOf(f)
+	if val.Kind() != reflect.Func || val.IsNil() {
+		return nil
 	}
-	firstDotAfterLastSlash := strings.IndexByte(fullName[lastSlash:], '.') + lastSlash
-
-	pkgName := fullName[:firstDotAfterLastSlash]
-	funcName := fullName[firstDotAfterLastSlash+1:]
 
-	return &location{pkgName, funcName, fileName, fileLine}
+	return locationOfPC(val.Pointer(), true)
 }
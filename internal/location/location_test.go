@@ -2,6 +2,7 @@ package location
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -204,7 +205,7 @@ func TestGetCallerLocation(t *testing.T) {
 
 func TestCallLocation_Format(t *testing.T) {
 	cl := &callLocation{
-		location: location{
+		location: &location{
 			pkgName:  "this/is/package",
 			funcName: "i_am_function",
 			fileName: "fileName.go",
@@ -225,3 +226,55 @@ func TestCallLocation_Format(t *testing.T) {
 	assert.Equal(t, "call callee/package.callee_function at this/is/package.i_am_function (fileName.go:7)",
 		fmt.Sprintf("%+v", cl))
 }
+
+func TestGetCallLocation_cache(t *testing.T) {
+	var locs []CallLocation
+	for i := 0; i < 2; i++ {
+		locs = append(locs, GetCallLocationFromExportedFunc())
+	}
+	assert.Same(t, locs[0], locs[1])
+	assert.Same(t, locs[0].Callee(), locs[1].Callee())
+}
+
+func TestGetLocation(t *testing.T) {
+	baseLoc := GetCallLocation(1)
+	loc := GetLocation(1)
+	assert.Equal(t, baseLoc.Callee().FileName(), loc.FileName())
+	assert.Equal(t, baseLoc.Callee().FileLine()+1, loc.FileLine())
+	assert.Equal(t, "TestGetLocation", loc.FuncName())
+
+	assert.Nil(t, GetLocation(1000000))
+}
+
+func lazyFunc() {}
+
+func TestGetFuncLocation_lazy(t *testing.T) {
+	// a fresh location rather than the cached one, which may be resolved by other runs
+	loc := &location{pc: reflect.ValueOf(lazyFunc).Pointer(), entry: true}
+	assert.Equal(t, "", loc.fileName)
+	assert.Equal(t, "lazyFunc", loc.FuncName())
+	assert.True(t, strings.HasSuffix(loc.fileName, "internal/location/location_test.go"))
+	assert.Equal(t, loc.FileName(), GetFuncLocation(lazyFunc).FileName())
+
+	assert.Nil(t, GetFuncLocation((func())(nil)))
+	assert.Nil(t, GetFuncLocation(1))
+}
+
+func TestSetEnabled(t *testing.T) {
+	SetEnabled(false)
+	assert.False(t, Enabled())
+	assert.Nil(t, GetCallLocation(0))
+	assert.Nil(t, GetLocation(0))
+	assert.NotNil(t, GetFuncLocation(baselineFunc))
+
+	SetEnabled(true)
+	assert.True(t, Enabled())
+	assert.NotNil(t, GetCallLocation(0))
+	assert.NotNil(t, GetLocation(0))
+}
+
+func BenchmarkGetCallLocation(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = GetCallLocation(0)
+	}
+}